@@ -1,30 +1,51 @@
 package main
 
 import (
+	"flag"
 	"fmt"
-	"log"
 	"os"
+
+	"gopkg.in/yaml.v3"
+
 	"translator-service/internal/config"
 )
 
+// test-config validates a config file (and optional profile overlay) and prints
+// the effective configuration, including environment overrides, with secrets masked.
 func main() {
-	// Set some environment variables for testing
-	os.Setenv("OPENAI_API_KEY", "test-openai-key")
-	os.Setenv("ANTHROPIC_API_KEY", "test-anthropic-key")
-	os.Setenv("PORT", "9090")
+	configFile := flag.String("config", "", "Path to config file")
+	profile := flag.String("profile", os.Getenv("CONFIG_PROFILE"), "Config profile overlaid on the config file")
+	printSchema := flag.Bool("schema", false, "Print the JSON Schema of the config file format and exit")
+	flag.Parse()
+
+	if *printSchema {
+		schema, err := config.JSONSchema()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to generate schema: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(schema))
+		return
+	}
 
-	// Load configuration
-	cfg, err := config.NewConfig()
+	files, err := config.ProfileFiles(*configFile, *profile)
 	if err != nil {
-		log.Fatalf("Failed to load configuration: %v", err)
+		fmt.Fprintf(os.Stderr, "Invalid configuration: %v\n", err)
+		os.Exit(1)
 	}
 
-	// Print configuration
-	fmt.Printf("Server Port: %s\n", cfg.ServerPort)
-	fmt.Printf("OpenAI Endpoint: %s\n", cfg.GetOpenAIEndpoint())
-	fmt.Printf("Has OpenAI Key: %t\n", cfg.HasOpenAIKey())
-	fmt.Printf("Anthropic Endpoint: %s\n", cfg.GetAnthropicEndpoint())
-	fmt.Printf("Has Anthropic Key: %t\n", cfg.HasAnthropicKey())
-	fmt.Printf("Timeout: %d\n", cfg.Timeout)
-	fmt.Printf("Debug: %t\n", cfg.Debug)
+	cfg, err := config.Load(files...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid configuration: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Print the effective configuration
+	fmt.Fprintf(os.Stderr, "Configuration is valid (files: %v)\n", files)
+	encoder := yaml.NewEncoder(os.Stdout)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(cfg.Effective()); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to print configuration: %v\n", err)
+		os.Exit(1)
+	}
 }
@@ -1,15 +1,32 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
 	"translator-service/internal/config"
 	"translator-service/internal/handlers"
+	"translator-service/internal/prompts"
 	"translator-service/internal/services"
+	"translator-service/internal/storage"
 )
 
+// configWatchInterval is how often the config file is checked for changes
+const configWatchInterval = 5 * time.Second
+
+// cancelledRequestWait is how long shutdown waits for cancelled requests to
+// return before the stores they write to are closed
+const cancelledRequestWait = 5 * time.Second
+
 func main() {
 	fmt.Println("Translation Service Starting...")
 
@@ -18,14 +35,74 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
+	if cfg.Debug {
+		log.Printf("Effective configuration: %s", cfg)
+	}
+
+	// Create history store
+	historyStore, err := storage.NewHistoryStore(cfg.HistoryStore, cfg.HistoryPath)
+	if err != nil {
+		log.Fatalf("Failed to create history store: %v", err)
+	}
+	defer historyStore.Close()
+
+	// Create vote store for model comparisons (shares the history storage settings)
+	voteStore, err := storage.NewVoteStore(cfg.HistoryStore, cfg.HistoryPath)
+	if err != nil {
+		log.Fatalf("Failed to create vote store: %v", err)
+	}
+	defer voteStore.Close()
+
+	// Create review store for human post-editing (shares the history storage settings)
+	var reviewStore storage.ReviewStore
+	if cfg.ReviewEnabled {
+		reviewStore, err = storage.NewReviewStore(cfg.HistoryStore, cfg.HistoryPath)
+		if err != nil {
+			log.Fatalf("Failed to create review store: %v", err)
+		}
+		defer reviewStore.Close()
+	}
+
+	// Create tenant store for tenants created through the admin API (shares the history storage settings)
+	tenantStore, err := storage.NewTenantStore(cfg.HistoryStore, cfg.HistoryPath)
+	if err != nil {
+		log.Fatalf("Failed to create tenant store: %v", err)
+	}
+	defer tenantStore.Close()
+
+	// Load prompt templates
+	promptStore, err := loadPrompts(cfg)
+	if err != nil {
+		log.Fatalf("Failed to load prompt templates: %v", err)
+	}
 
 	// Create translator service with configuration
 	translatorService := services.NewTranslatorService(cfg)
+	translatorService.SetHistoryStore(historyStore)
+	translatorService.SetVoteStore(voteStore)
+	translatorService.SetPromptStore(promptStore)
+	translatorService.SetTenantStore(tenantStore)
+	if reviewStore != nil {
+		translatorService.SetReviewStore(reviewStore)
+	}
 
 	// Create handlers with dependencies
 	homeHandler := handlers.NewHomeHandler(translatorService)
 	translateHandler := handlers.NewTranslateHandler(translatorService)
 	apiHandler := handlers.NewAPIHandler(translatorService)
+	historyAPIHandler := handlers.NewHistoryAPIHandler(historyStore)
+	historyPageHandler := handlers.NewHistoryPageHandler(historyStore)
+	compareAPIHandler := handlers.NewCompareAPIHandler(translatorService)
+	comparePageHandler := handlers.NewComparePageHandler(translatorService)
+	voteHandler := handlers.NewVoteHandler(voteStore)
+	sessionAPIHandler := handlers.NewSessionAPIHandler(translatorService)
+	reviewAPIHandler := handlers.NewReviewAPIHandler(translatorService)
+	reviewPageHandler := handlers.NewReviewPageHandler(translatorService)
+	tenantAdminHandler := handlers.NewTenantAdminHandler(translatorService)
+	adminStatusHandler := handlers.NewAdminStatusHandler(translatorService)
+	modelAdminHandler := handlers.NewModelAdminHandler(translatorService)
+	aliasAdminHandler := handlers.NewAliasAdminHandler(translatorService)
+	adminPageHandler := handlers.NewAdminPageHandler(translatorService)
 
 	// Create a new serve mux for routing
 	mux := http.NewServeMux()
@@ -34,11 +111,180 @@ func main() {
 	mux.HandleFunc("/", homeHandler)
 	mux.HandleFunc("/translate", translateHandler)
 	mux.HandleFunc("/api/translate", apiHandler)
+	mux.HandleFunc("/api/history", historyAPIHandler)
+	mux.HandleFunc("/history", historyPageHandler)
+	mux.HandleFunc("/api/translate/compare", compareAPIHandler)
+	mux.HandleFunc("/api/translate/compare/votes", voteHandler)
+	mux.HandleFunc("/compare", comparePageHandler)
+	mux.HandleFunc("/compare/vote", voteHandler)
+	mux.HandleFunc("/api/sessions", sessionAPIHandler)
+	mux.HandleFunc("/api/sessions/{id}", sessionAPIHandler)
+	mux.HandleFunc("/api/sessions/{id}/translate", apiHandler)
+	mux.HandleFunc("/api/reviews", reviewAPIHandler)
+	mux.HandleFunc("/api/reviews/{id}", reviewAPIHandler)
+	mux.HandleFunc("/review", reviewPageHandler)
+	mux.HandleFunc("/review/{id}", reviewPageHandler)
+	mux.HandleFunc("/api/admin/tenants", tenantAdminHandler)
+	mux.HandleFunc("/api/admin/tenants/{id}", tenantAdminHandler)
+	mux.HandleFunc("/api/admin/status", adminStatusHandler)
+	mux.HandleFunc("/api/admin/models", modelAdminHandler)
+	mux.HandleFunc("/api/admin/models/{model}", modelAdminHandler)
+	mux.HandleFunc("/api/admin/aliases", aliasAdminHandler)
+	mux.HandleFunc("/api/admin/aliases/{alias}", aliasAdminHandler)
+	mux.HandleFunc("/admin", adminPageHandler)
+	mux.HandleFunc("/admin/models/{model}", adminPageHandler)
+	mux.HandleFunc("/admin/aliases/{alias}", adminPageHandler)
 
 	// Serve static files
 	fs := http.FileServer(http.Dir("./web/static/"))
 	mux.Handle("/static/", http.StripPrefix("/static/", fs))
 
-	log.Printf("Server starting on port %s", cfg.ServerPort)
-	log.Fatal(http.ListenAndServe(":"+cfg.ServerPort, mux))
+	// Requests derive their context from this one so they can be cancelled
+	// once the shutdown grace period has elapsed
+	requestCtx, cancelRequests := context.WithCancel(context.Background())
+	defer cancelRequests()
+
+	// Count running handlers so the stores are not closed under them
+	var running sync.WaitGroup
+
+	server := &http.Server{
+		Addr:         ":" + cfg.ServerPort,
+		Handler:      trackRequests(&running, handlers.NewTenantMiddleware(translatorService, mux)),
+		ReadTimeout:  cfg.GetReadTimeout(),
+		WriteTimeout: cfg.GetWriteTimeout(),
+		IdleTimeout:  cfg.GetIdleTimeout(),
+		BaseContext: func(net.Listener) context.Context {
+			return requestCtx
+		},
+	}
+
+	// Start serving in the background
+	serverErrors := make(chan error, 1)
+	go func() {
+		log.Printf("Server starting on port %s", cfg.ServerPort)
+		serverErrors <- server.ListenAndServe()
+	}()
+
+	// Reload the configuration whenever one of its files changes
+	reloads := make(chan struct{}, 1)
+	watchCtx, stopWatching := context.WithCancel(context.Background())
+	defer stopWatching()
+	for _, configFile := range cfg.ConfigFiles() {
+		go config.WatchFile(watchCtx, configFile, configWatchInterval, func() {
+			select {
+			case reloads <- struct{}{}:
+			default:
+			}
+		})
+	}
+
+	// Wait for a shutdown signal or a server failure, reloading on SIGHUP
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+
+	for {
+		select {
+		case err := <-serverErrors:
+			if !errors.Is(err, http.ErrServerClosed) {
+				log.Fatalf("Server failed: %v", err)
+			}
+			return
+		case <-reloads:
+			reloadConfig(translatorService)
+		case sig := <-signals:
+			if sig == syscall.SIGHUP {
+				reloadConfig(translatorService)
+				continue
+			}
+			log.Printf("Received %s, shutting down (grace period %s)", sig, cfg.GetShutdownTimeout())
+			shutdown(server, cfg.GetShutdownTimeout(), cancelRequests, &running)
+			return
+		}
+	}
+}
+
+// reloadConfig reloads the config file and swaps the translator providers and prompt templates.
+// An invalid configuration is rejected and the current one stays in effect.
+func reloadConfig(translatorService *services.TranslatorService) {
+	current := translatorService.Config()
+
+	cfg, err := config.Load(current.ConfigFiles()...)
+	if err != nil {
+		log.Printf("Configuration reload failed, keeping current configuration: %v", err)
+		return
+	}
+
+	promptStore, err := loadPrompts(cfg)
+	if err != nil {
+		log.Printf("Configuration reload failed, keeping current configuration: %v", err)
+		return
+	}
+
+	if cfg.ServerPort != current.ServerPort || cfg.HistoryStore != current.HistoryStore || cfg.HistoryPath != current.HistoryPath ||
+		cfg.ReviewEnabled != current.ReviewEnabled {
+		log.Printf("Warning: server, history and review settings only take effect after a restart")
+	}
+
+	translatorService.Reload(cfg, promptStore)
+	log.Printf("Configuration reloaded")
+}
+
+// loadPrompts loads the prompt templates and checks that every template the
+// configuration refers to exists
+func loadPrompts(cfg *config.Config) (*prompts.Store, error) {
+	store, err := prompts.NewStore(cfg.PromptDir)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := store.Get(cfg.GetPromptTemplate("")); err != nil {
+		return nil, fmt.Errorf("default prompt: %w", err)
+	}
+	for model, ref := range cfg.PromptModels {
+		if _, err := store.Get(ref); err != nil {
+			return nil, fmt.Errorf("prompt for %s: %w", model, err)
+		}
+	}
+
+	return store, nil
+}
+
+// trackRequests counts the requests being handled in running
+func trackRequests(running *sync.WaitGroup, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		running.Add(1)
+		defer running.Done()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// shutdown stops accepting new requests and waits for in-flight requests to drain.
+// Requests still running after the grace period have their context cancelled,
+// which aborts any outstanding provider calls, and are given a short time to
+// return before the caller closes the stores.
+func shutdown(server *http.Server, gracePeriod time.Duration, cancelRequests context.CancelFunc, running *sync.WaitGroup) {
+	ctx, cancel := context.WithTimeout(context.Background(), gracePeriod)
+	defer cancel()
+
+	if err := server.Shutdown(ctx); err != nil {
+		log.Printf("Grace period elapsed, cancelling in-flight requests: %v", err)
+		cancelRequests()
+		if err := server.Close(); err != nil {
+			log.Printf("Error closing server: %v", err)
+		}
+
+		returned := make(chan struct{})
+		go func() {
+			running.Wait()
+			close(returned)
+		}()
+		select {
+		case <-returned:
+		case <-time.After(cancelledRequestWait):
+			log.Printf("Requests still running %s after cancellation, closing stores", cancelledRequestWait)
+		}
+		return
+	}
+
+	log.Printf("Server stopped gracefully")
 }
@@ -3,44 +3,246 @@ package config
 import (
 	"flag"
 	"fmt"
+	"net/url"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
-
-	"gopkg.in/yaml.v3"
+	"time"
 )
 
-// Config holds application configuration
+// Config holds application configuration. The config file layout is
+// described by FileConfig.
 type Config struct {
-	ServerPort        string `yaml:"port"`
-	OpenAIEndpoint    string `yaml:"openai_endpoint"`
-	OpenAIKey         string `yaml:"openai_key"`
-	AnthropicEndpoint string `yaml:"anthropic_endpoint"`
-	AnthropicKey      string `yaml:"anthropic_key"`
-	Debug             bool   `yaml:"debug"`
-	Timeout           int    `yaml:"timeout"`
+	ServerPort              string
+	OpenAIEndpoint          string
+	OpenAIKey               string
+	AnthropicEndpoint       string
+	AnthropicKey            string
+	Debug                   bool
+	Timeout                 int
+	HistoryStore            string
+	HistoryPath             string
+	ReadTimeout             int
+	WriteTimeout            int
+	IdleTimeout             int
+	ShutdownTimeout         int
+	LocalProvider           string
+	LocalEndpoint           string
+	LocalModel              string
+	AzureEndpoint           string
+	AzureKey                string
+	AzureAPIVersion         string
+	AzureDeployments        map[string]string
+	GeminiEndpoint          string
+	GeminiKey               string
+	GeminiModels            []string
+	DeepLEndpoint           string
+	DeepLKey                string
+	LibreTranslateEndpoint  string
+	LibreTranslateKey       string
+	PromptDir               string
+	PromptDefault           string
+	PromptModels            map[string]string
+	ModelAliases            map[string]map[string]int
+	RoutingModels           map[string]RoutingModelConfig
+	HedgingEnabled          bool
+	HedgingPercentile       float64
+	HedgingDelay            int
+	HedgingAlternates       map[string]string
+	HTTPMaxIdleConnsPerHost int
+	HTTPIdleConnTimeout     int
+	HTTPKeepAlive           int
+	HTTPDialTimeout         int
+	HTTPProxy               string
+	HTTPCABundle            string
+	HTTPTLSMinVersion       string
+	HTTPDisableHTTP2        bool
+	QualityEnabled          bool
+	QualityJudgeModel       string
+	QualityThreshold        float64
+	BackTranslationModel    string
+	PIIDefaultPolicy        string
+	PIIPolicies             map[string]string
+	MaxSessions             int
+	SessionMaxTurns         int
+	SessionTTL              int
+	ReviewEnabled           bool
+	// ReviewReviewers maps reviewer names to the keys that authenticate their review actions
+	ReviewReviewers map[string]string
+	Tenants         map[string]TenantConfig
+	AdminKey        string
+
+	configFiles []string
+}
+
+// TenantConfig holds the identity, credentials and defaults of a tenant and
+// restricts where the text of its requests may be sent
+type TenantConfig struct {
+	// APIKeys identify the tenant's requests
+	APIKeys []string
+	// AllowTenantHeader identifies the tenant's requests by the X-Tenant-ID header
+	// alone, without an API key. It cannot be combined with APIKeys.
+	AllowTenantHeader bool
+	// OpenAIKey and AnthropicKey replace the global provider keys for the tenant's requests
+	OpenAIKey    string
+	AnthropicKey string
+	// DefaultModel and TargetLanguage apply to requests that do not set them
+	DefaultModel   string
+	TargetLanguage string
+	// AllowedProviders lists the providers the tenant's text may be sent to; empty allows all
+	AllowedProviders []string
+	// AllowedEndpoints lists the base URLs the tenant's text may be sent to; empty allows all
+	AllowedEndpoints []string
+	// Disabled rejects all of the tenant's requests
+	Disabled bool
+}
+
+// RoutingModelConfig describes a model that requests with model auto may be routed to
+type RoutingModelConfig struct {
+	// Cost is the price of translating 1,000 characters, in any currency used for all models
+	Cost float64
+	// Languages lists the target languages the model is chosen for; empty allows all
+	Languages []string
+}
+
+// AutoModel is the model name that lets the service choose the model of a request
+const AutoModel = "auto"
+
+// ProviderNames lists the providers a tenant's text can be restricted to
+var ProviderNames = []string{"openai", "anthropic", "azure", "gemini", "deepl", "libretranslate", "ollama", "llamacpp"}
+
+// ValidateTenantRestrictions checks the providers and endpoints a tenant is restricted to
+func ValidateTenantRestrictions(providers, endpoints []string) error {
+	for _, provider := range providers {
+		known := false
+		for _, name := range ProviderNames {
+			known = known || provider == name
+		}
+		if !known {
+			return fmt.Errorf("unknown provider %s (must be one of: %s)", provider, strings.Join(ProviderNames, ", "))
+		}
+	}
+	for _, endpoint := range endpoints {
+		if !strings.HasPrefix(endpoint, "http") {
+			return fmt.Errorf("allowed endpoint %s must be a valid URL", endpoint)
+		}
+	}
+	return nil
+}
+
+// ValidateModelAlias checks an alias and the weights of the models it routes to
+func ValidateModelAlias(alias string, weights map[string]int) error {
+	if strings.TrimSpace(alias) == "" {
+		return fmt.Errorf("alias name cannot be empty")
+	}
+	if alias == AutoModel {
+		return fmt.Errorf("alias name %s is reserved", AutoModel)
+	}
+	if len(weights) == 0 {
+		return fmt.Errorf("alias %s must map at least one model", alias)
+	}
+
+	total := 0
+	for model, weight := range weights {
+		if strings.TrimSpace(model) == "" {
+			return fmt.Errorf("alias %s: model name cannot be empty", alias)
+		}
+		if weight < 0 {
+			return fmt.Errorf("alias %s: weight of %s cannot be negative", alias, model)
+		}
+		total += weight
+	}
+	if total == 0 {
+		return fmt.Errorf("alias %s: at least one weight must be positive", alias)
+	}
+	return nil
 }
 
 // NewConfig creates a new configuration from environment variables and config file
 func NewConfig() (*Config, error) {
 	// Parse command line flags
 	configFile := flag.String("config", "", "Path to config file")
+	profile := flag.String("profile", os.Getenv("CONFIG_PROFILE"), "Config profile overlaid on the config file (e.g. prod loads prod.yaml next to it)")
 	flag.Parse()
 
+	files, err := ProfileFiles(*configFile, *profile)
+	if err != nil {
+		return nil, err
+	}
+
+	return Load(files...)
+}
+
+// ProfileFiles returns the config files for a base file and an optional profile.
+// The profile file is named after the profile and lives next to the base file,
+// so base.yaml with profile "prod" yields base.yaml followed by prod.yaml.
+func ProfileFiles(baseFile, profile string) ([]string, error) {
+	if profile == "" {
+		if baseFile == "" {
+			return nil, nil
+		}
+		return []string{baseFile}, nil
+	}
+	if baseFile == "" {
+		return nil, fmt.Errorf("profile %s requires a base config file", profile)
+	}
+	if strings.ContainsAny(profile, `/\`) {
+		return nil, fmt.Errorf("invalid profile name: %s", profile)
+	}
+
+	profileFile := filepath.Join(filepath.Dir(baseFile), profile+".yaml")
+	if _, err := os.Stat(profileFile); err != nil {
+		return nil, fmt.Errorf("profile %s: %w", profile, err)
+	}
+
+	return []string{baseFile, profileFile}, nil
+}
+
+// Load builds a configuration from defaults, the given config files (each one
+// overlaid on the previous) and environment variables, and validates the result.
+// It can be called again to reload the configuration while the service is running.
+func Load(configFiles ...string) (*Config, error) {
 	// Create default config
 	config := &Config{
-		ServerPort:        "8080",
-		OpenAIEndpoint:    "https://api.openai.com/v1",
-		OpenAIKey:         "",
-		AnthropicEndpoint: "https://api.anthropic.com/v1",
-		AnthropicKey:      "",
-		Debug:             false,
-		Timeout:           30,
-	}
-
-	// Load from config file if specified
-	if *configFile != "" {
-		if err := config.loadFromFile(*configFile); err != nil {
+		ServerPort:              "8080",
+		OpenAIEndpoint:          "https://api.openai.com/v1",
+		OpenAIKey:               "",
+		AnthropicEndpoint:       "https://api.anthropic.com/v1",
+		AnthropicKey:            "",
+		Debug:                   false,
+		Timeout:                 30,
+		HistoryStore:            "memory",
+		HistoryPath:             "history.db",
+		ReadTimeout:             15,
+		WriteTimeout:            60,
+		IdleTimeout:             120,
+		ShutdownTimeout:         30,
+		LocalProvider:           "ollama",
+		LocalModel:              "llama3",
+		AzureAPIVersion:         "2024-02-01",
+		GeminiEndpoint:          "https://generativelanguage.googleapis.com/v1beta",
+		GeminiModels:            []string{"gemini-1.5-pro", "gemini-1.5-flash"},
+		DeepLEndpoint:           "https://api.deepl.com/v2",
+		PromptDefault:           "translate",
+		QualityThreshold:        0.6,
+		HedgingPercentile:       95,
+		HedgingDelay:            5000,
+		HTTPMaxIdleConnsPerHost: 10,
+		HTTPIdleConnTimeout:     90,
+		HTTPKeepAlive:           30,
+		HTTPDialTimeout:         10,
+		HTTPTLSMinVersion:       "1.2",
+		PIIDefaultPolicy:        "allow",
+		MaxSessions:             1000,
+		SessionMaxTurns:         10,
+		SessionTTL:              3600,
+		configFiles:             configFiles,
+	}
+
+	// Load from config files if specified
+	for _, configFile := range configFiles {
+		if err := config.loadFromFile(configFile); err != nil {
 			return nil, fmt.Errorf("failed to load config from file: %w", err)
 		}
 	}
@@ -48,6 +250,11 @@ func NewConfig() (*Config, error) {
 	// Override with environment variables
 	config.loadFromEnv()
 
+	// Resolve secret references such as file:/run/secrets/openai
+	if err := config.resolveSecrets(); err != nil {
+		return nil, fmt.Errorf("failed to resolve secrets: %w", err)
+	}
+
 	// Validate configuration
 	if err := config.validate(); err != nil {
 		return nil, fmt.Errorf("invalid configuration: %w", err)
@@ -56,53 +263,16 @@ func NewConfig() (*Config, error) {
 	return config, nil
 }
 
-// loadFromFile loads configuration from a YAML file
-func (c *Config) loadFromFile(filename string) error {
-	data, err := os.ReadFile(filename)
-	if err != nil {
-		return err
-	}
-
-	var fileConfig struct {
-		Server struct {
-			Port string `yaml:"port"`
-		} `yaml:"server"`
-		LLM struct {
-			OpenAIEndpoint    string `yaml:"openai_endpoint"`
-			OpenAIKey         string `yaml:"openai_key"`
-			AnthropicEndpoint string `yaml:"anthropic_endpoint"`
-			AnthropicKey      string `yaml:"anthropic_key"`
-			Timeout           int    `yaml:"timeout"`
-		} `yaml:"llm"`
-		Debug bool `yaml:"debug"`
-	}
-
-	if err := yaml.Unmarshal(data, &fileConfig); err != nil {
-		return err
-	}
-
-	// Apply file config
-	if fileConfig.Server.Port != "" {
-		c.ServerPort = fileConfig.Server.Port
-	}
-	if fileConfig.LLM.OpenAIEndpoint != "" {
-		c.OpenAIEndpoint = fileConfig.LLM.OpenAIEndpoint
-	}
-	if fileConfig.LLM.OpenAIKey != "" {
-		c.OpenAIKey = fileConfig.LLM.OpenAIKey
-	}
-	if fileConfig.LLM.AnthropicEndpoint != "" {
-		c.AnthropicEndpoint = fileConfig.LLM.AnthropicEndpoint
-	}
-	if fileConfig.LLM.AnthropicKey != "" {
-		c.AnthropicKey = fileConfig.LLM.AnthropicKey
-	}
-	if fileConfig.LLM.Timeout > 0 {
-		c.Timeout = fileConfig.LLM.Timeout
-	}
-	c.Debug = fileConfig.Debug
+// String returns a printable form of the configuration with secrets masked
+func (c Config) String() string {
+	return fmt.Sprintf("{port=%s openai_endpoint=%s openai_key=%s anthropic_endpoint=%s anthropic_key=%s timeout=%d debug=%t history_store=%s}",
+		c.ServerPort, c.OpenAIEndpoint, MaskSecret(c.OpenAIKey), c.AnthropicEndpoint, MaskSecret(c.AnthropicKey),
+		c.Timeout, c.Debug, c.HistoryStore)
+}
 
-	return nil
+// ConfigFiles returns the config files the configuration was loaded from, in overlay order
+func (c *Config) ConfigFiles() []string {
+	return c.configFiles
 }
 
 // loadFromEnv loads configuration from environment variables
@@ -132,6 +302,97 @@ func (c *Config) loadFromEnv() {
 			c.Timeout = intValue
 		}
 	}
+	if value := os.Getenv("READ_TIMEOUT"); value != "" {
+		if intValue, err := strconv.Atoi(value); err == nil {
+			c.ReadTimeout = intValue
+		}
+	}
+	if value := os.Getenv("WRITE_TIMEOUT"); value != "" {
+		if intValue, err := strconv.Atoi(value); err == nil {
+			c.WriteTimeout = intValue
+		}
+	}
+	if value := os.Getenv("IDLE_TIMEOUT"); value != "" {
+		if intValue, err := strconv.Atoi(value); err == nil {
+			c.IdleTimeout = intValue
+		}
+	}
+	if value := os.Getenv("SHUTDOWN_TIMEOUT"); value != "" {
+		if intValue, err := strconv.Atoi(value); err == nil {
+			c.ShutdownTimeout = intValue
+		}
+	}
+	if value := os.Getenv("LOCAL_PROVIDER"); value != "" {
+		c.LocalProvider = value
+	}
+	if value := os.Getenv("LOCAL_ENDPOINT"); value != "" {
+		c.LocalEndpoint = value
+	}
+	if value := os.Getenv("LOCAL_MODEL"); value != "" {
+		c.LocalModel = value
+	}
+	if value := os.Getenv("AZURE_OPENAI_ENDPOINT"); value != "" {
+		c.AzureEndpoint = value
+	}
+	if value := os.Getenv("AZURE_OPENAI_API_KEY"); value != "" {
+		c.AzureKey = value
+	}
+	if value := os.Getenv("AZURE_OPENAI_API_VERSION"); value != "" {
+		c.AzureAPIVersion = value
+	}
+	if value := os.Getenv("GEMINI_ENDPOINT"); value != "" {
+		c.GeminiEndpoint = value
+	}
+	if value := os.Getenv("GEMINI_API_KEY"); value != "" {
+		c.GeminiKey = value
+	}
+	if value := os.Getenv("DEEPL_ENDPOINT"); value != "" {
+		c.DeepLEndpoint = value
+	}
+	if value := os.Getenv("DEEPL_API_KEY"); value != "" {
+		c.DeepLKey = value
+	}
+	if value := os.Getenv("LIBRETRANSLATE_ENDPOINT"); value != "" {
+		c.LibreTranslateEndpoint = value
+	}
+	if value := os.Getenv("LIBRETRANSLATE_API_KEY"); value != "" {
+		c.LibreTranslateKey = value
+	}
+	if value := os.Getenv("PROMPT_DIR"); value != "" {
+		c.PromptDir = value
+	}
+	if value := os.Getenv("PII_POLICY"); value != "" {
+		c.PIIDefaultPolicy = value
+	}
+	if value := os.Getenv("MAX_SESSIONS"); value != "" {
+		if intValue, err := strconv.Atoi(value); err == nil {
+			c.MaxSessions = intValue
+		}
+	}
+	if value := os.Getenv("SESSION_MAX_TURNS"); value != "" {
+		if intValue, err := strconv.Atoi(value); err == nil {
+			c.SessionMaxTurns = intValue
+		}
+	}
+	if value := os.Getenv("SESSION_TTL"); value != "" {
+		if intValue, err := strconv.Atoi(value); err == nil {
+			c.SessionTTL = intValue
+		}
+	}
+	if value := os.Getenv("REVIEW_ENABLED"); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			c.ReviewEnabled = boolValue
+		}
+	}
+	if value := os.Getenv("ADMIN_API_KEY"); value != "" {
+		c.AdminKey = value
+	}
+	if value := os.Getenv("HISTORY_STORE"); value != "" {
+		c.HistoryStore = value
+	}
+	if value := os.Getenv("HISTORY_PATH"); value != "" {
+		c.HistoryPath = value
+	}
 }
 
 // validate checks that the configuration is valid
@@ -156,6 +417,157 @@ func (c *Config) validate() error {
 		return fmt.Errorf("timeout is too large (maximum 300 seconds)")
 	}
 
+	// Validate server timeouts (zero means the built-in default is used)
+	if c.ReadTimeout < 0 || c.WriteTimeout < 0 || c.IdleTimeout < 0 || c.ShutdownTimeout < 0 {
+		return fmt.Errorf("server timeouts cannot be negative")
+	}
+
+	// Validate the quality threshold, a score between 0 and 1
+	if c.QualityThreshold < 0 || c.QualityThreshold > 1 {
+		return fmt.Errorf("quality threshold must be between 0 and 1")
+	}
+
+	// Validate PII policies
+	if err := validatePIIPolicy(c.PIIDefaultPolicy); err != nil {
+		return fmt.Errorf("pii default policy: %w", err)
+	}
+	for piiType, policy := range c.PIIPolicies {
+		switch piiType {
+		case "email", "phone", "credit_card", "iban", "ip":
+		default:
+			return fmt.Errorf("pii policies: unknown type %s (must be one of: email, phone, credit_card, iban, ip)", piiType)
+		}
+		if err := validatePIIPolicy(policy); err != nil {
+			return fmt.Errorf("pii policy for %s: %w", piiType, err)
+		}
+	}
+
+	// Validate model aliases, whose models must not be aliases themselves
+	for alias, weights := range c.ModelAliases {
+		if err := ValidateModelAlias(alias, weights); err != nil {
+			return err
+		}
+		for model := range weights {
+			if _, ok := c.ModelAliases[model]; ok {
+				return fmt.Errorf("alias %s: model %s is an alias", alias, model)
+			}
+		}
+	}
+
+	// Validate the models requests with model auto may be routed to
+	for model, routing := range c.RoutingModels {
+		if strings.TrimSpace(model) == "" || model == AutoModel {
+			return fmt.Errorf("routing model names cannot be empty or %s", AutoModel)
+		}
+		if routing.Cost < 0 {
+			return fmt.Errorf("routing model %s: cost cannot be negative", model)
+		}
+		for _, language := range routing.Languages {
+			if language != "zh-Hans" && language != "zh-Hant" {
+				return fmt.Errorf("routing model %s: languages must be zh-Hans or zh-Hant", model)
+			}
+		}
+	}
+
+	// Validate request hedging
+	if c.HedgingEnabled {
+		if c.HedgingPercentile <= 0 || c.HedgingPercentile >= 100 {
+			return fmt.Errorf("hedging percentile must be greater than 0 and less than 100")
+		}
+		if c.HedgingDelay <= 0 {
+			return fmt.Errorf("hedging delay must be positive")
+		}
+	}
+	for model, alternate := range c.HedgingAlternates {
+		if strings.TrimSpace(model) == "" || strings.TrimSpace(alternate) == "" {
+			return fmt.Errorf("hedging alternates cannot have empty model names")
+		}
+	}
+
+	// Validate the HTTP transport shared by the providers (zero means the built-in default is used)
+	if c.HTTPMaxIdleConnsPerHost < 0 || c.HTTPIdleConnTimeout < 0 || c.HTTPKeepAlive < 0 || c.HTTPDialTimeout < 0 {
+		return fmt.Errorf("http connection settings cannot be negative")
+	}
+	if c.HTTPDialTimeout > c.Timeout {
+		return fmt.Errorf("http dial timeout (%ds) cannot exceed the timeout (%ds)", c.HTTPDialTimeout, c.Timeout)
+	}
+	if c.HTTPProxy != "" {
+		proxy, err := url.Parse(c.HTTPProxy)
+		if err != nil || proxy.Host == "" {
+			return fmt.Errorf("http proxy must be a valid URL")
+		}
+		switch proxy.Scheme {
+		case "http", "https", "socks5":
+		default:
+			return fmt.Errorf("http proxy scheme must be one of: http, https, socks5")
+		}
+	}
+	if _, err := TLSVersion(c.HTTPTLSMinVersion); err != nil {
+		return err
+	}
+	if c.HTTPCABundle != "" {
+		if _, err := LoadCABundle(c.HTTPCABundle); err != nil {
+			return err
+		}
+	}
+
+	// Validate reviewers; their keys identify them, so each must be unique
+	reviewerKeys := make(map[string]bool)
+	for name, key := range c.ReviewReviewers {
+		if strings.TrimSpace(name) == "" || len(name) > 100 || strings.ContainsAny(name, "\r\n") {
+			return fmt.Errorf("reviewer names must be a single line of at most 100 characters")
+		}
+		if key == "" {
+			return fmt.Errorf("reviewer %s has an empty key", name)
+		}
+		if reviewerKeys[key] || key == c.AdminKey {
+			return fmt.Errorf("reviewer %s has a key that is already in use", name)
+		}
+		reviewerKeys[key] = true
+	}
+
+	// Validate tenants
+	apiKeys := make(map[string]string)
+	for name, tenant := range c.Tenants {
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("tenant names cannot be empty")
+		}
+		if len(tenant.APIKeys) == 0 && !tenant.AllowTenantHeader {
+			return fmt.Errorf("tenant %s: set api_keys, or allow_tenant_header to identify it by X-Tenant-ID alone", name)
+		}
+		if len(tenant.APIKeys) > 0 && tenant.AllowTenantHeader {
+			return fmt.Errorf("tenant %s: allow_tenant_header cannot be combined with api_keys", name)
+		}
+		for _, key := range tenant.APIKeys {
+			if key == "" {
+				return fmt.Errorf("tenant %s: api keys cannot be empty", name)
+			}
+			if other, ok := apiKeys[key]; ok {
+				return fmt.Errorf("tenant %s: api key is also used by tenant %s", name, other)
+			}
+			apiKeys[key] = name
+		}
+		switch tenant.TargetLanguage {
+		case "", "zh-Hans", "zh-Hant":
+		default:
+			return fmt.Errorf("tenant %s: target language must be one of: zh-Hans, zh-Hant", name)
+		}
+		if err := ValidateTenantRestrictions(tenant.AllowedProviders, tenant.AllowedEndpoints); err != nil {
+			return fmt.Errorf("tenant %s: %w", name, err)
+		}
+	}
+
+	// Validate session limits (zero means the built-in default is used)
+	if c.MaxSessions < 0 || c.SessionMaxTurns < 0 || c.SessionTTL < 0 {
+		return fmt.Errorf("session limits cannot be negative")
+	}
+
+	// The write timeout bounds the whole response, so it must leave room for the
+	// translation; GetRequestTimeout keeps a margin for writing the response
+	if c.WriteTimeout > 0 && c.WriteTimeout <= c.Timeout {
+		return fmt.Errorf("write_timeout must be greater than timeout")
+	}
+
 	// Validate endpoints
 	if c.OpenAIEndpoint != "" && !strings.HasPrefix(c.OpenAIEndpoint, "http") {
 		return fmt.Errorf("openai_endpoint must be a valid URL")
@@ -164,6 +576,31 @@ func (c *Config) validate() error {
 		return fmt.Errorf("anthropic_endpoint must be a valid URL")
 	}
 
+	if c.LocalEndpoint != "" && !strings.HasPrefix(c.LocalEndpoint, "http") {
+		return fmt.Errorf("local endpoint must be a valid URL")
+	}
+
+	if c.AzureEndpoint != "" && !strings.HasPrefix(c.AzureEndpoint, "http") {
+		return fmt.Errorf("azure endpoint must be a valid URL")
+	}
+	if c.GeminiEndpoint != "" && !strings.HasPrefix(c.GeminiEndpoint, "http") {
+		return fmt.Errorf("gemini endpoint must be a valid URL")
+	}
+
+	if c.DeepLEndpoint != "" && !strings.HasPrefix(c.DeepLEndpoint, "http") {
+		return fmt.Errorf("deepl endpoint must be a valid URL")
+	}
+	if c.LibreTranslateEndpoint != "" && !strings.HasPrefix(c.LibreTranslateEndpoint, "http") {
+		return fmt.Errorf("libretranslate endpoint must be a valid URL")
+	}
+
+	// Validate local model provider
+	switch c.LocalProvider {
+	case "", "ollama", "llamacpp":
+	default:
+		return fmt.Errorf("local provider must be one of: ollama, llamacpp")
+	}
+
 	// Validate that if API keys are provided, endpoints are also provided
 	if c.OpenAIKey != "" && c.OpenAIEndpoint == "" {
 		return fmt.Errorf("openai_endpoint must be provided when openai_key is set")
@@ -171,6 +608,29 @@ func (c *Config) validate() error {
 	if c.AnthropicKey != "" && c.AnthropicEndpoint == "" {
 		return fmt.Errorf("anthropic_endpoint must be provided when anthropic_key is set")
 	}
+	if c.AzureKey != "" && c.AzureEndpoint == "" {
+		return fmt.Errorf("azure endpoint must be provided when azure key is set")
+	}
+	if c.AzureKey != "" && len(c.AzureDeployments) == 0 {
+		return fmt.Errorf("azure deployments must map at least one model when azure key is set")
+	}
+	if c.GeminiKey != "" && c.GeminiEndpoint == "" {
+		return fmt.Errorf("gemini endpoint must be provided when gemini key is set")
+	}
+	if c.DeepLKey != "" && c.DeepLEndpoint == "" {
+		return fmt.Errorf("deepl endpoint must be provided when deepl key is set")
+	}
+
+	// Validate history store
+	switch c.HistoryStore {
+	case "", "memory":
+	case "sqlite":
+		if c.HistoryPath == "" {
+			return fmt.Errorf("history_path must be provided when history_store is sqlite")
+		}
+	default:
+		return fmt.Errorf("history_store must be one of: memory, sqlite")
+	}
 
 	return nil
 }
@@ -195,6 +655,145 @@ func (c *Config) GetAnthropicKey() string {
 	return c.AnthropicKey
 }
 
+// HasAzureKey returns true if an Azure OpenAI API key is configured
+func (c *Config) HasAzureKey() bool {
+	return c.AzureKey != ""
+}
+
+// HasGeminiKey returns true if a Google Gemini API key is configured
+func (c *Config) HasGeminiKey() bool {
+	return c.GeminiKey != ""
+}
+
+// HasDeepLKey returns true if a DeepL API key is configured
+func (c *Config) HasDeepLKey() bool {
+	return c.DeepLKey != ""
+}
+
+// HasLibreTranslateEndpoint returns true if a LibreTranslate server is configured
+func (c *Config) HasLibreTranslateEndpoint() bool {
+	return c.LibreTranslateEndpoint != ""
+}
+
+// HasLocalEndpoint returns true if a local Ollama or llama.cpp server is configured
+func (c *Config) HasLocalEndpoint() bool {
+	return c.LocalEndpoint != ""
+}
+
+// GetLocalProvider returns the local model server type, defaulting to Ollama
+func (c *Config) GetLocalProvider() string {
+	if c.LocalProvider == "" {
+		return "ollama"
+	}
+	return c.LocalProvider
+}
+
+// GetPromptTemplate returns the prompt template reference used for a model:
+// the model's entry in PromptModels, or PromptDefault
+func (c *Config) GetPromptTemplate(model string) string {
+	if ref, ok := c.PromptModels[model]; ok {
+		return ref
+	}
+	if c.PromptDefault == "" {
+		return "translate"
+	}
+	return c.PromptDefault
+}
+
+// GetPIIPolicy returns the policy applied to personal data of a type: the
+// type's entry in PIIPolicies, or PIIDefaultPolicy
+func (c *Config) GetPIIPolicy(piiType string) string {
+	if policy, ok := c.PIIPolicies[piiType]; ok {
+		return policy
+	}
+	if c.PIIDefaultPolicy == "" {
+		return "allow"
+	}
+	return c.PIIDefaultPolicy
+}
+
+// GetReadTimeout returns the HTTP server read timeout
+func (c *Config) GetReadTimeout() time.Duration {
+	return secondsOrDefault(c.ReadTimeout, 15)
+}
+
+// GetWriteTimeout returns the HTTP server write timeout
+func (c *Config) GetWriteTimeout() time.Duration {
+	return secondsOrDefault(c.WriteTimeout, 60)
+}
+
+// GetIdleTimeout returns the HTTP server keep-alive idle timeout
+func (c *Config) GetIdleTimeout() time.Duration {
+	return secondsOrDefault(c.IdleTimeout, 120)
+}
+
+// GetShutdownTimeout returns how long in-flight requests may drain during shutdown
+func (c *Config) GetShutdownTimeout() time.Duration {
+	return secondsOrDefault(c.ShutdownTimeout, 30)
+}
+
+// GetTimeout returns how long a translation may take, including every call to
+// a provider it makes
+func (c *Config) GetTimeout() time.Duration {
+	return secondsOrDefault(c.Timeout, 30)
+}
+
+// writeMargin is the time left after a request's translation to write its response
+const writeMargin = 2 * time.Second
+
+// GetRequestTimeout returns how long the translation of an HTTP request may
+// take: the timeout, cut short where needed so that the response can still be
+// written within the write timeout
+func (c *Config) GetRequestTimeout() time.Duration {
+	return max(min(c.GetTimeout(), c.GetWriteTimeout()-writeMargin), time.Second)
+}
+
+// GetHTTPMaxIdleConnsPerHost returns how many idle connections to each provider host are kept open
+func (c *Config) GetHTTPMaxIdleConnsPerHost() int {
+	if c.HTTPMaxIdleConnsPerHost <= 0 {
+		return 10
+	}
+	return c.HTTPMaxIdleConnsPerHost
+}
+
+// GetHTTPIdleConnTimeout returns how long an idle connection to a provider is kept open
+func (c *Config) GetHTTPIdleConnTimeout() time.Duration {
+	return secondsOrDefault(c.HTTPIdleConnTimeout, 90)
+}
+
+// GetHTTPDialTimeout returns how long connecting to a provider may take
+func (c *Config) GetHTTPDialTimeout() time.Duration {
+	return secondsOrDefault(c.HTTPDialTimeout, 10)
+}
+
+// GetHTTPKeepAlive returns the interval of TCP keep-alive probes on connections to providers
+func (c *Config) GetHTTPKeepAlive() time.Duration {
+	return secondsOrDefault(c.HTTPKeepAlive, 30)
+}
+
+// GetSessionTTL returns how long a translation session is kept without use
+func (c *Config) GetSessionTTL() time.Duration {
+	return secondsOrDefault(c.SessionTTL, 3600)
+}
+
+// validatePIIPolicy checks that a PII policy is known; empty means the default
+func validatePIIPolicy(policy string) error {
+	switch policy {
+	case "", "allow", "mask", "block":
+		return nil
+	default:
+		return fmt.Errorf("policy must be one of: allow, mask, block")
+	}
+}
+
+// secondsOrDefault converts a number of seconds to a duration, using the default when unset
+func secondsOrDefault(seconds, defaultSeconds int) time.Duration {
+	if seconds <= 0 {
+		seconds = defaultSeconds
+	}
+	return time.Duration(seconds) * time.Second
+}
+
 // GetOpenAIEndpoint returns the OpenAI endpoint, or the default if not configured
 func (c *Config) GetOpenAIEndpoint() string {
 	if c.OpenAIEndpoint == "" {
@@ -0,0 +1,586 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileConfig is the schema of a YAML config file. Every field is optional: an
+// unset field keeps its default or the value set by an earlier file.
+type FileConfig struct {
+	Server   *ServerFileConfig            `yaml:"server,omitempty" doc:"HTTP server settings"`
+	LLM      *LLMFileConfig               `yaml:"llm,omitempty" doc:"Translation provider settings"`
+	History  *HistoryFileConfig           `yaml:"history,omitempty" doc:"Translation history storage"`
+	Sessions *SessionsFileConfig          `yaml:"sessions,omitempty" doc:"Multi-turn translation session limits"`
+	Review   *ReviewFileConfig            `yaml:"review,omitempty" doc:"Human post-editing of machine translations"`
+	Tenants  map[string]*TenantFileConfig `yaml:"tenants,omitempty" doc:"Tenants, identified by an API key or the X-Tenant-ID header, with their credentials, defaults and where their text may be sent"`
+	Admin    *AdminFileConfig             `yaml:"admin,omitempty" doc:"Admin dashboard and API for operating the service and managing tenants"`
+	Debug    *bool                        `yaml:"debug,omitempty" doc:"Enable debug logging"`
+}
+
+// ServerFileConfig holds the HTTP server section of a config file
+type ServerFileConfig struct {
+	Port            *string `yaml:"port,omitempty" doc:"Port to listen on (default 8080)"`
+	ReadTimeout     *int    `yaml:"read_timeout,omitempty" doc:"Seconds allowed to read a request (default 15)"`
+	WriteTimeout    *int    `yaml:"write_timeout,omitempty" doc:"Seconds allowed to write a response; must exceed llm.timeout (default 60)"`
+	IdleTimeout     *int    `yaml:"idle_timeout,omitempty" doc:"Seconds to keep idle keep-alive connections open (default 120)"`
+	ShutdownTimeout *int    `yaml:"shutdown_timeout,omitempty" doc:"Seconds to drain in-flight requests on shutdown (default 30)"`
+}
+
+// LLMFileConfig holds the translation provider section of a config file
+type LLMFileConfig struct {
+	OpenAIEndpoint    *string                   `yaml:"openai_endpoint,omitempty" doc:"Base URL of the OpenAI-compatible API"`
+	OpenAIKey         *string                   `yaml:"openai_key,omitempty" secret:"true" doc:"OpenAI API key or a file:, env: or exec: reference"`
+	AnthropicEndpoint *string                   `yaml:"anthropic_endpoint,omitempty" doc:"Base URL of the Anthropic API"`
+	AnthropicKey      *string                   `yaml:"anthropic_key,omitempty" secret:"true" doc:"Anthropic API key or a file:, env: or exec: reference"`
+	Timeout           *int                      `yaml:"timeout,omitempty" doc:"Seconds allowed for a translation (1-300, default 30)"`
+	Local             *LocalFileConfig          `yaml:"local,omitempty" doc:"Local model server used for the llama model"`
+	Azure             *AzureFileConfig          `yaml:"azure,omitempty" doc:"Azure OpenAI deployments"`
+	Gemini            *GeminiFileConfig         `yaml:"gemini,omitempty" doc:"Google Gemini models"`
+	DeepL             *DeepLFileConfig          `yaml:"deepl,omitempty" doc:"DeepL-compatible machine translation API, registered as the deepl model"`
+	LibreTranslate    *LibreTranslateFileConfig `yaml:"libretranslate,omitempty" doc:"LibreTranslate-compatible server, registered as the libretranslate model"`
+	Prompts           *PromptsFileConfig        `yaml:"prompts,omitempty" doc:"Prompt templates used by the LLM providers"`
+	Quality           *QualityFileConfig        `yaml:"quality,omitempty" doc:"Automatic translation quality estimation"`
+	PII               *PIIFileConfig            `yaml:"pii,omitempty" doc:"Handling of personal data before text is sent to a provider"`
+	Aliases           map[string]map[string]int `yaml:"aliases,omitempty" doc:"Model aliases such as quality or fast, each mapping models to their share of the alias's requests, e.g. {gpt-4: 90, gpt-4o: 10}"`
+	Routing           *RoutingFileConfig        `yaml:"routing,omitempty" doc:"Choice of the model for requests with model auto"`
+	Hedging           *HedgingFileConfig        `yaml:"hedging,omitempty" doc:"Second requests sent when a provider is slow to answer"`
+	HTTP              *HTTPFileConfig           `yaml:"http,omitempty" doc:"HTTP transport shared by all providers"`
+}
+
+// AzureFileConfig holds the Azure OpenAI section of a config file
+type AzureFileConfig struct {
+	Endpoint    *string           `yaml:"endpoint,omitempty" doc:"Azure OpenAI resource URL, e.g. https://my-resource.openai.azure.com"`
+	Key         *string           `yaml:"key,omitempty" secret:"true" doc:"Azure OpenAI API key or a file:, env: or exec: reference"`
+	APIVersion  *string           `yaml:"api_version,omitempty" doc:"Azure OpenAI API version (default 2024-02-01)"`
+	Deployments map[string]string `yaml:"deployments,omitempty" doc:"Model names mapped to Azure deployment names"`
+}
+
+// GeminiFileConfig holds the Google Gemini section of a config file
+type GeminiFileConfig struct {
+	Endpoint *string  `yaml:"endpoint,omitempty" doc:"Gemini API base URL (default https://generativelanguage.googleapis.com/v1beta)"`
+	Key      *string  `yaml:"key,omitempty" secret:"true" doc:"Gemini API key or a file:, env: or exec: reference"`
+	Models   []string `yaml:"models,omitempty" doc:"Gemini models to register (default gemini-1.5-pro, gemini-1.5-flash)"`
+}
+
+// DeepLFileConfig holds the DeepL section of a config file
+type DeepLFileConfig struct {
+	Endpoint *string `yaml:"endpoint,omitempty" doc:"DeepL API base URL (default https://api.deepl.com/v2; free accounts use https://api-free.deepl.com/v2)"`
+	Key      *string `yaml:"key,omitempty" secret:"true" doc:"DeepL API key or a file:, env: or exec: reference"`
+}
+
+// LibreTranslateFileConfig holds the LibreTranslate section of a config file
+type LibreTranslateFileConfig struct {
+	Endpoint *string `yaml:"endpoint,omitempty" doc:"Base URL of the LibreTranslate server; unset disables the libretranslate model"`
+	Key      *string `yaml:"key,omitempty" secret:"true" doc:"Optional LibreTranslate API key or a file:, env: or exec: reference"`
+}
+
+// RoutingFileConfig holds the automatic model selection section of a config file
+type RoutingFileConfig struct {
+	Models map[string]*RoutingModelFileConfig `yaml:"models,omitempty" doc:"Models that requests with model auto may be routed to; unset allows every registered model at equal cost"`
+}
+
+// RoutingModelFileConfig holds the routing settings of one model in a config file
+type RoutingModelFileConfig struct {
+	Cost      float64  `yaml:"cost,omitempty" doc:"Price of translating 1,000 characters, in any currency used for all models"`
+	Languages []string `yaml:"languages,omitempty" doc:"Target languages (zh-Hans, zh-Hant) the model is chosen for; unset allows both"`
+}
+
+// HedgingFileConfig holds the request hedging section of a config file
+type HedgingFileConfig struct {
+	Enabled    *bool             `yaml:"enabled,omitempty" doc:"Send a second request when a provider has not answered within the model's usual latency and use whichever answers first (default false)"`
+	Percentile *float64          `yaml:"percentile,omitempty" doc:"Latency percentile of the model's recent provider calls after which the second request is sent (default 95)"`
+	DelayMs    *int              `yaml:"delay_ms,omitempty" doc:"Milliseconds after which the second request is sent until the model has 20 successful requests (default 5000)"`
+	Alternates map[string]string `yaml:"alternates,omitempty" doc:"Model names mapped to the model their second requests go to; unset sends them to the same model"`
+}
+
+// HTTPFileConfig holds the provider HTTP transport section of a config file
+type HTTPFileConfig struct {
+	MaxIdleConnsPerHost *int    `yaml:"max_idle_conns_per_host,omitempty" doc:"Idle connections kept open to each provider host (default 10)"`
+	IdleConnTimeout     *int    `yaml:"idle_conn_timeout,omitempty" doc:"Seconds an idle connection to a provider is kept open (default 90)"`
+	KeepAlive           *int    `yaml:"keep_alive,omitempty" doc:"Seconds between TCP keep-alive probes on provider connections (default 30)"`
+	DialTimeout         *int    `yaml:"dial_timeout,omitempty" doc:"Seconds a connection to a provider may take to open, at most llm.timeout (default 10)"`
+	Proxy               *string `yaml:"proxy,omitempty" doc:"URL of the HTTP, HTTPS or SOCKS5 proxy for provider calls; unset uses HTTPS_PROXY, HTTP_PROXY and NO_PROXY"`
+	CABundle            *string `yaml:"ca_bundle,omitempty" doc:"PEM file of CA certificates trusted in addition to the system ones"`
+	TLSMinVersion       *string `yaml:"tls_min_version,omitempty" doc:"Minimum TLS version of provider connections, 1.2 or 1.3 (default 1.2)"`
+	DisableHTTP2        *bool   `yaml:"disable_http2,omitempty" doc:"Use HTTP/1.1 only instead of negotiating HTTP/2 (default false)"`
+}
+
+// PromptsFileConfig holds the prompt template section of a config file
+type PromptsFileConfig struct {
+	Dir     *string           `yaml:"dir,omitempty" doc:"Directory of prompt templates laid out as <name>/v<N>.tmpl, added to the built-in ones"`
+	Default *string           `yaml:"default,omitempty" doc:"Template used when neither the request nor the model selects one, as name or name@version (default translate)"`
+	Models  map[string]string `yaml:"models,omitempty" doc:"Model names mapped to the template they use, as name or name@version"`
+}
+
+// QualityFileConfig holds the quality estimation section of a config file
+type QualityFileConfig struct {
+	Enabled              *bool    `yaml:"enabled,omitempty" doc:"Estimate the quality of every translation; requests can also ask with quality: true"`
+	JudgeModel           *string  `yaml:"judge_model,omitempty" doc:"Model that reviews translations in addition to the heuristic checks; unset disables the review"`
+	Threshold            *float64 `yaml:"threshold,omitempty" doc:"Translations scoring below this are flagged (0-1, default 0.6)"`
+	BackTranslationModel *string  `yaml:"back_translation_model,omitempty" doc:"Model that translates results back to English for verify: back_translation; defaults to the request's model"`
+}
+
+// PIIFileConfig holds the personal data section of a config file
+type PIIFileConfig struct {
+	Default  *string           `yaml:"default,omitempty" enum:"allow,mask,block" doc:"Policy for personal data types without their own policy (default allow)"`
+	Policies map[string]string `yaml:"policies,omitempty" doc:"Personal data types (email, phone, credit_card, iban, ip) mapped to allow, mask or block"`
+}
+
+// LocalFileConfig holds the local model server section of a config file
+type LocalFileConfig struct {
+	Provider *string `yaml:"provider,omitempty" enum:"ollama,llamacpp" doc:"Local model server type (default ollama)"`
+	Endpoint *string `yaml:"endpoint,omitempty" doc:"Base URL of the local server, e.g. http://localhost:11434; unset uses a mock"`
+	Model    *string `yaml:"model,omitempty" doc:"Ollama model tag, e.g. llama3:8b (default llama3)"`
+}
+
+// HistoryFileConfig holds the history storage section of a config file
+type HistoryFileConfig struct {
+	Store *string `yaml:"store,omitempty" enum:"memory,sqlite" doc:"Where translations and votes are stored (default memory)"`
+	Path  *string `yaml:"path,omitempty" doc:"SQLite database file (default history.db)"`
+}
+
+// SessionsFileConfig holds the translation session section of a config file
+type SessionsFileConfig struct {
+	MaxSessions *int `yaml:"max_sessions,omitempty" doc:"Sessions kept in memory; the least recently used is evicted beyond this (default 1000)"`
+	MaxTurns    *int `yaml:"max_turns,omitempty" doc:"Earlier translations kept per session and sent with each request (default 10)"`
+	TTL         *int `yaml:"ttl,omitempty" doc:"Seconds a session is kept without use (default 3600)"`
+}
+
+// ReviewFileConfig holds the review workflow section of a config file
+type ReviewFileConfig struct {
+	Enabled   *bool             `yaml:"enabled,omitempty" doc:"Queue translations for human review and reuse approved ones; stored like the history (default false)"`
+	Reviewers map[string]string `yaml:"reviewers,omitempty" secret:"true" doc:"Reviewer names mapped to the keys that authenticate their review actions, or file:, env: or exec: references; admin.key is accepted as the reviewer admin"`
+}
+
+// TenantFileConfig holds the settings of one tenant in a config file
+type TenantFileConfig struct {
+	APIKeys           []string `yaml:"api_keys,omitempty" secret:"true" doc:"Keys sent as a bearer token or in X-API-Key to identify the tenant"`
+	AllowTenantHeader bool     `yaml:"allow_tenant_header,omitempty" doc:"Identify the tenant by the X-Tenant-ID header alone, without an API key; only for trusted networks, and not together with api_keys"`
+	OpenAIKey         string   `yaml:"openai_key,omitempty" secret:"true" doc:"OpenAI API key used instead of llm.openai_key for the tenant's requests"`
+	AnthropicKey      string   `yaml:"anthropic_key,omitempty" secret:"true" doc:"Anthropic API key used instead of llm.anthropic_key for the tenant's requests"`
+	DefaultModel      string   `yaml:"default_model,omitempty" doc:"Model used for the tenant's requests that do not name one"`
+	TargetLanguage    string   `yaml:"target_language,omitempty" enum:"zh-Hans,zh-Hant" doc:"Chinese script used for the tenant's requests that do not name one (default zh-Hans)"`
+	AllowedProviders  []string `yaml:"allowed_providers,omitempty" doc:"Providers the tenant's text may be sent to (openai, anthropic, azure, gemini, deepl, libretranslate, ollama, llamacpp); unset allows all"`
+	AllowedEndpoints  []string `yaml:"allowed_endpoints,omitempty" doc:"Base URLs the tenant's text may be sent to, e.g. https://my-eu-resource.openai.azure.com; unset allows all"`
+	Disabled          bool     `yaml:"disabled,omitempty" doc:"Reject all of the tenant's requests"`
+}
+
+// AdminFileConfig holds the admin API section of a config file
+type AdminFileConfig struct {
+	Key *string `yaml:"key,omitempty" secret:"true" doc:"Key for the /admin dashboard and admin API, sent in the X-Admin-Key header or as the basic authentication password; unset disables them"`
+}
+
+// interpolationPattern matches ${NAME} and ${NAME:-default}
+var interpolationPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// loadFromFile overlays the settings of a YAML config file onto the configuration
+func (c *Config) loadFromFile(filename string) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	// Refuse to use plaintext keys that other users can read. Values that do not
+	// fit their field are skipped here and reported when the file is decoded.
+	var raw FileConfig
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		var typeErr *yaml.TypeError
+		if !errors.As(err, &typeErr) {
+			return fmt.Errorf("%s: %w", filename, err)
+		}
+	}
+	var secrets []string
+	replaceSecrets(reflect.ValueOf(&raw), func(secret string) string {
+		secrets = append(secrets, secret)
+		return secret
+	})
+	if err := checkFilePermissions(filename, secrets...); err != nil {
+		return err
+	}
+
+	data, err = interpolate(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", filename, err)
+	}
+
+	fileConfig, err := decodeFileConfig(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", filename, err)
+	}
+
+	c.apply(fileConfig)
+	return nil
+}
+
+// decodeFileConfig strictly decodes a config file, rejecting unknown keys
+func decodeFileConfig(data []byte) (*FileConfig, error) {
+	var fileConfig FileConfig
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&fileConfig); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	return &fileConfig, nil
+}
+
+// interpolate replaces ${NAME} and ${NAME:-default} with environment variable
+// values. The file is parsed first and only the values of scalars are replaced,
+// so comments are left alone and a value cannot add YAML structure. Referencing
+// an unset variable without a default is an error.
+func interpolate(data []byte) ([]byte, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+	if root.Kind == 0 {
+		// An empty file
+		return data, nil
+	}
+
+	var missing []string
+	interpolateNode(&root, &missing)
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("unset environment variables: %s", strings.Join(missing, ", "))
+	}
+
+	return yaml.Marshal(&root)
+}
+
+// interpolateNode replaces the variables in the scalar values below a node,
+// collecting the names of unset variables in missing. Mapping keys are kept.
+func interpolateNode(node *yaml.Node, missing *[]string) {
+	switch node.Kind {
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, child := range node.Content {
+			interpolateNode(child, missing)
+		}
+	case yaml.MappingNode:
+		for i := 1; i < len(node.Content); i += 2 {
+			interpolateNode(node.Content[i], missing)
+		}
+	case yaml.ScalarNode:
+		value := interpolationPattern.ReplaceAllStringFunc(node.Value, func(match string) string {
+			groups := interpolationPattern.FindStringSubmatch(match)
+			if value, ok := os.LookupEnv(groups[1]); ok && value != "" {
+				return value
+			}
+			if groups[2] != "" {
+				return groups[3]
+			}
+			*missing = append(*missing, groups[1])
+			return match
+		})
+		if value != node.Value {
+			node.Value = value
+			if node.Style == 0 {
+				// An unquoted value takes the type of what it was replaced with,
+				// so timeout: ${TIMEOUT:-30} stays a number
+				node.Tag = ""
+			}
+		}
+	}
+}
+
+// apply overlays the fields set in a config file onto the configuration
+func (c *Config) apply(fc *FileConfig) {
+	if fc.Server != nil {
+		setString(&c.ServerPort, fc.Server.Port)
+		setInt(&c.ReadTimeout, fc.Server.ReadTimeout)
+		setInt(&c.WriteTimeout, fc.Server.WriteTimeout)
+		setInt(&c.IdleTimeout, fc.Server.IdleTimeout)
+		setInt(&c.ShutdownTimeout, fc.Server.ShutdownTimeout)
+	}
+	if fc.LLM != nil {
+		setString(&c.OpenAIEndpoint, fc.LLM.OpenAIEndpoint)
+		setString(&c.OpenAIKey, fc.LLM.OpenAIKey)
+		setString(&c.AnthropicEndpoint, fc.LLM.AnthropicEndpoint)
+		setString(&c.AnthropicKey, fc.LLM.AnthropicKey)
+		setInt(&c.Timeout, fc.LLM.Timeout)
+		if fc.LLM.Local != nil {
+			setString(&c.LocalProvider, fc.LLM.Local.Provider)
+			setString(&c.LocalEndpoint, fc.LLM.Local.Endpoint)
+			setString(&c.LocalModel, fc.LLM.Local.Model)
+		}
+		if fc.LLM.Azure != nil {
+			setString(&c.AzureEndpoint, fc.LLM.Azure.Endpoint)
+			setString(&c.AzureKey, fc.LLM.Azure.Key)
+			setString(&c.AzureAPIVersion, fc.LLM.Azure.APIVersion)
+			if fc.LLM.Azure.Deployments != nil {
+				c.AzureDeployments = fc.LLM.Azure.Deployments
+			}
+		}
+		if fc.LLM.Gemini != nil {
+			setString(&c.GeminiEndpoint, fc.LLM.Gemini.Endpoint)
+			setString(&c.GeminiKey, fc.LLM.Gemini.Key)
+			if fc.LLM.Gemini.Models != nil {
+				c.GeminiModels = fc.LLM.Gemini.Models
+			}
+		}
+		if fc.LLM.DeepL != nil {
+			setString(&c.DeepLEndpoint, fc.LLM.DeepL.Endpoint)
+			setString(&c.DeepLKey, fc.LLM.DeepL.Key)
+		}
+		if fc.LLM.LibreTranslate != nil {
+			setString(&c.LibreTranslateEndpoint, fc.LLM.LibreTranslate.Endpoint)
+			setString(&c.LibreTranslateKey, fc.LLM.LibreTranslate.Key)
+		}
+		if fc.LLM.Aliases != nil {
+			c.ModelAliases = fc.LLM.Aliases
+		}
+		if fc.LLM.Routing != nil && fc.LLM.Routing.Models != nil {
+			c.RoutingModels = make(map[string]RoutingModelConfig, len(fc.LLM.Routing.Models))
+			for model, routing := range fc.LLM.Routing.Models {
+				if routing == nil {
+					routing = &RoutingModelFileConfig{}
+				}
+				c.RoutingModels[model] = RoutingModelConfig{Cost: routing.Cost, Languages: routing.Languages}
+			}
+		}
+		if fc.LLM.Hedging != nil {
+			if fc.LLM.Hedging.Enabled != nil {
+				c.HedgingEnabled = *fc.LLM.Hedging.Enabled
+			}
+			if fc.LLM.Hedging.Percentile != nil {
+				c.HedgingPercentile = *fc.LLM.Hedging.Percentile
+			}
+			setInt(&c.HedgingDelay, fc.LLM.Hedging.DelayMs)
+			if fc.LLM.Hedging.Alternates != nil {
+				c.HedgingAlternates = fc.LLM.Hedging.Alternates
+			}
+		}
+		if fc.LLM.HTTP != nil {
+			setInt(&c.HTTPMaxIdleConnsPerHost, fc.LLM.HTTP.MaxIdleConnsPerHost)
+			setInt(&c.HTTPIdleConnTimeout, fc.LLM.HTTP.IdleConnTimeout)
+			setInt(&c.HTTPKeepAlive, fc.LLM.HTTP.KeepAlive)
+			setInt(&c.HTTPDialTimeout, fc.LLM.HTTP.DialTimeout)
+			setString(&c.HTTPProxy, fc.LLM.HTTP.Proxy)
+			setString(&c.HTTPCABundle, fc.LLM.HTTP.CABundle)
+			setString(&c.HTTPTLSMinVersion, fc.LLM.HTTP.TLSMinVersion)
+			if fc.LLM.HTTP.DisableHTTP2 != nil {
+				c.HTTPDisableHTTP2 = *fc.LLM.HTTP.DisableHTTP2
+			}
+		}
+		if fc.LLM.Prompts != nil {
+			setString(&c.PromptDir, fc.LLM.Prompts.Dir)
+			setString(&c.PromptDefault, fc.LLM.Prompts.Default)
+			if fc.LLM.Prompts.Models != nil {
+				c.PromptModels = fc.LLM.Prompts.Models
+			}
+		}
+		if fc.LLM.Quality != nil {
+			if fc.LLM.Quality.Enabled != nil {
+				c.QualityEnabled = *fc.LLM.Quality.Enabled
+			}
+			setString(&c.QualityJudgeModel, fc.LLM.Quality.JudgeModel)
+			if fc.LLM.Quality.Threshold != nil {
+				c.QualityThreshold = *fc.LLM.Quality.Threshold
+			}
+			setString(&c.BackTranslationModel, fc.LLM.Quality.BackTranslationModel)
+		}
+		if fc.LLM.PII != nil {
+			setString(&c.PIIDefaultPolicy, fc.LLM.PII.Default)
+			if fc.LLM.PII.Policies != nil {
+				c.PIIPolicies = fc.LLM.PII.Policies
+			}
+		}
+	}
+	if fc.History != nil {
+		setString(&c.HistoryStore, fc.History.Store)
+		setString(&c.HistoryPath, fc.History.Path)
+	}
+	if fc.Sessions != nil {
+		setInt(&c.MaxSessions, fc.Sessions.MaxSessions)
+		setInt(&c.SessionMaxTurns, fc.Sessions.MaxTurns)
+		setInt(&c.SessionTTL, fc.Sessions.TTL)
+	}
+	if fc.Review != nil {
+		if fc.Review.Enabled != nil {
+			c.ReviewEnabled = *fc.Review.Enabled
+		}
+		if fc.Review.Reviewers != nil {
+			c.ReviewReviewers = fc.Review.Reviewers
+		}
+	}
+	if fc.Tenants != nil {
+		c.Tenants = make(map[string]TenantConfig, len(fc.Tenants))
+		for name, tenant := range fc.Tenants {
+			if tenant == nil {
+				tenant = &TenantFileConfig{}
+			}
+			c.Tenants[name] = TenantConfig{
+				APIKeys:           tenant.APIKeys,
+				AllowTenantHeader: tenant.AllowTenantHeader,
+				OpenAIKey:         tenant.OpenAIKey,
+				AnthropicKey:      tenant.AnthropicKey,
+				DefaultModel:      tenant.DefaultModel,
+				TargetLanguage:    tenant.TargetLanguage,
+				AllowedProviders:  tenant.AllowedProviders,
+				AllowedEndpoints:  tenant.AllowedEndpoints,
+				Disabled:          tenant.Disabled,
+			}
+		}
+	}
+	if fc.Admin != nil {
+		setString(&c.AdminKey, fc.Admin.Key)
+	}
+	if fc.Debug != nil {
+		c.Debug = *fc.Debug
+	}
+}
+
+// Effective returns the configuration in config file form, with secrets masked
+func (c *Config) Effective() *FileConfig {
+	proxy := c.HTTPProxy
+	if proxyURL, err := url.Parse(c.HTTPProxy); err == nil {
+		// Hide a proxy password
+		proxy = proxyURL.Redacted()
+	}
+
+	var routing *RoutingFileConfig
+	if c.RoutingModels != nil {
+		routing = &RoutingFileConfig{Models: make(map[string]*RoutingModelFileConfig, len(c.RoutingModels))}
+		for model, settings := range c.RoutingModels {
+			routing.Models[model] = &RoutingModelFileConfig{Cost: settings.Cost, Languages: settings.Languages}
+		}
+	}
+
+	var tenants map[string]*TenantFileConfig
+	if c.Tenants != nil {
+		tenants = make(map[string]*TenantFileConfig, len(c.Tenants))
+		for name, tenant := range c.Tenants {
+			tenants[name] = &TenantFileConfig{
+				APIKeys:           tenant.APIKeys,
+				AllowTenantHeader: tenant.AllowTenantHeader,
+				OpenAIKey:         tenant.OpenAIKey,
+				AnthropicKey:      tenant.AnthropicKey,
+				DefaultModel:      tenant.DefaultModel,
+				TargetLanguage:    tenant.TargetLanguage,
+				AllowedProviders:  tenant.AllowedProviders,
+				AllowedEndpoints:  tenant.AllowedEndpoints,
+				Disabled:          tenant.Disabled,
+			}
+		}
+	}
+
+	effective := &FileConfig{
+		Server: &ServerFileConfig{
+			Port:            &c.ServerPort,
+			ReadTimeout:     &c.ReadTimeout,
+			WriteTimeout:    &c.WriteTimeout,
+			IdleTimeout:     &c.IdleTimeout,
+			ShutdownTimeout: &c.ShutdownTimeout,
+		},
+		LLM: &LLMFileConfig{
+			OpenAIEndpoint:    &c.OpenAIEndpoint,
+			OpenAIKey:         &c.OpenAIKey,
+			AnthropicEndpoint: &c.AnthropicEndpoint,
+			AnthropicKey:      &c.AnthropicKey,
+			Timeout:           &c.Timeout,
+			Local: &LocalFileConfig{
+				Provider: &c.LocalProvider,
+				Endpoint: &c.LocalEndpoint,
+				Model:    &c.LocalModel,
+			},
+			Azure: &AzureFileConfig{
+				Endpoint:    &c.AzureEndpoint,
+				Key:         &c.AzureKey,
+				APIVersion:  &c.AzureAPIVersion,
+				Deployments: c.AzureDeployments,
+			},
+			Gemini: &GeminiFileConfig{
+				Endpoint: &c.GeminiEndpoint,
+				Key:      &c.GeminiKey,
+				Models:   c.GeminiModels,
+			},
+			DeepL: &DeepLFileConfig{
+				Endpoint: &c.DeepLEndpoint,
+				Key:      &c.DeepLKey,
+			},
+			LibreTranslate: &LibreTranslateFileConfig{
+				Endpoint: &c.LibreTranslateEndpoint,
+				Key:      &c.LibreTranslateKey,
+			},
+			Prompts: &PromptsFileConfig{
+				Dir:     &c.PromptDir,
+				Default: &c.PromptDefault,
+				Models:  c.PromptModels,
+			},
+			Quality: &QualityFileConfig{
+				Enabled:              &c.QualityEnabled,
+				JudgeModel:           &c.QualityJudgeModel,
+				Threshold:            &c.QualityThreshold,
+				BackTranslationModel: &c.BackTranslationModel,
+			},
+			PII: &PIIFileConfig{
+				Default:  &c.PIIDefaultPolicy,
+				Policies: c.PIIPolicies,
+			},
+			Aliases: c.ModelAliases,
+			Routing: routing,
+			Hedging: &HedgingFileConfig{
+				Enabled:    &c.HedgingEnabled,
+				Percentile: &c.HedgingPercentile,
+				DelayMs:    &c.HedgingDelay,
+				Alternates: c.HedgingAlternates,
+			},
+			HTTP: &HTTPFileConfig{
+				MaxIdleConnsPerHost: &c.HTTPMaxIdleConnsPerHost,
+				IdleConnTimeout:     &c.HTTPIdleConnTimeout,
+				KeepAlive:           &c.HTTPKeepAlive,
+				DialTimeout:         &c.HTTPDialTimeout,
+				Proxy:               &proxy,
+				CABundle:            &c.HTTPCABundle,
+				TLSMinVersion:       &c.HTTPTLSMinVersion,
+				DisableHTTP2:        &c.HTTPDisableHTTP2,
+			},
+		},
+		History: &HistoryFileConfig{
+			Store: &c.HistoryStore,
+			Path:  &c.HistoryPath,
+		},
+		Sessions: &SessionsFileConfig{
+			MaxSessions: &c.MaxSessions,
+			MaxTurns:    &c.SessionMaxTurns,
+			TTL:         &c.SessionTTL,
+		},
+		Review: &ReviewFileConfig{
+			Enabled:   &c.ReviewEnabled,
+			Reviewers: c.ReviewReviewers,
+		},
+		Tenants: tenants,
+		Admin: &AdminFileConfig{
+			Key: &c.AdminKey,
+		},
+		Debug: &c.Debug,
+	}
+
+	// The fields tagged as secret are replaced with masked copies, leaving the configuration intact
+	replaceSecrets(reflect.ValueOf(effective), MaskSecret)
+	return effective
+}
+
+// setString overwrites dst when the file sets a value
+func setString(dst *string, value *string) {
+	if value != nil {
+		*dst = *value
+	}
+}
+
+// setInt overwrites dst when the file sets a value
+func setInt(dst *int, value *int) {
+	if value != nil {
+		*dst = *value
+	}
+}
@@ -0,0 +1,40 @@
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+)
+
+// TLSVersion converts a minimum TLS version such as 1.2 to its crypto/tls
+// constant; empty means TLS 1.2
+func TLSVersion(version string) (uint16, error) {
+	switch version {
+	case "", "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("http tls min version must be one of: 1.2, 1.3")
+	}
+}
+
+// LoadCABundle reads a PEM file of CA certificates and returns the system
+// certificate pool with them added, so providers behind a private CA are
+// trusted alongside public ones
+func LoadCABundle(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("http ca bundle: %w", err)
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("http ca bundle %s contains no PEM certificates", path)
+	}
+	return pool, nil
+}
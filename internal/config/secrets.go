@@ -0,0 +1,236 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// secretHelperTimeout bounds how long an exec: secret helper may run
+const secretHelperTimeout = 10 * time.Second
+
+// isSecretReference returns true if the value refers to a secret stored elsewhere
+func isSecretReference(value string) bool {
+	return strings.HasPrefix(value, "file:") ||
+		strings.HasPrefix(value, "env:") ||
+		strings.HasPrefix(value, "exec:")
+}
+
+// resolveSecret resolves a secret reference of the form file:/path, env:NAME or
+// exec:command args. Values that are not references are returned unchanged.
+func resolveSecret(value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, "file:"):
+		path := strings.TrimPrefix(value, "file:")
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read secret file %s: %w", path, err)
+		}
+		secret := strings.TrimSpace(string(data))
+		if secret == "" {
+			return "", fmt.Errorf("secret file %s is empty", path)
+		}
+		return secret, nil
+
+	case strings.HasPrefix(value, "env:"):
+		name := strings.TrimPrefix(value, "env:")
+		secret := strings.TrimSpace(os.Getenv(name))
+		if secret == "" {
+			return "", fmt.Errorf("secret environment variable %s is not set", name)
+		}
+		return secret, nil
+
+	case strings.HasPrefix(value, "exec:"):
+		args := strings.Fields(strings.TrimPrefix(value, "exec:"))
+		if len(args) == 0 {
+			return "", fmt.Errorf("secret helper command is empty")
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), secretHelperTimeout)
+		defer cancel()
+
+		// Only stdout is captured; the helper's stderr is not included in errors
+		// because it may echo the secret
+		output, err := exec.CommandContext(ctx, args[0], args[1:]...).Output()
+		if err != nil {
+			return "", fmt.Errorf("secret helper %s failed: %w", args[0], err)
+		}
+		secret := strings.TrimSpace(string(output))
+		if secret == "" {
+			return "", fmt.Errorf("secret helper %s returned an empty secret", args[0])
+		}
+		return secret, nil
+
+	default:
+		return value, nil
+	}
+}
+
+// ValidateStoredSecret checks that a secret kept outside the config file, such
+// as a provider key of a tenant created through the admin API, is a file: or
+// env: reference. Literal secrets would be stored in plain text, and exec:
+// helpers would let the admin API run commands.
+func ValidateStoredSecret(value string) error {
+	name, ok := strings.CutPrefix(value, "file:")
+	if !ok {
+		name, ok = strings.CutPrefix(value, "env:")
+	}
+	if !ok || strings.TrimSpace(name) == "" {
+		return fmt.Errorf("must be a file:/path or env:NAME reference")
+	}
+	return nil
+}
+
+// ResolveStoredSecret resolves a secret reference checked by ValidateStoredSecret
+func ResolveStoredSecret(value string) (string, error) {
+	if err := ValidateStoredSecret(value); err != nil {
+		return "", err
+	}
+	return resolveSecret(value)
+}
+
+// resolveSecrets replaces secret references in the configuration with their values
+func (c *Config) resolveSecrets() error {
+	var err error
+	if c.OpenAIKey, err = resolveSecret(c.OpenAIKey); err != nil {
+		return fmt.Errorf("openai_key: %w", err)
+	}
+	if c.AnthropicKey, err = resolveSecret(c.AnthropicKey); err != nil {
+		return fmt.Errorf("anthropic_key: %w", err)
+	}
+	if c.AzureKey, err = resolveSecret(c.AzureKey); err != nil {
+		return fmt.Errorf("azure key: %w", err)
+	}
+	if c.GeminiKey, err = resolveSecret(c.GeminiKey); err != nil {
+		return fmt.Errorf("gemini key: %w", err)
+	}
+	if c.DeepLKey, err = resolveSecret(c.DeepLKey); err != nil {
+		return fmt.Errorf("deepl key: %w", err)
+	}
+	if c.LibreTranslateKey, err = resolveSecret(c.LibreTranslateKey); err != nil {
+		return fmt.Errorf("libretranslate key: %w", err)
+	}
+	if c.AdminKey, err = resolveSecret(c.AdminKey); err != nil {
+		return fmt.Errorf("admin key: %w", err)
+	}
+	for name, key := range c.ReviewReviewers {
+		if c.ReviewReviewers[name], err = resolveSecret(key); err != nil {
+			return fmt.Errorf("reviewer %s key: %w", name, err)
+		}
+	}
+	for name, tenant := range c.Tenants {
+		// Tenants are stored by value, so the resolved keys are written back
+		apiKeys := make([]string, len(tenant.APIKeys))
+		for i, key := range tenant.APIKeys {
+			if apiKeys[i], err = resolveSecret(key); err != nil {
+				return fmt.Errorf("tenant %s api key: %w", name, err)
+			}
+		}
+		tenant.APIKeys = apiKeys
+		if tenant.OpenAIKey, err = resolveSecret(tenant.OpenAIKey); err != nil {
+			return fmt.Errorf("tenant %s openai key: %w", name, err)
+		}
+		if tenant.AnthropicKey, err = resolveSecret(tenant.AnthropicKey); err != nil {
+			return fmt.Errorf("tenant %s anthropic key: %w", name, err)
+		}
+		c.Tenants[name] = tenant
+	}
+	return nil
+}
+
+// checkFilePermissions refuses a world-readable config file that contains a literal secret
+func checkFilePermissions(filename string, secrets ...string) error {
+	hasLiteral := false
+	for _, secret := range secrets {
+		if secret != "" && !isSecretReference(secret) && !interpolationPattern.MatchString(secret) {
+			hasLiteral = true
+		}
+	}
+	if !hasLiteral {
+		return nil
+	}
+
+	info, err := os.Stat(filename)
+	if err != nil {
+		return err
+	}
+	if info.Mode().Perm()&0004 != 0 {
+		return fmt.Errorf("config file %s contains a literal API key and is world-readable; "+
+			"restrict its permissions (chmod 600) or use a file:, env: or exec: reference", filename)
+	}
+	return nil
+}
+
+// replaceSecrets replaces the values of the config file fields tagged
+// secret:"true" below v with what replace returns for them. Pointers, slices
+// and maps holding secrets are swapped for new ones rather than written
+// through, so values shared with a Config are left alone.
+func replaceSecrets(v reflect.Value, replace func(string) string) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			replaceSecrets(v.Elem(), replace)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).Tag.Get("secret") == "true" {
+				replaceSecret(v.Field(i), replace)
+			} else {
+				replaceSecrets(v.Field(i), replace)
+			}
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			replaceSecrets(v.Index(i), replace)
+		}
+	case reflect.Map:
+		// Only sections held by pointer, such as tenants, can hold secrets
+		for _, key := range v.MapKeys() {
+			replaceSecrets(v.MapIndex(key), replace)
+		}
+	}
+}
+
+// replaceSecret replaces the secrets held by a field tagged secret:"true"
+func replaceSecret(field reflect.Value, replace func(string) string) {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(replace(field.String()))
+	case reflect.Ptr:
+		if !field.IsNil() {
+			value := replace(field.Elem().String())
+			field.Set(reflect.ValueOf(&value))
+		}
+	case reflect.Slice:
+		if !field.IsNil() {
+			values := reflect.MakeSlice(field.Type(), field.Len(), field.Len())
+			for i := 0; i < field.Len(); i++ {
+				values.Index(i).SetString(replace(field.Index(i).String()))
+			}
+			field.Set(values)
+		}
+	case reflect.Map:
+		if !field.IsNil() {
+			values := reflect.MakeMapWithSize(field.Type(), field.Len())
+			for _, key := range field.MapKeys() {
+				values.SetMapIndex(key, reflect.ValueOf(replace(field.MapIndex(key).String())))
+			}
+			field.Set(values)
+		}
+	}
+}
+
+// MaskSecret returns a representation of a secret that is safe to print
+func MaskSecret(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	if len(secret) <= 8 {
+		return "****"
+	}
+	return "****" + secret[len(secret)-4:]
+}
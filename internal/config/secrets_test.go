@@ -0,0 +1,142 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolveSecret(t *testing.T) {
+	dir := t.TempDir()
+	secretFile := filepath.Join(dir, "openai")
+	if err := os.WriteFile(secretFile, []byte("sk-from-file\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	original := os.Getenv("TEST_SECRET_VALUE")
+	defer os.Setenv("TEST_SECRET_VALUE", original)
+	os.Setenv("TEST_SECRET_VALUE", "sk-from-env")
+
+	tests := []struct {
+		name        string
+		value       string
+		expected    string
+		expectError bool
+	}{
+		{name: "Literal value", value: "sk-literal", expected: "sk-literal"},
+		{name: "Empty value", value: "", expected: ""},
+		{name: "File reference", value: "file:" + secretFile, expected: "sk-from-file"},
+		{name: "Missing file", value: "file:" + filepath.Join(dir, "missing"), expectError: true},
+		{name: "Env reference", value: "env:TEST_SECRET_VALUE", expected: "sk-from-env"},
+		{name: "Unset env", value: "env:TEST_SECRET_UNSET", expectError: true},
+		{name: "Exec reference", value: "exec:echo sk-from-helper", expected: "sk-from-helper"},
+		{name: "Failing helper", value: "exec:false", expectError: true},
+		{name: "Empty helper", value: "exec:", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			secret, err := resolveSecret(tt.value)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if secret != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, secret)
+			}
+		})
+	}
+}
+
+func TestResolveStoredSecret(t *testing.T) {
+	t.Setenv("TEST_STORED_SECRET", "sk-from-env")
+
+	if secret, err := ResolveStoredSecret("env:TEST_STORED_SECRET"); err != nil || secret != "sk-from-env" {
+		t.Errorf("Expected the env reference to resolve, got %q (%v)", secret, err)
+	}
+
+	// Literal secrets and helpers are refused
+	for _, value := range []string{"sk-literal", "", "env:", "file: ", "exec:echo sk-from-helper"} {
+		if _, err := ResolveStoredSecret(value); err == nil {
+			t.Errorf("Expected error for %q", value)
+		}
+	}
+}
+
+func TestLoad_WorldReadableLiteralKey(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	content := "llm:\n  openai_endpoint: \"https://api.openai.com/v1\"\n  openai_key: \"sk-literal-key\"\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(path, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// A world-readable file with a literal key is refused
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "world-readable") {
+		t.Errorf("Expected world-readable error, got %v", err)
+	}
+
+	// The same file is accepted once permissions are restricted
+	if err := os.Chmod(path, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	// Every field tagged as secret is checked, including the admin and tenant keys
+	content = "admin:\n  key: \"admin-literal-key\"\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(path, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "world-readable") {
+		t.Errorf("Expected world-readable error for the admin key, got %v", err)
+	}
+
+	// References are allowed in world-readable files
+	secretFile := filepath.Join(dir, "openai")
+	if err := os.WriteFile(secretFile, []byte("sk-from-file"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	content = "llm:\n  openai_endpoint: \"https://api.openai.com/v1\"\n  openai_key: \"file:" + secretFile + "\"\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(path, 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cfg.GetOpenAIKey() != "sk-from-file" {
+		t.Errorf("Expected resolved key, got %s", MaskSecret(cfg.GetOpenAIKey()))
+	}
+}
+
+func TestConfig_StringMasksSecrets(t *testing.T) {
+	cfg := &Config{OpenAIKey: "sk-very-secret-openai", AnthropicKey: "sk-ant-very-secret"}
+
+	printed := cfg.String()
+	if strings.Contains(printed, "very-secret") {
+		t.Errorf("Expected secrets to be masked, got %s", printed)
+	}
+	if !strings.Contains(printed, "****enai") {
+		t.Errorf("Expected masked OpenAI key suffix, got %s", printed)
+	}
+	if MaskSecret("short") != "****" {
+		t.Errorf("Expected short secrets to be fully masked")
+	}
+}
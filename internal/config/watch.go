@@ -0,0 +1,33 @@
+package config
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// WatchFile polls a file for changes and calls onChange whenever its
+// modification time or size changes. It returns when the context is done.
+func WatchFile(ctx context.Context, path string, interval time.Duration, onChange func()) {
+	last, _ := os.Stat(path)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			current, err := os.Stat(path)
+			if err != nil {
+				// The file may be mid-replacement; try again on the next tick
+				continue
+			}
+			if last == nil || !current.ModTime().Equal(last.ModTime()) || current.Size() != last.Size() {
+				last = current
+				onChange()
+			}
+		}
+	}
+}
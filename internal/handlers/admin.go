@@ -0,0 +1,336 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"translator-service/internal/models"
+	"translator-service/internal/services"
+)
+
+// adminKeyHeader carries the key of the admin API
+const adminKeyHeader = "X-Admin-Key"
+
+// adminKeyValid reports whether a request carries the admin key, either in the
+// X-Admin-Key header or, for browsers, as the password of HTTP basic authentication
+func adminKeyValid(r *http.Request, adminKey string) bool {
+	key := r.Header.Get(adminKeyHeader)
+	if key == "" {
+		_, key, _ = r.BasicAuth()
+	}
+	return subtle.ConstantTimeCompare([]byte(key), []byte(adminKey)) == 1
+}
+
+// authorizeAdminAPI checks the admin key of an admin API request, writing the
+// JSON error response when it may not proceed
+func authorizeAdminAPI(w http.ResponseWriter, r *http.Request, translatorService *services.TranslatorService) bool {
+	adminKey := translatorService.Config().AdminKey
+	if adminKey == "" {
+		writeJSONError(w, http.StatusNotFound, "Admin API is disabled", "set admin.key to use the admin API")
+		return false
+	}
+	if !adminKeyValid(r, adminKey) {
+		writeJSONError(w, http.StatusUnauthorized, "A valid admin key is required", "send the admin key in the "+adminKeyHeader+" header")
+		return false
+	}
+	return true
+}
+
+// AdminStatusHandler reports the state of the running service through the admin API
+type AdminStatusHandler struct {
+	translatorService *services.TranslatorService
+}
+
+func NewAdminStatusHandler(translatorService *services.TranslatorService) http.HandlerFunc {
+	handler := &AdminStatusHandler{
+		translatorService: translatorService,
+	}
+
+	return handler.ServeHTTP
+}
+
+func (h *AdminStatusHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !authorizeAdminAPI(w, r, h.translatorService) {
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, h.translatorService.AdminStatus())
+}
+
+// ModelAdminHandler lists the registered models and enables or disables them through the admin API
+type ModelAdminHandler struct {
+	translatorService *services.TranslatorService
+}
+
+func NewModelAdminHandler(translatorService *services.TranslatorService) http.HandlerFunc {
+	handler := &ModelAdminHandler{
+		translatorService: translatorService,
+	}
+
+	return handler.ServeHTTP
+}
+
+func (h *ModelAdminHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !authorizeAdminAPI(w, r, h.translatorService) {
+		return
+	}
+
+	model := r.PathValue("model")
+	switch {
+	case model == "" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, map[string]interface{}{"models": h.translatorService.AdminStatus().Models})
+	case model != "" && r.Method == http.MethodPatch:
+		var update models.ModelUpdate
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil || update.Enabled == nil {
+			http.Error(w, "Invalid JSON request: enabled is required", http.StatusBadRequest)
+			return
+		}
+
+		status, err := h.translatorService.SetModelEnabled(model, *update.Enabled)
+		if err != nil {
+			writeJSONError(w, getErrorCode(err), getErrorMessage(err), err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, status)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// AliasAdminHandler lists model aliases and sets their weights through the admin API
+type AliasAdminHandler struct {
+	translatorService *services.TranslatorService
+}
+
+func NewAliasAdminHandler(translatorService *services.TranslatorService) http.HandlerFunc {
+	handler := &AliasAdminHandler{
+		translatorService: translatorService,
+	}
+
+	return handler.ServeHTTP
+}
+
+func (h *AliasAdminHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !authorizeAdminAPI(w, r, h.translatorService) {
+		return
+	}
+
+	name := r.PathValue("alias")
+	switch {
+	case name == "" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, map[string]interface{}{"aliases": h.translatorService.ListAliases()})
+	case name != "" && r.Method == http.MethodGet:
+		alias, err := h.translatorService.Alias(name)
+		if err != nil {
+			writeJSONError(w, getErrorCode(err), getErrorMessage(err), err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, alias)
+	case name != "" && r.Method == http.MethodPut:
+		var update models.AliasUpdate
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			http.Error(w, "Invalid JSON request", http.StatusBadRequest)
+			return
+		}
+
+		alias, err := h.translatorService.SetAliasWeights(name, update.Models)
+		if err != nil {
+			log.Printf("Alias error: %v", err)
+			writeJSONError(w, getErrorCode(err), getErrorMessage(err), err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, alias)
+	case name != "" && r.Method == http.MethodDelete:
+		if err := h.translatorService.ResetAlias(name); err != nil {
+			writeJSONError(w, getErrorCode(err), getErrorMessage(err), err.Error())
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// AdminPageHandler serves the admin dashboard and applies the model and alias controls submitted from it
+type AdminPageHandler struct {
+	translatorService *services.TranslatorService
+	// csrfSecret signs the tokens of the dashboard forms
+	csrfSecret []byte
+}
+
+func NewAdminPageHandler(translatorService *services.TranslatorService) http.HandlerFunc {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		log.Fatalf("Failed to generate admin form secret: %v", err)
+	}
+
+	handler := &AdminPageHandler{
+		translatorService: translatorService,
+		csrfSecret:        secret,
+	}
+
+	return handler.ServeHTTP
+}
+
+func (h *AdminPageHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	adminKey := h.translatorService.Config().AdminKey
+	if adminKey == "" {
+		http.Error(w, "Admin area is disabled", http.StatusNotFound)
+		return
+	}
+	if !adminKeyValid(r, adminKey) {
+		// Browsers ask for the key; any user name is accepted
+		w.Header().Set("WWW-Authenticate", `Basic realm="Translator admin", charset="UTF-8"`)
+		http.Error(w, "A valid admin key is required", http.StatusUnauthorized)
+		return
+	}
+
+	// Browsers resend basic credentials with forms posted from other sites,
+	// which cannot read the dashboard to learn its token
+	if r.Method == http.MethodPost {
+		if !sameOrigin(r) || !hmac.Equal([]byte(r.PostFormValue("csrf_token")), []byte(h.csrfToken(adminKey))) {
+			http.Error(w, "Cross-origin request refused", http.StatusForbidden)
+			return
+		}
+	}
+
+	model, alias := r.PathValue("model"), r.PathValue("alias")
+	switch {
+	case model == "" && alias == "" && r.Method == http.MethodGet:
+		h.serveDashboard(w, h.csrfToken(adminKey))
+	case model != "" && r.Method == http.MethodPost:
+		h.serveModelAction(w, r, model)
+	case alias != "" && r.Method == http.MethodPost:
+		h.serveAliasAction(w, r, alias)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// sameOrigin reports whether a form was posted from the dashboard itself.
+// Browsers resend basic credentials to any page, so forms from other sites
+// would otherwise be accepted.
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && u.Host == r.Host
+}
+
+// csrfToken returns the token of the dashboard forms for an admin key, so
+// tokens stop working once the key is changed
+func (h *AdminPageHandler) csrfToken(adminKey string) string {
+	mac := hmac.New(sha256.New, h.csrfSecret)
+	mac.Write([]byte(adminKey))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// serveDashboard renders the models, provider health, request volume and recent errors
+func (h *AdminPageHandler) serveDashboard(w http.ResponseWriter, csrfToken string) {
+	status := h.translatorService.AdminStatus()
+
+	if adminTemplate == nil {
+		// Fallback for testing or when templates are not available
+		writeJSON(w, http.StatusOK, status)
+		return
+	}
+
+	// Scale the volume chart to the busiest minute
+	var busiest int64
+	for _, count := range status.Volume.PerMinute {
+		busiest = max(busiest, count)
+	}
+	bars := make([]int64, len(status.Volume.PerMinute))
+	for i, count := range status.Volume.PerMinute {
+		if busiest > 0 {
+			bars[i] = count * 100 / busiest
+		}
+	}
+
+	data := struct {
+		Status    *models.AdminStatus
+		Bars      []int64
+		CSRFToken string
+	}{
+		Status:    status,
+		Bars:      bars,
+		CSRFToken: csrfToken,
+	}
+	if err := adminTemplate.Execute(w, data); err != nil {
+		log.Printf("Error rendering admin template: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
+// serveModelAction enables or disables a model from the dashboard and redirects back to it
+func (h *AdminPageHandler) serveModelAction(w http.ResponseWriter, r *http.Request, model string) {
+	enabled, err := strconv.ParseBool(r.FormValue("enabled"))
+	if err != nil {
+		http.Error(w, "Invalid form data: enabled must be true or false", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := h.translatorService.SetModelEnabled(model, enabled); err != nil {
+		http.Error(w, getErrorMessage(err), getErrorCode(err))
+		return
+	}
+
+	http.Redirect(w, r, "/admin", http.StatusSeeOther)
+}
+
+// serveAliasAction sets or resets an alias's weights from the dashboard and redirects back to it
+func (h *AdminPageHandler) serveAliasAction(w http.ResponseWriter, r *http.Request, alias string) {
+	var err error
+	if r.FormValue("action") == "reset" {
+		err = h.translatorService.ResetAlias(alias)
+	} else {
+		var weights map[string]int
+		if weights, err = parseWeights(r.FormValue("models")); err != nil {
+			http.Error(w, "Invalid form data: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		_, err = h.translatorService.SetAliasWeights(alias, weights)
+	}
+	if err != nil {
+		http.Error(w, getErrorMessage(err), getErrorCode(err))
+		return
+	}
+
+	http.Redirect(w, r, "/admin", http.StatusSeeOther)
+}
+
+// parseWeights parses alias weights written as "gpt-4=90, gpt-4o=10"
+func parseWeights(value string) (map[string]int, error) {
+	weights := make(map[string]int)
+	for _, pair := range strings.Split(value, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		model, weight, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("weights must be written as model=weight, got %q", strings.TrimSpace(pair))
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(weight))
+		if err != nil {
+			return nil, fmt.Errorf("weight of %s must be a whole number", strings.TrimSpace(model))
+		}
+		weights[strings.TrimSpace(model)] = n
+	}
+	return weights, nil
+}
@@ -0,0 +1,291 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"translator-service/internal/models"
+	"translator-service/internal/services"
+	"translator-service/internal/storage"
+)
+
+// CompareAPIHandler handles REST API requests for multi-model comparisons
+type CompareAPIHandler struct {
+	translatorService *services.TranslatorService
+}
+
+func NewCompareAPIHandler(translatorService *services.TranslatorService) http.HandlerFunc {
+	handler := &CompareAPIHandler{
+		translatorService: translatorService,
+	}
+
+	return handler.ServeHTTP
+}
+
+func (h *CompareAPIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Decode JSON request
+	var req models.ComparisonRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON request", http.StatusBadRequest)
+		return
+	}
+
+	req.Text = strings.TrimSpace(req.Text)
+	if req.Text == "" {
+		http.Error(w, "Text field is required", http.StatusBadRequest)
+		return
+	}
+
+	// Create context with timeout
+	ctx, cancel := context.WithTimeout(requestContext(r), h.translatorService.Config().GetRequestTimeout())
+	defer cancel()
+
+	// Perform comparison
+	response, err := h.translatorService.Compare(ctx, &req)
+	if err != nil {
+		log.Printf("Comparison error: %v", err)
+		writeJSONError(w, getErrorCode(err), getErrorMessage(err), err.Error())
+		return
+	}
+
+	// Send JSON response
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding JSON response: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+}
+
+// ComparePageHandler serves the comparison web page and renders comparison results
+type ComparePageHandler struct {
+	translatorService *services.TranslatorService
+}
+
+func NewComparePageHandler(translatorService *services.TranslatorService) http.HandlerFunc {
+	handler := &ComparePageHandler{
+		translatorService: translatorService,
+	}
+
+	return handler.ServeHTTP
+}
+
+func (h *ComparePageHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	data := struct {
+		ModelOptions map[string]string
+		Comparison   *models.ComparisonResponse
+		Voted        string
+	}{
+		ModelOptions: make(map[string]string),
+		Voted:        r.URL.Query().Get("voted"),
+	}
+	for _, model := range h.translatorService.GetSupportedModels() {
+		data.ModelOptions[model] = getModelDisplayName(model)
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		// Show the comparison form
+	case http.MethodPost:
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Invalid form data", http.StatusBadRequest)
+			return
+		}
+
+		req := &models.ComparisonRequest{
+			Text:   strings.TrimSpace(r.FormValue("text")),
+			Models: r.Form["models"],
+		}
+		if req.Text == "" {
+			http.Error(w, "Please enter text to translate", http.StatusBadRequest)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(requestContext(r), h.translatorService.Config().GetRequestTimeout())
+		defer cancel()
+
+		response, err := h.translatorService.Compare(ctx, req)
+		if err != nil {
+			log.Printf("Comparison error: %v", err)
+			http.Error(w, getErrorMessage(err), getErrorCode(err))
+			return
+		}
+		data.Comparison = response
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Render compare template
+	if compareTemplate != nil {
+		if err := compareTemplate.Execute(w, data); err != nil {
+			log.Printf("Error rendering compare template: %v", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+	} else {
+		// Fallback for testing or when templates are not available
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(data.Comparison); err != nil {
+			log.Printf("Error encoding JSON response: %v", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// VoteHandler records reviewer votes submitted from the comparison page or the API
+type VoteHandler struct {
+	voteStore storage.VoteStore
+}
+
+func NewVoteHandler(voteStore storage.VoteStore) http.HandlerFunc {
+	handler := &VoteHandler{
+		voteStore: voteStore,
+	}
+
+	return handler.ServeHTTP
+}
+
+func (h *VoteHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.serveTally(w, r)
+	case http.MethodPost:
+		h.serveVote(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// serveTally writes the number of votes each model has received from the tenant
+func (h *VoteHandler) serveTally(w http.ResponseWriter, r *http.Request) {
+	tally, err := h.voteStore.Tally(r.Context(), services.TenantFromContext(r.Context()))
+	if err != nil {
+		log.Printf("Vote tally error: %v", err)
+		writeJSONError(w, http.StatusInternalServerError, "Failed to tally votes", err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{"votes": tally}); err != nil {
+		log.Printf("Error encoding JSON response: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+}
+
+// serveVote stores a vote sent either as JSON or as a form submission
+func (h *VoteHandler) serveVote(w http.ResponseWriter, r *http.Request) {
+	isJSON := strings.HasPrefix(r.Header.Get("Content-Type"), "application/json")
+
+	var vote models.Vote
+	if isJSON {
+		if err := json.NewDecoder(r.Body).Decode(&vote); err != nil {
+			http.Error(w, "Invalid JSON request", http.StatusBadRequest)
+			return
+		}
+	} else {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Invalid form data", http.StatusBadRequest)
+			return
+		}
+		vote.ComparisonID = r.FormValue("comparison_id")
+		vote.Model = r.FormValue("model")
+	}
+
+	vote.Voter = clientFromRequest(r)
+	vote.Tenant = services.TenantFromContext(r.Context())
+	vote.CreatedAt = time.Now().UTC()
+
+	err := validateVote(r.Context(), h.voteStore, &vote)
+	if err == nil {
+		err = h.voteStore.SaveVote(r.Context(), &vote)
+	}
+	if err != nil {
+		code, message := voteError(err)
+		if code == http.StatusInternalServerError {
+			log.Printf("Vote storage error: %v", err)
+		}
+		if isJSON {
+			writeJSONError(w, code, message, err.Error())
+		} else {
+			http.Error(w, message, code)
+		}
+		return
+	}
+
+	if !isJSON {
+		http.Redirect(w, r, "/compare?voted="+url.QueryEscape(vote.Model), http.StatusSeeOther)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(vote); err != nil {
+		log.Printf("Error encoding JSON response: %v", err)
+	}
+}
+
+// validateVote checks that a vote names one of the tenant's stored comparisons
+// and a model that translated the text in it. The vote's original text and
+// candidates are taken from the stored comparison rather than the client.
+func validateVote(ctx context.Context, store storage.VoteStore, vote *models.Vote) error {
+	vote.ComparisonID = strings.TrimSpace(vote.ComparisonID)
+	vote.Model = strings.TrimSpace(vote.Model)
+
+	if vote.ComparisonID == "" {
+		return fmt.Errorf("comparison_id is required")
+	}
+	if vote.Model == "" {
+		return fmt.Errorf("model is required")
+	}
+
+	comparison, err := store.GetComparison(ctx, vote.Tenant, vote.ComparisonID)
+	if err != nil {
+		return err
+	}
+
+	compared := false
+	candidates := make([]string, 0, len(comparison.Results))
+	for _, result := range comparison.Results {
+		candidates = append(candidates, result.Model)
+		if result.Model == vote.Model && result.Error == "" {
+			compared = true
+		}
+	}
+	if !compared {
+		return fmt.Errorf("model must be one of the compared candidates")
+	}
+
+	vote.Original = comparison.Original
+	vote.Candidates = candidates
+	return nil
+}
+
+// voteError returns the status code and message for a vote that could not be recorded
+func voteError(err error) (int, string) {
+	switch {
+	case errors.Is(err, storage.ErrComparisonNotFound):
+		return http.StatusNotFound, "Comparison not found"
+	case errors.Is(err, storage.ErrDuplicateVote):
+		return http.StatusConflict, "You have already voted on this comparison"
+	case strings.HasPrefix(err.Error(), "failed to"):
+		return http.StatusInternalServerError, "Failed to record vote"
+	default:
+		return http.StatusBadRequest, err.Error()
+	}
+}
@@ -3,22 +3,29 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"html/template"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
-	"time"
 
 	"translator-service/internal/models"
 	"translator-service/internal/services"
 )
 
 var (
-	homeTemplate   *template.Template
-	resultTemplate *template.Template
+	homeTemplate    *template.Template
+	resultTemplate  *template.Template
+	historyTemplate *template.Template
+	compareTemplate *template.Template
+	// reviewTemplate lists review items and reviewItemTemplate shows a single one
+	reviewTemplate     *template.Template
+	reviewItemTemplate *template.Template
+	adminTemplate      *template.Template
 )
 
 func init() {
@@ -29,6 +36,11 @@ func init() {
 		// This allows tests to run even when templates are not available
 		homeTemplatePath := filepath.Join("web", "templates", "home.html")
 		resultTemplatePath := filepath.Join("web", "templates", "result.html")
+		historyTemplatePath := filepath.Join("web", "templates", "history.html")
+		compareTemplatePath := filepath.Join("web", "templates", "compare.html")
+		reviewTemplatePath := filepath.Join("web", "templates", "review.html")
+		reviewItemTemplatePath := filepath.Join("web", "templates", "review_item.html")
+		adminTemplatePath := filepath.Join("web", "templates", "admin.html")
 
 		if homeTemplateFile, err := template.ParseFiles(homeTemplatePath); err == nil {
 			homeTemplate = homeTemplateFile
@@ -41,6 +53,36 @@ func init() {
 		} else {
 			log.Printf("Warning: Could not load result template: %v", err)
 		}
+
+		if historyTemplateFile, err := template.ParseFiles(historyTemplatePath); err == nil {
+			historyTemplate = historyTemplateFile
+		} else {
+			log.Printf("Warning: Could not load history template: %v", err)
+		}
+
+		if compareTemplateFile, err := template.ParseFiles(compareTemplatePath); err == nil {
+			compareTemplate = compareTemplateFile
+		} else {
+			log.Printf("Warning: Could not load compare template: %v", err)
+		}
+
+		if reviewTemplateFile, err := template.ParseFiles(reviewTemplatePath); err == nil {
+			reviewTemplate = reviewTemplateFile
+		} else {
+			log.Printf("Warning: Could not load review template: %v", err)
+		}
+
+		if reviewItemTemplateFile, err := template.ParseFiles(reviewItemTemplatePath); err == nil {
+			reviewItemTemplate = reviewItemTemplateFile
+		} else {
+			log.Printf("Warning: Could not load review item template: %v", err)
+		}
+
+		if adminTemplateFile, err := template.ParseFiles(adminTemplatePath); err == nil {
+			adminTemplate = adminTemplateFile
+		} else {
+			log.Printf("Warning: Could not load admin template: %v", err)
+		}
 	}
 }
 
@@ -73,7 +115,7 @@ func (h *HomeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		for _, model := range supportedModels {
 			// Use the model identifier as both key and value for now
 			// In a more sophisticated implementation, we could map to user-friendly names
-			modelOptions[model] = h.getModelDisplayName(model)
+			modelOptions[model] = getModelDisplayName(model)
 		}
 
 		// Pass data to template
@@ -97,7 +139,7 @@ func (h *HomeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 }
 
 // getModelDisplayName returns a user-friendly display name for a model
-func (h *HomeHandler) getModelDisplayName(model string) string {
+func getModelDisplayName(model string) string {
 	// Map model identifiers to user-friendly names
 	modelNames := map[string]string{
 		"gpt-3.5-turbo":            "GPT-3.5 Turbo",
@@ -112,12 +154,16 @@ func (h *HomeHandler) getModelDisplayName(model string) string {
 		"claude-3-sonnet-20240229": "Claude 3 Sonnet (2024-02-29)",
 		"claude-3-haiku-20240307":  "Claude 3 Haiku (2024-03-07)",
 		"claude":                   "Claude",
-		"llama":                    "Llama 2 (Mock)",
+		"llama":                    "Llama (Local)",
 		"Qwen3-Coder-Plus":         "Qwen3 Coder Plus",
 		"qwen-max-latest":          "Qwen Max Latest",
 		"qwen-plus":                "Qwen Plus",
 		"qwen2.5-max":              "Qwen 2.5 Max",
 		"qwen2.5-plus":             "Qwen 2.5 Plus",
+		"gemini-1.5-pro":           "Gemini 1.5 Pro",
+		"gemini-1.5-flash":         "Gemini 1.5 Flash",
+		"deepl":                    "DeepL",
+		"libretranslate":           "LibreTranslate",
 	}
 
 	if name, exists := modelNames[model]; exists {
@@ -169,25 +215,14 @@ func (h *TranslateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Create context with timeout
-	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(requestContext(r), h.translatorService.Config().GetRequestTimeout())
 	defer cancel()
 
 	// Perform translation
 	response, err := h.translatorService.Translate(ctx, req)
 	if err != nil {
 		log.Printf("Translation error: %v", err)
-		// Provide user-friendly error message
-		if strings.Contains(err.Error(), "validation error") {
-			http.Error(w, fmt.Sprintf("Invalid input: %s", strings.TrimPrefix(err.Error(), "validation error: ")), http.StatusBadRequest)
-		} else if strings.Contains(err.Error(), "unsupported model") {
-			http.Error(w, "Selected translation model is not supported", http.StatusBadRequest)
-		} else if strings.Contains(err.Error(), "context deadline exceeded") {
-			http.Error(w, "Translation request timed out. Please try again.", http.StatusRequestTimeout)
-		} else if strings.Contains(err.Error(), "context canceled") {
-			http.Error(w, "Translation request was canceled", http.StatusBadRequest)
-		} else {
-			http.Error(w, "Translation service temporarily unavailable. Please try again in a moment.", http.StatusServiceUnavailable)
-		}
+		http.Error(w, getErrorMessage(err), getErrorCode(err))
 		return
 	}
 
@@ -239,6 +274,12 @@ func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// Trim whitespace
 	req.Text = strings.TrimSpace(req.Text)
 	req.Model = strings.TrimSpace(req.Model)
+	req.SessionID = strings.TrimSpace(req.SessionID)
+
+	// Translations posted to /api/sessions/{id}/translate belong to that session
+	if id := r.PathValue("id"); id != "" {
+		req.SessionID = id
+	}
 
 	// Validate request
 	if req.Text == "" {
@@ -246,13 +287,14 @@ func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if req.Model == "" {
+	// Translations in a session default to the session's model, others to the tenant's
+	if req.Model == "" && req.SessionID == "" && h.translatorService.DefaultModel(r.Context()) == "" {
 		http.Error(w, "Model field is required", http.StatusBadRequest)
 		return
 	}
 
 	// Create context with timeout
-	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(requestContext(r), h.translatorService.Config().GetRequestTimeout())
 	defer cancel()
 
 	// Perform translation
@@ -260,17 +302,7 @@ func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		log.Printf("Translation error: %v", err)
 		// Provide structured error response
-		errorResponse := map[string]interface{}{
-			"error":   true,
-			"message": h.getErrorMessage(err),
-			"details": err.Error(),
-		}
-
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(h.getErrorCode(err))
-		if err := json.NewEncoder(w).Encode(errorResponse); err != nil {
-			log.Printf("Error encoding JSON error response: %v", err)
-		}
+		writeJSONError(w, getErrorCode(err), getErrorMessage(err), err.Error())
 		return
 	}
 
@@ -284,11 +316,34 @@ func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 }
 
 // getErrorMessage returns a user-friendly error message based on the error
-func (h *APIHandler) getErrorMessage(err error) string {
-	if strings.Contains(err.Error(), "validation error") {
+func getErrorMessage(err error) string {
+	var accessErr *services.TenantAccessError
+	if errors.As(err, &accessErr) {
+		return tenantAccessMessage(accessErr)
+	} else if strings.Contains(err.Error(), "validation error") {
 		return fmt.Sprintf("Invalid input: %s", strings.TrimPrefix(err.Error(), "validation error: "))
 	} else if strings.Contains(err.Error(), "unsupported model") {
 		return "Selected translation model is not supported"
+	} else if strings.Contains(err.Error(), "model disabled") {
+		return "Selected translation model is disabled"
+	} else if strings.Contains(err.Error(), "no model available") {
+		return "No translation model is available for the request"
+	} else if strings.Contains(err.Error(), "model not registered") {
+		return "Model not found"
+	} else if strings.Contains(err.Error(), "alias not found") {
+		return "Alias not found"
+	} else if strings.Contains(err.Error(), "exceeds max_length") {
+		return "Translation could not be shortened to max_length"
+	} else if strings.Contains(err.Error(), "content blocked") {
+		return "Request contains personal data that may not be sent to translation providers"
+	} else if strings.Contains(err.Error(), "review item not found") {
+		return "Review item not found"
+	} else if strings.Contains(err.Error(), "review conflict") {
+		return fmt.Sprintf("Review action not allowed: %s", strings.TrimPrefix(err.Error(), "review conflict: "))
+	} else if strings.Contains(err.Error(), "tenant not found") {
+		return "Tenant not found"
+	} else if strings.Contains(err.Error(), "tenant conflict") {
+		return fmt.Sprintf("Tenant change not allowed: %s", strings.TrimPrefix(err.Error(), "tenant conflict: "))
 	} else if strings.Contains(err.Error(), "context deadline exceeded") {
 		return "Translation request timed out"
 	} else if strings.Contains(err.Error(), "context canceled") {
@@ -299,11 +354,34 @@ func (h *APIHandler) getErrorMessage(err error) string {
 }
 
 // getErrorCode returns an appropriate HTTP status code based on the error
-func (h *APIHandler) getErrorCode(err error) int {
-	if strings.Contains(err.Error(), "validation error") {
+func getErrorCode(err error) int {
+	var accessErr *services.TenantAccessError
+	if errors.As(err, &accessErr) {
+		return http.StatusForbidden
+	} else if strings.Contains(err.Error(), "validation error") {
 		return http.StatusBadRequest
 	} else if strings.Contains(err.Error(), "unsupported model") {
 		return http.StatusBadRequest
+	} else if strings.Contains(err.Error(), "model disabled") {
+		return http.StatusServiceUnavailable
+	} else if strings.Contains(err.Error(), "no model available") {
+		return http.StatusServiceUnavailable
+	} else if strings.Contains(err.Error(), "model not registered") {
+		return http.StatusNotFound
+	} else if strings.Contains(err.Error(), "alias not found") {
+		return http.StatusNotFound
+	} else if strings.Contains(err.Error(), "exceeds max_length") {
+		return http.StatusUnprocessableEntity
+	} else if strings.Contains(err.Error(), "content blocked") {
+		return http.StatusUnprocessableEntity
+	} else if strings.Contains(err.Error(), "review item not found") {
+		return http.StatusNotFound
+	} else if strings.Contains(err.Error(), "review conflict") {
+		return http.StatusConflict
+	} else if strings.Contains(err.Error(), "tenant not found") {
+		return http.StatusNotFound
+	} else if strings.Contains(err.Error(), "tenant conflict") {
+		return http.StatusConflict
 	} else if strings.Contains(err.Error(), "context deadline exceeded") {
 		return http.StatusRequestTimeout
 	} else if strings.Contains(err.Error(), "context canceled") {
@@ -312,3 +390,44 @@ func (h *APIHandler) getErrorCode(err error) int {
 		return http.StatusServiceUnavailable
 	}
 }
+
+// writeJSONError writes a structured JSON error response
+func writeJSONError(w http.ResponseWriter, status int, message, details string) {
+	errorResponse := map[string]interface{}{
+		"error":   true,
+		"message": message,
+		"details": details,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(errorResponse); err != nil {
+		log.Printf("Error encoding JSON error response: %v", err)
+	}
+}
+
+// writeJSON writes a value as a JSON response with the given status
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		log.Printf("Error encoding JSON response: %v", err)
+	}
+}
+
+// requestContext returns the request's context carrying the calling client.
+// The tenant is added by the TenantMiddleware.
+func requestContext(r *http.Request) context.Context {
+	return services.WithClient(r.Context(), clientFromRequest(r))
+}
+
+// clientFromRequest returns an identifier for the client that sent the request
+func clientFromRequest(r *http.Request) string {
+	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+		return strings.TrimSpace(strings.Split(forwarded, ",")[0])
+	}
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
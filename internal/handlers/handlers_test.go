@@ -2,15 +2,19 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strconv"
 	"strings"
 	"testing"
 
 	"translator-service/internal/config"
 	"translator-service/internal/models"
 	"translator-service/internal/services"
+	"translator-service/internal/storage"
 )
 
 func createTestTranslatorService() *services.TranslatorService {
@@ -140,6 +144,72 @@ func TestTranslateHandler_EmptyModel(t *testing.T) {
 	}
 }
 
+func TestTranslateHandler_ContentBlocked(t *testing.T) {
+	// Create a form POST containing a blocked email address
+	form := strings.NewReader("text=Please%20email%20jane%40example.com&model=gpt-3.5")
+	req, err := http.NewRequest("POST", "/translate", form)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	// Create a ResponseRecorder
+	rr := httptest.NewRecorder()
+
+	// Create a translator service that blocks email addresses
+	service := services.NewTranslatorService(&config.Config{
+		ServerPort:  "8080",
+		Timeout:     30,
+		PIIPolicies: map[string]string{"email": "block"},
+	})
+
+	// Create the handler
+	handler := NewTranslateHandler(service)
+
+	// Serve the HTTP request
+	handler.ServeHTTP(rr, req)
+
+	// Check the status code (should be 422 Unprocessable Entity)
+	if status := rr.Code; status != http.StatusUnprocessableEntity {
+		t.Errorf("TranslateHandler returned wrong status code for blocked content: got %v want %v",
+			status, http.StatusUnprocessableEntity)
+	}
+}
+
+func TestTranslateHandler_DisabledModel(t *testing.T) {
+	// Create a form POST for a model that has been disabled by an admin
+	form := strings.NewReader("text=Hello%2C%20world%21&model=gpt-3.5")
+	req, err := http.NewRequest("POST", "/translate", form)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	// Create a ResponseRecorder
+	rr := httptest.NewRecorder()
+
+	// Create a translator service and disable the model
+	service := createTestTranslatorService()
+	if _, err := service.SetModelEnabled("gpt-3.5", false); err != nil {
+		t.Fatal(err)
+	}
+
+	// Create the handler
+	handler := NewTranslateHandler(service)
+
+	// Serve the HTTP request
+	handler.ServeHTTP(rr, req)
+
+	// Check the status code and that the page says why
+	if status := rr.Code; status != http.StatusServiceUnavailable {
+		t.Errorf("TranslateHandler returned wrong status code for a disabled model: got %v want %v",
+			status, http.StatusServiceUnavailable)
+	}
+	if !strings.Contains(rr.Body.String(), "Selected translation model is disabled") {
+		t.Errorf("TranslateHandler returned wrong message for a disabled model: %q", rr.Body.String())
+	}
+}
+
 func TestAPIHandler_GetRequest(t *testing.T) {
 	// Create a GET request to the API endpoint (should fail)
 	req, err := http.NewRequest("GET", "/api/translate", nil)
@@ -302,3 +372,773 @@ func TestAPIHandler_EmptyModel(t *testing.T) {
 			status, http.StatusBadRequest)
 	}
 }
+
+func TestAPIHandler_ContentBlocked(t *testing.T) {
+	// Create a JSON request containing a blocked email address
+	requestData := models.TranslationRequest{
+		Text:  "Please email jane@example.com",
+		Model: "gpt-3.5",
+	}
+	jsonData, _ := json.Marshal(requestData)
+
+	req, err := http.NewRequest("POST", "/api/translate", bytes.NewBuffer(jsonData))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	// Create a ResponseRecorder
+	rr := httptest.NewRecorder()
+
+	// Create a translator service that blocks email addresses
+	service := services.NewTranslatorService(&config.Config{
+		ServerPort:  "8080",
+		Timeout:     30,
+		PIIPolicies: map[string]string{"email": "block"},
+	})
+
+	// Create the handler
+	handler := NewAPIHandler(service)
+
+	// Serve the HTTP request
+	handler.ServeHTTP(rr, req)
+
+	// Check the status code (should be 422 Unprocessable Entity)
+	if status := rr.Code; status != http.StatusUnprocessableEntity {
+		t.Errorf("APIHandler returned wrong status code for blocked content: got %v want %v",
+			status, http.StatusUnprocessableEntity)
+	}
+}
+
+func TestAPIHandler_TenantForbidden(t *testing.T) {
+	// Create a JSON request for a tenant that is not configured
+	requestData := models.TranslationRequest{
+		Text:  "Hello, world!",
+		Model: "gpt-3.5",
+	}
+	jsonData, _ := json.Marshal(requestData)
+
+	req, err := http.NewRequest("POST", "/api/translate", bytes.NewBuffer(jsonData))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Tenant-ID", "globex")
+
+	// Create a ResponseRecorder
+	rr := httptest.NewRecorder()
+
+	// Create a translator service with a single tenant
+	service := services.NewTranslatorService(&config.Config{
+		ServerPort: "8080",
+		Timeout:    30,
+		Tenants:    map[string]config.TenantConfig{"acme": {AllowedProviders: []string{"azure"}}},
+	})
+
+	// Create the handler behind the middleware that identifies tenants
+	handler := NewTenantMiddleware(service, NewAPIHandler(service))
+
+	// Serve the HTTP request
+	handler.ServeHTTP(rr, req)
+
+	// Check the status code (should be 403 Forbidden)
+	if status := rr.Code; status != http.StatusForbidden {
+		t.Errorf("APIHandler returned wrong status code for unknown tenant: got %v want %v",
+			status, http.StatusForbidden)
+	}
+}
+
+func TestTenantAdminHandler(t *testing.T) {
+	service := createTestTranslatorService()
+
+	// Route requests the same way the server does
+	mux := http.NewServeMux()
+	adminHandler := NewTenantAdminHandler(service)
+	mux.HandleFunc("/api/admin/tenants", adminHandler)
+	mux.HandleFunc("/api/admin/tenants/{id}", adminHandler)
+	mux.HandleFunc("/api/translate", NewAPIHandler(service))
+	handler := NewTenantMiddleware(service, mux)
+
+	// The admin API is unavailable until an admin key is set
+	req, _ := http.NewRequest("GET", "/api/admin/tenants", nil)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusNotFound {
+		t.Errorf("TenantAdminHandler returned wrong status code while disabled: got %v want %v", status, http.StatusNotFound)
+	}
+
+	cfg := *service.Config()
+	cfg.AdminKey = "admin-secret"
+	service.Reload(&cfg, nil)
+
+	req, _ = http.NewRequest("GET", "/api/admin/tenants", nil)
+	req.Header.Set("X-Admin-Key", "wrong")
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusUnauthorized {
+		t.Errorf("TenantAdminHandler returned wrong status code for a bad key: got %v want %v", status, http.StatusUnauthorized)
+	}
+
+	// Create a tenant and keep its API key
+	req, _ = http.NewRequest("POST", "/api/admin/tenants", strings.NewReader(`{"id":"acme","default_model":"gpt-3.5"}`))
+	req.Header.Set("X-Admin-Key", "admin-secret")
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusCreated {
+		t.Fatalf("TenantAdminHandler returned wrong status code: got %v want %v: %s", status, http.StatusCreated, rr.Body.String())
+	}
+	var created models.TenantCreated
+	if err := json.Unmarshal(rr.Body.Bytes(), &created); err != nil || created.APIKey == "" {
+		t.Fatalf("TenantAdminHandler returned unexpected body: %v", rr.Body.String())
+	}
+
+	// The key identifies the tenant, whose default model is used
+	req, _ = http.NewRequest("POST", "/api/translate", strings.NewReader(`{"text":"Hello"}`))
+	req.Header.Set("Authorization", "Bearer "+created.APIKey)
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("Tenant translation returned wrong status code: got %v want %v: %s", status, http.StatusOK, rr.Body.String())
+	}
+
+	// The tenant ID alone is not enough
+	req, _ = http.NewRequest("POST", "/api/translate", strings.NewReader(`{"text":"Hello"}`))
+	req.Header.Set("X-Tenant-ID", "acme")
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusUnauthorized {
+		t.Errorf("Translation without a key returned wrong status code: got %v want %v", status, http.StatusUnauthorized)
+	}
+
+	// Disabling the tenant refuses its requests
+	req, _ = http.NewRequest("PATCH", "/api/admin/tenants/acme", strings.NewReader(`{"disabled":true}`))
+	req.Header.Set("X-Admin-Key", "admin-secret")
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("TenantAdminHandler returned wrong status code for disable: got %v want %v", status, http.StatusOK)
+	}
+
+	req, _ = http.NewRequest("POST", "/api/translate", strings.NewReader(`{"text":"Hello"}`))
+	req.Header.Set("X-API-Key", created.APIKey)
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusForbidden {
+		t.Errorf("Translation for a disabled tenant returned wrong status code: got %v want %v", status, http.StatusForbidden)
+	}
+
+	// Unknown tenants are reported as such
+	req, _ = http.NewRequest("GET", "/api/admin/tenants/globex", nil)
+	req.Header.Set("X-Admin-Key", "admin-secret")
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusNotFound {
+		t.Errorf("TenantAdminHandler returned wrong status code for unknown tenant: got %v want %v", status, http.StatusNotFound)
+	}
+}
+
+func TestAdminHandlers(t *testing.T) {
+	service := services.NewTranslatorService(&config.Config{ServerPort: "8080", Timeout: 30, AdminKey: "admin-secret"})
+
+	// Route requests the same way the server does
+	mux := http.NewServeMux()
+	modelAdminHandler := NewModelAdminHandler(service)
+	adminPage := &AdminPageHandler{translatorService: service, csrfSecret: []byte("test-secret")}
+	adminPageHandler := adminPage.ServeHTTP
+	token := adminPage.csrfToken("admin-secret")
+	mux.HandleFunc("/api/admin/status", NewAdminStatusHandler(service))
+	mux.HandleFunc("/api/admin/models", modelAdminHandler)
+	mux.HandleFunc("/api/admin/models/{model}", modelAdminHandler)
+	mux.HandleFunc("/admin", adminPageHandler)
+	mux.HandleFunc("/admin/models/{model}", adminPageHandler)
+	mux.HandleFunc("/api/translate", NewAPIHandler(service))
+
+	// Browsers are asked for the key
+	req, _ := http.NewRequest("GET", "/admin", nil)
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusUnauthorized || !strings.HasPrefix(rr.Header().Get("WWW-Authenticate"), "Basic") {
+		t.Errorf("AdminPageHandler returned wrong status code without a key: got %v want %v", status, http.StatusUnauthorized)
+	}
+
+	// Disable a model through the API
+	req, _ = http.NewRequest("PATCH", "/api/admin/models/gpt-3.5", strings.NewReader(`{"enabled":false}`))
+	req.Header.Set("X-Admin-Key", "admin-secret")
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK || !strings.Contains(rr.Body.String(), `"enabled":false`) {
+		t.Fatalf("ModelAdminHandler returned unexpected response: %v %s", status, rr.Body.String())
+	}
+
+	req, _ = http.NewRequest("POST", "/api/translate", strings.NewReader(`{"text":"Hello","model":"gpt-3.5"}`))
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusServiceUnavailable {
+		t.Errorf("Translation with a disabled model returned wrong status code: got %v want %v", status, http.StatusServiceUnavailable)
+	}
+
+	req, _ = http.NewRequest("PATCH", "/api/admin/models/no-such-model", strings.NewReader(`{"enabled":false}`))
+	req.Header.Set("X-Admin-Key", "admin-secret")
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusNotFound {
+		t.Errorf("ModelAdminHandler returned wrong status code for unknown model: got %v want %v", status, http.StatusNotFound)
+	}
+
+	// Forms without the dashboard's token are refused, even without an Origin header
+	req, _ = http.NewRequest("POST", "/admin/models/gpt-3.5", strings.NewReader("enabled=true"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth("admin", "admin-secret")
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusForbidden {
+		t.Errorf("AdminPageHandler returned wrong status code for a form without a token: got %v want %v", status, http.StatusForbidden)
+	}
+
+	// Re-enable it from the dashboard, which redirects back
+	req, _ = http.NewRequest("POST", "/admin/models/gpt-3.5", strings.NewReader("enabled=true&csrf_token="+token))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth("admin", "admin-secret")
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusSeeOther {
+		t.Errorf("AdminPageHandler returned wrong status code for a model action: got %v want %v", status, http.StatusSeeOther)
+	}
+
+	// Forms posted from other sites are refused
+	req, _ = http.NewRequest("POST", "/admin/models/gpt-3.5", strings.NewReader("enabled=false&csrf_token="+token))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Origin", "https://evil.example.com")
+	req.SetBasicAuth("admin", "admin-secret")
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusForbidden {
+		t.Errorf("AdminPageHandler returned wrong status code for a cross-origin form: got %v want %v", status, http.StatusForbidden)
+	}
+
+	// The status reports the failed request and the model enabled again
+	req, _ = http.NewRequest("GET", "/api/admin/status", nil)
+	req.Header.Set("X-Admin-Key", "admin-secret")
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	var status models.AdminStatus
+	if err := json.Unmarshal(rr.Body.Bytes(), &status); err != nil {
+		t.Fatalf("AdminStatusHandler returned unexpected body: %v", rr.Body.String())
+	}
+	if status.Volume.Total != 1 || status.Volume.Errors != 1 || len(status.RecentErrors) != 1 {
+		t.Errorf("Unexpected volume and errors: %+v %+v", status.Volume, status.RecentErrors)
+	}
+	for _, model := range status.Models {
+		if !model.Enabled || !model.Mock {
+			t.Errorf("Expected every model to be an enabled mock, got %+v", model)
+		}
+	}
+}
+
+func TestAliasAdminHandler(t *testing.T) {
+	service := services.NewTranslatorService(&config.Config{
+		ServerPort:   "8080",
+		Timeout:      30,
+		AdminKey:     "admin-secret",
+		ModelAliases: map[string]map[string]int{"quality": {"gpt-4": 1}},
+	})
+
+	mux := http.NewServeMux()
+	aliasAdminHandler := NewAliasAdminHandler(service)
+	mux.HandleFunc("/api/admin/aliases", aliasAdminHandler)
+	mux.HandleFunc("/api/admin/aliases/{alias}", aliasAdminHandler)
+	adminPage := &AdminPageHandler{translatorService: service, csrfSecret: []byte("test-secret")}
+	mux.HandleFunc("/admin/aliases/{alias}", adminPage.ServeHTTP)
+	token := adminPage.csrfToken("admin-secret")
+	mux.HandleFunc("/api/translate", NewAPIHandler(service))
+
+	req, _ := http.NewRequest("GET", "/api/admin/aliases", nil)
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusUnauthorized {
+		t.Errorf("AliasAdminHandler returned wrong status code without a key: got %v want %v", status, http.StatusUnauthorized)
+	}
+
+	// Route a new alias to a single model and translate with it
+	req, _ = http.NewRequest("PUT", "/api/admin/aliases/fast", strings.NewReader(`{"models":{"gpt-3.5":1,"claude":0}}`))
+	req.Header.Set("X-Admin-Key", "admin-secret")
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK || !strings.Contains(rr.Body.String(), `"source":"admin"`) {
+		t.Fatalf("AliasAdminHandler returned unexpected response: %v %s", status, rr.Body.String())
+	}
+
+	req, _ = http.NewRequest("POST", "/api/translate", strings.NewReader(`{"text":"Hello","model":"fast"}`))
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	var response models.TranslationResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil || rr.Code != http.StatusOK {
+		t.Fatalf("Translation with an alias returned unexpected response: %v %s", rr.Code, rr.Body.String())
+	}
+	if response.Model != "gpt-3.5" || response.Alias != "fast" {
+		t.Errorf("Expected fast to route to gpt-3.5, got %s via %q", response.Model, response.Alias)
+	}
+
+	req, _ = http.NewRequest("PUT", "/api/admin/aliases/fast", strings.NewReader(`{"models":{"no-such-model":1}}`))
+	req.Header.Set("X-Admin-Key", "admin-secret")
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Errorf("AliasAdminHandler returned wrong status code for an unknown model: got %v want %v", status, http.StatusBadRequest)
+	}
+
+	// Change the weights from the dashboard, then reset them
+	req, _ = http.NewRequest("POST", "/admin/aliases/quality", strings.NewReader("models=gpt-4%3D90%2C+gpt-4o%3D10&csrf_token="+token))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth("admin", "admin-secret")
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusSeeOther {
+		t.Errorf("AdminPageHandler returned wrong status code for an alias action: got %v want %v", status, http.StatusSeeOther)
+	}
+	if alias, err := service.Alias("quality"); err != nil || len(alias.Targets) != 2 || alias.Targets[1].Weight != 10 {
+		t.Errorf("Expected weights from the dashboard, got %+v %v", alias, err)
+	}
+
+	req, _ = http.NewRequest("POST", "/admin/aliases/quality", strings.NewReader("models=gpt-4&csrf_token="+token))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth("admin", "admin-secret")
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Errorf("AdminPageHandler returned wrong status code for malformed weights: got %v want %v", status, http.StatusBadRequest)
+	}
+
+	req, _ = http.NewRequest("DELETE", "/api/admin/aliases/quality", nil)
+	req.Header.Set("X-Admin-Key", "admin-secret")
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusNoContent {
+		t.Errorf("AliasAdminHandler returned wrong status code for a reset: got %v want %v", status, http.StatusNoContent)
+	}
+
+	req, _ = http.NewRequest("GET", "/api/admin/aliases/quality", nil)
+	req.Header.Set("X-Admin-Key", "admin-secret")
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK || !strings.Contains(rr.Body.String(), `"source":"config"`) {
+		t.Errorf("Expected config weights after reset, got %v %s", status, rr.Body.String())
+	}
+
+	req, _ = http.NewRequest("GET", "/api/admin/aliases/no-such-alias", nil)
+	req.Header.Set("X-Admin-Key", "admin-secret")
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusNotFound {
+		t.Errorf("AliasAdminHandler returned wrong status code for an unknown alias: got %v want %v", status, http.StatusNotFound)
+	}
+}
+
+func TestHistoryAPIHandler(t *testing.T) {
+	// Record a translation so history is not empty
+	service := createTestTranslatorService()
+	handler := NewAPIHandler(service)
+
+	jsonData, _ := json.Marshal(models.TranslationRequest{Text: "Hello, world!", Model: "gpt-3.5"})
+	req, err := http.NewRequest("POST", "/api/translate", bytes.NewBuffer(jsonData))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	// Query the history API
+	req, err = http.NewRequest("GET", "/api/history?model=gpt-3.5&q=hello", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+	NewHistoryAPIHandler(service.HistoryStore()).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("HistoryAPIHandler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+
+	var page models.HistoryPage
+	if err := json.NewDecoder(rr.Body).Decode(&page); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if page.Total != 1 || page.Entries[0].Original != "Hello, world!" {
+		t.Errorf("HistoryAPIHandler returned unexpected page: %+v", page)
+	}
+}
+
+func TestHistoryAPIHandler_InvalidQuery(t *testing.T) {
+	service := createTestTranslatorService()
+
+	for _, query := range []string{"page=0", "page_size=abc", "from=yesterday"} {
+		req, err := http.NewRequest("GET", "/api/history?"+query, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		NewHistoryAPIHandler(service.HistoryStore()).ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusBadRequest {
+			t.Errorf("HistoryAPIHandler returned wrong status code for %q: got %v want %v", query, status, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestCompareAPIHandler(t *testing.T) {
+	requestData := models.ComparisonRequest{
+		Text:   "Hello, world!",
+		Models: []string{"gpt-3.5", "claude"},
+	}
+	jsonData, _ := json.Marshal(requestData)
+
+	req, err := http.NewRequest("POST", "/api/translate/compare", bytes.NewBuffer(jsonData))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	rr := httptest.NewRecorder()
+	service := createTestTranslatorService()
+	NewCompareAPIHandler(service).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("CompareAPIHandler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+
+	var response models.ComparisonResponse
+	if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(response.Results) != 2 {
+		t.Errorf("CompareAPIHandler returned %d results, want 2", len(response.Results))
+	}
+
+	// The comparison is stored so votes can be checked against it
+	comparison, err := service.VoteStore().GetComparison(context.Background(), "", response.ID)
+	if err != nil || comparison.Original != "Hello, world!" || len(comparison.Results) != 2 {
+		t.Errorf("Expected the comparison to be stored, got %+v (%v)", comparison, err)
+	}
+}
+
+func TestCompareAPIHandler_SingleModel(t *testing.T) {
+	jsonData, _ := json.Marshal(models.ComparisonRequest{Text: "Hello, world!", Models: []string{"gpt-3.5"}})
+
+	req, err := http.NewRequest("POST", "/api/translate/compare", bytes.NewBuffer(jsonData))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	rr := httptest.NewRecorder()
+	NewCompareAPIHandler(createTestTranslatorService()).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Errorf("CompareAPIHandler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+}
+
+func TestVoteHandler(t *testing.T) {
+	voteStore := storage.NewMemoryVoteStore()
+	handler := NewVoteHandler(voteStore)
+
+	// Store a comparison in which one model failed
+	err := voteStore.SaveComparison(context.Background(), &models.Comparison{
+		ID:       "abc",
+		Original: "Hello",
+		Results: []models.ComparisonResult{
+			{Model: "gpt-4", Translation: "你好"},
+			{Model: "claude", Translation: "您好"},
+			{Model: "llama", Error: "context deadline exceeded"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A vote for a model that was not compared is rejected, whatever candidates the client lists
+	jsonData, _ := json.Marshal(models.Vote{ComparisonID: "abc", Model: "qwen-max", Candidates: []string{"gpt-4", "qwen-max"}})
+	req, _ := http.NewRequest("POST", "/api/translate/compare/votes", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Errorf("VoteHandler returned wrong status code for invalid vote: got %v want %v", status, http.StatusBadRequest)
+	}
+
+	// So is a vote for a model that failed to translate
+	req, _ = http.NewRequest("POST", "/api/translate/compare/votes", strings.NewReader(`{"comparison_id":"abc","model":"llama"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Errorf("VoteHandler returned wrong status code for a failed model: got %v want %v", status, http.StatusBadRequest)
+	}
+
+	// A forged comparison ID is rejected
+	req, _ = http.NewRequest("POST", "/api/translate/compare/votes", strings.NewReader(`{"comparison_id":"forged","model":"gpt-4","candidates":["gpt-4","claude"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusNotFound {
+		t.Errorf("VoteHandler returned wrong status code for a forged comparison: got %v want %v", status, http.StatusNotFound)
+	}
+
+	// Another tenant cannot vote on the comparison
+	req, _ = http.NewRequest("POST", "/api/translate/compare/votes", strings.NewReader(`{"comparison_id":"abc","model":"gpt-4"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req = req.WithContext(services.WithTenant(req.Context(), &models.Tenant{ID: "acme"}))
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusNotFound {
+		t.Errorf("VoteHandler returned wrong status code for another tenant's comparison: got %v want %v", status, http.StatusNotFound)
+	}
+
+	// A form vote is stored and redirects back to the comparison page
+	form := strings.NewReader("comparison_id=abc&model=claude")
+	req, _ = http.NewRequest("POST", "/compare/vote", form)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.RemoteAddr = "203.0.113.7:51234"
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusSeeOther {
+		t.Errorf("VoteHandler returned wrong status code for form vote: got %v want %v", status, http.StatusSeeOther)
+	}
+
+	// The same voter cannot vote on the comparison again
+	req, _ = http.NewRequest("POST", "/api/translate/compare/votes", strings.NewReader(`{"comparison_id":"abc","model":"gpt-4"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.RemoteAddr = "203.0.113.7:51235"
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusConflict {
+		t.Errorf("VoteHandler returned wrong status code for a repeated vote: got %v want %v", status, http.StatusConflict)
+	}
+
+	// The tally reflects the stored vote
+	req, _ = http.NewRequest("GET", "/api/translate/compare/votes", nil)
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	if !strings.Contains(rr.Body.String(), `"claude":1`) {
+		t.Errorf("VoteHandler returned unexpected tally: %v", rr.Body.String())
+	}
+}
+
+func TestSessionAPIHandler(t *testing.T) {
+	service := createTestTranslatorService()
+
+	// Route requests the same way the server does
+	mux := http.NewServeMux()
+	sessionHandler := NewSessionAPIHandler(service)
+	mux.HandleFunc("/api/sessions", sessionHandler)
+	mux.HandleFunc("/api/sessions/{id}", sessionHandler)
+	mux.HandleFunc("/api/sessions/{id}/translate", NewAPIHandler(service))
+
+	// Create a session with a default model and a known term
+	jsonData, _ := json.Marshal(models.SessionRequest{Model: "gpt-3.5", Terms: map[string]string{"Invoice": "发票"}})
+	req, _ := http.NewRequest("POST", "/api/sessions", bytes.NewBuffer(jsonData))
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusCreated {
+		t.Fatalf("SessionAPIHandler returned wrong status code: got %v want %v", status, http.StatusCreated)
+	}
+
+	var session models.Session
+	if err := json.Unmarshal(rr.Body.Bytes(), &session); err != nil || session.ID == "" {
+		t.Fatalf("SessionAPIHandler returned unexpected body: %v", rr.Body.String())
+	}
+
+	// Translate within the session without naming a model
+	req, _ = http.NewRequest("POST", "/api/sessions/"+session.ID+"/translate", strings.NewReader(`{"text":"Hello"}`))
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("Session translation returned wrong status code: got %v want %v: %s", status, http.StatusOK, rr.Body.String())
+	}
+
+	// The session records the translation
+	req, _ = http.NewRequest("GET", "/api/sessions/"+session.ID, nil)
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	if err := json.Unmarshal(rr.Body.Bytes(), &session); err != nil || len(session.Turns) != 1 {
+		t.Errorf("Expected one recorded turn, got %v", rr.Body.String())
+	}
+
+	// Ending the session makes it unavailable
+	req, _ = http.NewRequest("DELETE", "/api/sessions/"+session.ID, nil)
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusNoContent {
+		t.Errorf("SessionAPIHandler returned wrong status code for delete: got %v want %v", status, http.StatusNoContent)
+	}
+
+	req, _ = http.NewRequest("POST", "/api/sessions/"+session.ID+"/translate", strings.NewReader(`{"text":"Hello"}`))
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Errorf("Translation in an ended session returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+
+	req, _ = http.NewRequest("GET", "/api/sessions/"+session.ID, nil)
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusNotFound {
+		t.Errorf("SessionAPIHandler returned wrong status code for ended session: got %v want %v", status, http.StatusNotFound)
+	}
+}
+
+func TestReviewAPIHandler(t *testing.T) {
+	service := createTestTranslatorService()
+
+	// Route requests the same way the server does
+	mux := http.NewServeMux()
+	reviewHandler := NewReviewAPIHandler(service)
+	mux.HandleFunc("/api/reviews", reviewHandler)
+	mux.HandleFunc("/api/reviews/{id}", reviewHandler)
+	mux.HandleFunc("/api/translate", NewAPIHandler(service))
+
+	// The review API is unavailable until the workflow is enabled
+	req, _ := http.NewRequest("GET", "/api/reviews", nil)
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusNotFound {
+		t.Errorf("ReviewAPIHandler returned wrong status code while disabled: got %v want %v", status, http.StatusNotFound)
+	}
+	service.SetReviewStore(storage.NewMemoryReviewStore())
+
+	// Translate to queue a machine translation for review
+	req, _ = http.NewRequest("POST", "/api/translate", strings.NewReader(`{"text":"Hello","model":"gpt-3.5"}`))
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	var response models.TranslationResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil || response.ReviewID == 0 {
+		t.Fatalf("Expected a review item, got %v", rr.Body.String())
+	}
+
+	req, _ = http.NewRequest("GET", "/api/reviews?state=machine", nil)
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	var page models.ReviewPage
+	if err := json.Unmarshal(rr.Body.Bytes(), &page); err != nil || page.Total != 1 {
+		t.Errorf("Expected one item awaiting review, got %v", rr.Body.String())
+	}
+
+	// Review actions are refused until reviewers are configured, and without a valid key
+	id := strconv.FormatInt(response.ReviewID, 10)
+	req, _ = http.NewRequest("POST", "/api/reviews/"+id, strings.NewReader(`{"action":"approve","reviewer":"alice","translation":"你好"}`))
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusForbidden {
+		t.Errorf("ReviewAPIHandler returned wrong status code without reviewers: got %v want %v", status, http.StatusForbidden)
+	}
+
+	cfg := *service.Config()
+	cfg.ReviewReviewers = map[string]string{"alice": "alice-key"}
+	service.Reload(&cfg, nil)
+
+	req, _ = http.NewRequest("POST", "/api/reviews/"+id, strings.NewReader(`{"action":"approve","reviewer":"alice","translation":"你好"}`))
+	req.Header.Set("X-Reviewer-Key", "wrong")
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusUnauthorized {
+		t.Errorf("ReviewAPIHandler returned wrong status code for a wrong key: got %v want %v", status, http.StatusUnauthorized)
+	}
+
+	// Approve the translation with an edit; the key, not the body, names the reviewer
+	req, _ = http.NewRequest("POST", "/api/reviews/"+id, strings.NewReader(`{"action":"approve","reviewer":"mallory","translation":"你好"}`))
+	req.Header.Set("X-Reviewer-Key", "alice-key")
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("ReviewAPIHandler returned wrong status code: got %v want %v: %s", status, http.StatusOK, rr.Body.String())
+	}
+	var approved models.ReviewItem
+	if err := json.Unmarshal(rr.Body.Bytes(), &approved); err != nil || approved.Reviewer != "alice" {
+		t.Errorf("Expected the action to be recorded for alice, got %v", rr.Body.String())
+	}
+
+	// Approved items cannot be approved again
+	req, _ = http.NewRequest("POST", "/api/reviews/"+id, strings.NewReader(`{"action":"approve"}`))
+	req.Header.Set("X-Reviewer-Key", "alice-key")
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusConflict {
+		t.Errorf("ReviewAPIHandler returned wrong status code for conflict: got %v want %v", status, http.StatusConflict)
+	}
+
+	// The item carries its audit trail
+	req, _ = http.NewRequest("GET", "/api/reviews/"+id, nil)
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	var item models.ReviewItem
+	if err := json.Unmarshal(rr.Body.Bytes(), &item); err != nil || item.State != models.ReviewApproved || len(item.Events) != 2 {
+		t.Errorf("Unexpected review item: %v", rr.Body.String())
+	}
+
+	req, _ = http.NewRequest("GET", "/api/reviews/999", nil)
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	if status := rr.Code; status != http.StatusNotFound {
+		t.Errorf("ReviewAPIHandler returned wrong status code for missing item: got %v want %v", status, http.StatusNotFound)
+	}
+
+	// Identical requests receive the approved translation
+	req, _ = http.NewRequest("POST", "/api/translate", strings.NewReader(`{"text":"Hello","model":"gpt-3.5"}`))
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil || response.Translation != "你好" {
+		t.Errorf("Expected the approved translation, got %v", rr.Body.String())
+	}
+}
+
+func TestReviewPageHandler(t *testing.T) {
+	service := services.NewTranslatorService(&config.Config{
+		ServerPort:      "8080",
+		Timeout:         30,
+		ReviewReviewers: map[string]string{"alice": "alice-key"},
+	})
+	service.SetReviewStore(storage.NewMemoryReviewStore())
+	response, err := service.Translate(context.Background(), &models.TranslationRequest{Text: "Hello", Model: "gpt-3.5"})
+	if err != nil || response.ReviewID == 0 {
+		t.Fatalf("Expected a review item, got %v %v", response, err)
+	}
+
+	handler := &ReviewPageHandler{translatorService: service, csrfSecret: []byte("test-secret")}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/review/{id}", handler.ServeHTTP)
+	id := strconv.FormatInt(response.ReviewID, 10)
+
+	post := func(token string) *httptest.ResponseRecorder {
+		form := url.Values{"action": {"start"}, "reviewer": {"mallory"}, "csrf_token": {token}}
+		req, _ := http.NewRequest("POST", "/review/"+id, strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.SetBasicAuth("", "alice-key")
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, req)
+		return rr
+	}
+
+	// Browsers are asked for the reviewer key
+	req, _ := http.NewRequest("GET", "/review/"+id, nil)
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	if rr.Code != http.StatusUnauthorized || rr.Header().Get("WWW-Authenticate") == "" {
+		t.Errorf("Expected a basic authentication challenge, got %v", rr.Code)
+	}
+
+	// Forms without the token rendered for the reviewer are refused
+	if rr := post(""); rr.Code != http.StatusForbidden {
+		t.Errorf("Expected a form without token to be refused, got %v", rr.Code)
+	}
+	if rr := post(handler.csrfToken("mallory", response.ReviewID)); rr.Code != http.StatusForbidden {
+		t.Errorf("Expected a token of another reviewer to be refused, got %v", rr.Code)
+	}
+
+	if rr := post(handler.csrfToken("alice", response.ReviewID)); rr.Code != http.StatusSeeOther {
+		t.Fatalf("Expected the action to be applied, got %v: %s", rr.Code, rr.Body.String())
+	}
+	item, err := service.GetReview(context.Background(), response.ReviewID)
+	if err != nil || item.State != models.ReviewInReview || item.Events[len(item.Events)-1].Reviewer != "alice" {
+		t.Errorf("Expected a review started by alice, got %+v %v", item, err)
+	}
+}
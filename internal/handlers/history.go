@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"translator-service/internal/models"
+	"translator-service/internal/services"
+	"translator-service/internal/storage"
+)
+
+// HistoryAPIHandler handles REST API requests for translation history
+type HistoryAPIHandler struct {
+	historyStore storage.HistoryStore
+}
+
+func NewHistoryAPIHandler(historyStore storage.HistoryStore) http.HandlerFunc {
+	handler := &HistoryAPIHandler{
+		historyStore: historyStore,
+	}
+
+	return handler.ServeHTTP
+}
+
+func (h *HistoryAPIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Parse query parameters
+	query, err := parseHistoryQuery(r.URL.Query())
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error(), err.Error())
+		return
+	}
+
+	// Search the tenant's history
+	query.Tenant = services.TenantFromContext(r.Context())
+	page, err := h.historyStore.Search(r.Context(), query)
+	if err != nil {
+		log.Printf("History search error: %v", err)
+		writeJSONError(w, http.StatusInternalServerError, "Failed to search translation history", err.Error())
+		return
+	}
+
+	// Send JSON response
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(page); err != nil {
+		log.Printf("Error encoding JSON response: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+}
+
+// HistoryPageHandler serves the translation history web page
+type HistoryPageHandler struct {
+	historyStore storage.HistoryStore
+}
+
+func NewHistoryPageHandler(historyStore storage.HistoryStore) http.HandlerFunc {
+	handler := &HistoryPageHandler{
+		historyStore: historyStore,
+	}
+
+	return handler.ServeHTTP
+}
+
+func (h *HistoryPageHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Parse query parameters
+	values := r.URL.Query()
+	query, err := parseHistoryQuery(values)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid search: %s", err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	// Search history
+	query.Tenant = services.TenantFromContext(r.Context())
+	page, err := h.historyStore.Search(r.Context(), query)
+	if err != nil {
+		log.Printf("History search error: %v", err)
+		http.Error(w, "Failed to search translation history", http.StatusInternalServerError)
+		return
+	}
+
+	// Render history template
+	if historyTemplate != nil {
+		data := struct {
+			Page     *models.HistoryPage
+			Model    string
+			From     string
+			To       string
+			Search   string
+			PrevLink string
+			NextLink string
+		}{
+			Page:     page,
+			Model:    values.Get("model"),
+			From:     values.Get("from"),
+			To:       values.Get("to"),
+			Search:   values.Get("q"),
+			PrevLink: historyPageLink(values, page.Page-1, page.Page > 1),
+			NextLink: historyPageLink(values, page.Page+1, page.Page*page.PageSize < page.Total),
+		}
+
+		if err := historyTemplate.Execute(w, data); err != nil {
+			log.Printf("Error rendering history template: %v", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+	} else {
+		// Fallback for testing or when templates are not available
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(page); err != nil {
+			log.Printf("Error encoding JSON response: %v", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// parseHistoryQuery builds a history query from URL query parameters
+func parseHistoryQuery(values url.Values) (models.HistoryQuery, error) {
+	query := models.HistoryQuery{
+		Model:  strings.TrimSpace(values.Get("model")),
+		Search: strings.TrimSpace(values.Get("q")),
+	}
+
+	var err error
+	if query.Page, err = parsePositiveInt(values.Get("page")); err != nil {
+		return query, fmt.Errorf("page must be a positive integer")
+	}
+	if query.PageSize, err = parsePositiveInt(values.Get("page_size")); err != nil {
+		return query, fmt.Errorf("page_size must be a positive integer")
+	}
+	if query.From, err = parseHistoryDate(values.Get("from"), false); err != nil {
+		return query, fmt.Errorf("from must be a date (YYYY-MM-DD) or RFC 3339 timestamp")
+	}
+	if query.To, err = parseHistoryDate(values.Get("to"), true); err != nil {
+		return query, fmt.Errorf("to must be a date (YYYY-MM-DD) or RFC 3339 timestamp")
+	}
+
+	return query, nil
+}
+
+// parsePositiveInt parses an optional positive integer, returning zero when empty
+func parsePositiveInt(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("invalid positive integer: %s", value)
+	}
+	return n, nil
+}
+
+// parseHistoryDate parses an optional date or timestamp. A plain date used as an
+// upper bound covers the whole day.
+func parseHistoryDate(value string, endOfDay bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+// historyPageLink returns the link to another page of the current search, or an empty string
+func historyPageLink(values url.Values, page int, ok bool) string {
+	if !ok {
+		return ""
+	}
+	link := url.Values{}
+	for key, value := range values {
+		link[key] = value
+	}
+	link.Set("page", strconv.Itoa(page))
+	return "/history?" + link.Encode()
+}
@@ -0,0 +1,357 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"translator-service/internal/config"
+	"translator-service/internal/models"
+	"translator-service/internal/services"
+)
+
+// reviewerKeyHeader carries the key of a reviewer
+const reviewerKeyHeader = "X-Reviewer-Key"
+
+// adminReviewer is the reviewer name recorded for actions taken with the admin key
+const adminReviewer = "admin"
+
+// authenticateReviewer returns the reviewer a request is authenticated as. The
+// key is sent in the X-Reviewer-Key or X-Admin-Key header or, for browsers, as
+// the password of HTTP basic authentication; the admin key acts as the reviewer
+// admin. configured reports whether any reviewer credentials exist.
+func authenticateReviewer(r *http.Request, cfg *config.Config) (reviewer string, ok, configured bool) {
+	key := r.Header.Get(reviewerKeyHeader)
+	if key == "" {
+		key = r.Header.Get(adminKeyHeader)
+	}
+	if key == "" {
+		_, key, _ = r.BasicAuth()
+	}
+
+	configured = cfg.AdminKey != "" || len(cfg.ReviewReviewers) > 0
+	if key == "" {
+		return "", false, configured
+	}
+	if cfg.AdminKey != "" && subtle.ConstantTimeCompare([]byte(key), []byte(cfg.AdminKey)) == 1 {
+		return adminReviewer, true, configured
+	}
+	for name, reviewerKey := range cfg.ReviewReviewers {
+		if subtle.ConstantTimeCompare([]byte(key), []byte(reviewerKey)) == 1 {
+			return name, true, configured
+		}
+	}
+	return "", false, configured
+}
+
+// ReviewAPIHandler lists review items and applies reviewer actions through the REST API
+type ReviewAPIHandler struct {
+	translatorService *services.TranslatorService
+}
+
+func NewReviewAPIHandler(translatorService *services.TranslatorService) http.HandlerFunc {
+	handler := &ReviewAPIHandler{
+		translatorService: translatorService,
+	}
+
+	return handler.ServeHTTP
+}
+
+func (h *ReviewAPIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.translatorService.ReviewStore() == nil {
+		writeJSONError(w, http.StatusNotFound, "Review workflow is disabled", "set review.enabled to use the review workflow")
+		return
+	}
+
+	// List review items
+	if r.PathValue("id") == "" {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		query, err := parseReviewQuery(r.URL.Query())
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, err.Error(), err.Error())
+			return
+		}
+
+		page, err := h.translatorService.ListReviews(r.Context(), query)
+		if err != nil {
+			log.Printf("Review list error: %v", err)
+			writeJSONError(w, http.StatusInternalServerError, "Failed to list review items", err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, page)
+		return
+	}
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeJSONError(w, http.StatusNotFound, "Review item not found", "invalid review item id")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		item, err := h.translatorService.GetReview(r.Context(), id)
+		if err != nil {
+			writeJSONError(w, getErrorCode(err), getErrorMessage(err), err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, item)
+	case http.MethodPost:
+		reviewer, ok, configured := authenticateReviewer(r, h.translatorService.Config())
+		if !configured {
+			writeJSONError(w, http.StatusForbidden, "Review actions are disabled", "set review.reviewers or admin.key to review translations")
+			return
+		}
+		if !ok {
+			writeJSONError(w, http.StatusUnauthorized, "A valid reviewer key is required", "send the reviewer key in the "+reviewerKeyHeader+" header")
+			return
+		}
+
+		var action models.ReviewAction
+		if err := json.NewDecoder(r.Body).Decode(&action); err != nil {
+			http.Error(w, "Invalid JSON request", http.StatusBadRequest)
+			return
+		}
+		// The action is recorded under the authenticated reviewer, whatever the body says
+		action.Reviewer = reviewer
+
+		item, err := h.translatorService.ReviewTranslation(r.Context(), id, &action)
+		if err != nil {
+			log.Printf("Review error: %v", err)
+			writeJSONError(w, getErrorCode(err), getErrorMessage(err), err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, item)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// ReviewPageHandler serves the review queue and the page for reviewing a single item
+type ReviewPageHandler struct {
+	translatorService *services.TranslatorService
+	// csrfSecret signs the tokens of the review forms
+	csrfSecret []byte
+}
+
+func NewReviewPageHandler(translatorService *services.TranslatorService) http.HandlerFunc {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		log.Fatalf("Failed to generate review form secret: %v", err)
+	}
+
+	handler := &ReviewPageHandler{
+		translatorService: translatorService,
+		csrfSecret:        secret,
+	}
+
+	return handler.ServeHTTP
+}
+
+func (h *ReviewPageHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.translatorService.ReviewStore() == nil {
+		http.Error(w, "Review workflow is disabled", http.StatusNotFound)
+		return
+	}
+
+	reviewer, ok, configured := authenticateReviewer(r, h.translatorService.Config())
+	if !configured {
+		http.Error(w, "Review pages are disabled until review.reviewers or admin.key is set", http.StatusForbidden)
+		return
+	}
+	if !ok {
+		// Browsers ask for the key; any user name is accepted
+		w.Header().Set("WWW-Authenticate", `Basic realm="Translator review", charset="UTF-8"`)
+		http.Error(w, "A valid reviewer key is required", http.StatusUnauthorized)
+		return
+	}
+
+	if r.PathValue("id") == "" {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		h.serveQueue(w, r)
+		return
+	}
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		h.serveItem(w, r, id, reviewer, http.StatusOK, "")
+	case http.MethodPost:
+		h.serveAction(w, r, id, reviewer)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// serveQueue renders the list of review items, awaiting review by default
+func (h *ReviewPageHandler) serveQueue(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+	if !values.Has("state") {
+		values.Set("state", models.ReviewMachine)
+	}
+
+	query, err := parseReviewQuery(values)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid filter: %s", err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	page, err := h.translatorService.ListReviews(r.Context(), query)
+	if err != nil {
+		log.Printf("Review list error: %v", err)
+		http.Error(w, "Failed to list review items", http.StatusInternalServerError)
+		return
+	}
+
+	if reviewTemplate == nil {
+		// Fallback for testing or when templates are not available
+		writeJSON(w, http.StatusOK, page)
+		return
+	}
+
+	data := struct {
+		Page     *models.ReviewPage
+		State    string
+		States   []string
+		PrevLink string
+		NextLink string
+	}{
+		Page:     page,
+		State:    query.State,
+		States:   []string{models.ReviewMachine, models.ReviewInReview, models.ReviewApproved, models.ReviewRejected},
+		PrevLink: reviewPageLink(values, page.Page-1, page.Page > 1),
+		NextLink: reviewPageLink(values, page.Page+1, page.Page*page.PageSize < page.Total),
+	}
+	if err := reviewTemplate.Execute(w, data); err != nil {
+		log.Printf("Error rendering review template: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
+// serveItem renders a review item with its audit trail and the review form,
+// showing message when an action could not be applied
+func (h *ReviewPageHandler) serveItem(w http.ResponseWriter, r *http.Request, id int64, reviewer string, status int, message string) {
+	item, err := h.translatorService.GetReview(r.Context(), id)
+	if err != nil {
+		http.Error(w, getErrorMessage(err), getErrorCode(err))
+		return
+	}
+
+	if reviewItemTemplate == nil {
+		// Fallback for testing or when templates are not available
+		writeJSON(w, status, item)
+		return
+	}
+
+	data := struct {
+		Item      *models.ReviewItem
+		Reviewer  string
+		CSRFToken string
+		Message   string
+	}{
+		Item:      item,
+		Reviewer:  reviewer,
+		CSRFToken: h.csrfToken(reviewer, id),
+		Message:   message,
+	}
+	w.WriteHeader(status)
+	if err := reviewItemTemplate.Execute(w, data); err != nil {
+		log.Printf("Error rendering review item template: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
+// serveAction applies an action submitted from the review form and redirects back to the item
+func (h *ReviewPageHandler) serveAction(w http.ResponseWriter, r *http.Request, id int64, reviewer string) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	// Browsers resend basic credentials to any page, so only forms rendered for
+	// this reviewer and item are accepted
+	if !sameOrigin(r) || !hmac.Equal([]byte(r.PostFormValue("csrf_token")), []byte(h.csrfToken(reviewer, id))) {
+		http.Error(w, "Invalid or missing form token; reload the page and try again", http.StatusForbidden)
+		return
+	}
+
+	action := &models.ReviewAction{
+		Action:   r.PostFormValue("action"),
+		Reviewer: reviewer,
+		Comment:  r.PostFormValue("comment"),
+	}
+	// The form always carries the editable translation; only edits and approvals use it
+	if action.Action == models.ReviewActionEdit || action.Action == models.ReviewActionApprove {
+		action.Translation = r.PostFormValue("translation")
+	}
+
+	if _, err := h.translatorService.ReviewTranslation(r.Context(), id, action); err != nil {
+		log.Printf("Review error: %v", err)
+		h.serveItem(w, r, id, reviewer, getErrorCode(err), getErrorMessage(err))
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/review/%d", id), http.StatusSeeOther)
+}
+
+// csrfToken returns the token of the review form of an item rendered for a reviewer
+func (h *ReviewPageHandler) csrfToken(reviewer string, id int64) string {
+	mac := hmac.New(sha256.New, h.csrfSecret)
+	fmt.Fprintf(mac, "%s\x00%d", reviewer, id)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// parseReviewQuery converts URL query parameters into a review query
+func parseReviewQuery(values url.Values) (models.ReviewQuery, error) {
+	query := models.ReviewQuery{State: strings.TrimSpace(values.Get("state"))}
+
+	switch query.State {
+	case "", models.ReviewMachine, models.ReviewInReview, models.ReviewApproved, models.ReviewRejected:
+	default:
+		return query, fmt.Errorf("state must be one of: machine, in_review, approved, rejected")
+	}
+
+	var err error
+	if query.Page, err = parsePositiveInt(values.Get("page")); err != nil {
+		return query, fmt.Errorf("page must be a positive integer")
+	}
+	if query.PageSize, err = parsePositiveInt(values.Get("page_size")); err != nil {
+		return query, fmt.Errorf("page_size must be a positive integer")
+	}
+
+	return query, nil
+}
+
+// reviewPageLink returns the link to another page of the review queue, or an empty string
+func reviewPageLink(values url.Values, page int, ok bool) string {
+	if !ok {
+		return ""
+	}
+	link := url.Values{}
+	for key, value := range values {
+		link[key] = value
+	}
+	link.Set("page", strconv.Itoa(page))
+	return "/review?" + link.Encode()
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"translator-service/internal/models"
+	"translator-service/internal/services"
+)
+
+// SessionAPIHandler creates, returns and ends multi-turn translation sessions.
+// Translations within a session are handled by APIHandler.
+type SessionAPIHandler struct {
+	translatorService *services.TranslatorService
+}
+
+func NewSessionAPIHandler(translatorService *services.TranslatorService) http.HandlerFunc {
+	handler := &SessionAPIHandler{
+		translatorService: translatorService,
+	}
+
+	return handler.ServeHTTP
+}
+
+func (h *SessionAPIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	switch {
+	case id == "" && r.Method == http.MethodPost:
+		h.create(w, r)
+	case id != "" && r.Method == http.MethodGet:
+		session, ok := h.translatorService.GetSession(r.Context(), id)
+		if !ok {
+			writeJSONError(w, http.StatusNotFound, "Session not found", "unknown or expired session: "+id)
+			return
+		}
+		writeJSON(w, http.StatusOK, session)
+	case id != "" && r.Method == http.MethodDelete:
+		if !h.translatorService.DeleteSession(r.Context(), id) {
+			writeJSONError(w, http.StatusNotFound, "Session not found", "unknown or expired session: "+id)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// create starts a session from a JSON request
+func (h *SessionAPIHandler) create(w http.ResponseWriter, r *http.Request) {
+	// Decode JSON request; an empty body starts a session without defaults
+	var req models.SessionRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid JSON request", http.StatusBadRequest)
+			return
+		}
+	}
+	req.Model = strings.TrimSpace(req.Model)
+
+	session, err := h.translatorService.CreateSession(r.Context(), &req)
+	if err != nil {
+		log.Printf("Session error: %v", err)
+		writeJSONError(w, getErrorCode(err), getErrorMessage(err), err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, session)
+}
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"translator-service/internal/models"
+	"translator-service/internal/services"
+)
+
+const (
+	// tenantHeader names the tenant a request is made for
+	tenantHeader = "X-Tenant-ID"
+
+	// apiKeyHeader carries a tenant's API key for clients that cannot send a bearer token
+	apiKeyHeader = "X-API-Key"
+)
+
+// TenantMiddleware identifies the tenant of every request from its API key or
+// X-Tenant-ID header and adds it to the request context
+type TenantMiddleware struct {
+	translatorService *services.TranslatorService
+	next              http.Handler
+}
+
+func NewTenantMiddleware(translatorService *services.TranslatorService, next http.Handler) http.Handler {
+	return &TenantMiddleware{
+		translatorService: translatorService,
+		next:              next,
+	}
+}
+
+func (m *TenantMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	tenant, err := m.translatorService.ResolveTenant(r.Context(), apiKeyFromRequest(r), strings.TrimSpace(r.Header.Get(tenantHeader)))
+	if err != nil {
+		log.Printf("Tenant identification failed: %v", err)
+
+		status, message := http.StatusServiceUnavailable, "Tenant could not be identified"
+		var accessErr *services.TenantAccessError
+		if errors.Is(err, services.ErrInvalidAPIKey) || errors.Is(err, services.ErrAPIKeyRequired) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			status, message = http.StatusUnauthorized, "A valid API key is required"
+		} else if errors.As(err, &accessErr) {
+			status, message = http.StatusForbidden, tenantAccessMessage(accessErr)
+		}
+
+		if strings.HasPrefix(r.URL.Path, "/api/") {
+			writeJSONError(w, status, message, err.Error())
+		} else {
+			http.Error(w, message, status)
+		}
+		return
+	}
+
+	m.next.ServeHTTP(w, r.WithContext(services.WithTenant(r.Context(), tenant)))
+}
+
+// apiKeyFromRequest returns the API key sent as a bearer token or in the X-API-Key header
+func apiKeyFromRequest(r *http.Request) string {
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		return strings.TrimSpace(token)
+	}
+	return strings.TrimSpace(r.Header.Get(apiKeyHeader))
+}
+
+// tenantAccessMessage returns a user-friendly message for a tenant access error
+func tenantAccessMessage(err *services.TenantAccessError) string {
+	if err.Disabled {
+		return "Tenant is disabled"
+	}
+	if err.Provider.Name == "" {
+		return "Unknown tenant"
+	}
+	return "Selected translation model is not allowed for this tenant"
+}
+
+// TenantAdminHandler creates, lists and disables tenants through the admin API
+type TenantAdminHandler struct {
+	translatorService *services.TranslatorService
+}
+
+func NewTenantAdminHandler(translatorService *services.TranslatorService) http.HandlerFunc {
+	handler := &TenantAdminHandler{
+		translatorService: translatorService,
+	}
+
+	return handler.ServeHTTP
+}
+
+func (h *TenantAdminHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !authorizeAdminAPI(w, r, h.translatorService) {
+		return
+	}
+
+	id := r.PathValue("id")
+	switch {
+	case id == "" && r.Method == http.MethodGet:
+		tenants, err := h.translatorService.ListTenants(r.Context())
+		if err != nil {
+			log.Printf("Tenant list error: %v", err)
+			writeJSONError(w, http.StatusInternalServerError, "Failed to list tenants", err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"tenants": tenants})
+	case id == "" && r.Method == http.MethodPost:
+		var req models.TenantRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid JSON request", http.StatusBadRequest)
+			return
+		}
+
+		created, err := h.translatorService.CreateTenant(r.Context(), &req)
+		if err != nil {
+			log.Printf("Tenant error: %v", err)
+			writeJSONError(w, getErrorCode(err), getErrorMessage(err), err.Error())
+			return
+		}
+		writeJSON(w, http.StatusCreated, created)
+	case id != "" && r.Method == http.MethodGet:
+		tenant, err := h.translatorService.Tenant(r.Context(), id)
+		if err != nil {
+			writeJSONError(w, getErrorCode(err), getErrorMessage(err), err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, tenant)
+	case id != "" && r.Method == http.MethodPatch:
+		var update models.TenantUpdate
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			http.Error(w, "Invalid JSON request", http.StatusBadRequest)
+			return
+		}
+
+		tenant, err := h.translatorService.UpdateTenant(r.Context(), id, &update)
+		if err != nil {
+			log.Printf("Tenant error: %v", err)
+			writeJSONError(w, getErrorCode(err), getErrorMessage(err), err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, tenant)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
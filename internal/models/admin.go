@@ -0,0 +1,80 @@
+package models
+
+import "time"
+
+// Provider health states, derived from the outcome of recent calls
+const (
+	ProviderHealthy  = "healthy"
+	ProviderDegraded = "degraded"
+	ProviderDown     = "down"
+	ProviderUnknown  = "unknown"
+)
+
+// AdminStatus is the state of the running service shown on the admin dashboard
+type AdminStatus struct {
+	StartedAt    time.Time        `json:"started_at"`
+	Models       []ModelStatus    `json:"models"`
+	Aliases      []ModelAlias     `json:"aliases"`
+	Providers    []ProviderHealth `json:"providers"`
+	Volume       RequestVolume    `json:"volume"`
+	RecentErrors []ErrorEvent     `json:"recent_errors"`
+}
+
+// ModelStatus describes a registered model and its requests since the service started
+type ModelStatus struct {
+	Name string `json:"name"`
+	// Mock is true for models that answer locally instead of calling a provider
+	Mock         bool   `json:"mock"`
+	Provider     string `json:"provider,omitempty"`
+	Endpoint     string `json:"endpoint,omitempty"`
+	Enabled      bool   `json:"enabled"`
+	Requests     int64  `json:"requests"`
+	Errors       int64  `json:"errors"`
+	AvgLatencyMs int64  `json:"avg_latency_ms"`
+	// P95LatencyMs is taken from the model's latest 100 successful requests
+	P95LatencyMs int64 `json:"p95_latency_ms"`
+	// Hedges counts the requests sent a second time because the provider was
+	// slow to answer, HedgeWins those the second request answered first
+	Hedges    int64 `json:"hedges"`
+	HedgeWins int64 `json:"hedge_wins"`
+}
+
+// ProviderHealth reports the outcome of the calls made to a provider endpoint
+type ProviderHealth struct {
+	Name     string `json:"name"`
+	Endpoint string `json:"endpoint"`
+	// Status is healthy, degraded or down depending on the consecutive failures
+	// of the latest calls, or unknown before the first call
+	Status              string     `json:"status"`
+	Calls               int64      `json:"calls"`
+	Failures            int64      `json:"failures"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	LastSuccess         *time.Time `json:"last_success,omitempty"`
+	LastFailure         *time.Time `json:"last_failure,omitempty"`
+	LastError           string     `json:"last_error,omitempty"`
+}
+
+// RequestVolume counts translation requests since the service started
+type RequestVolume struct {
+	Total    int64 `json:"total"`
+	Errors   int64 `json:"errors"`
+	LastHour int64 `json:"last_hour"`
+	// Coalesced counts the requests that shared the provider call of an
+	// identical request instead of making their own
+	Coalesced int64 `json:"coalesced"`
+	// PerMinute holds the requests of each of the last 60 minutes, oldest first
+	PerMinute []int64 `json:"per_minute"`
+}
+
+// ErrorEvent is a failed translation request
+type ErrorEvent struct {
+	Time    time.Time `json:"time"`
+	Model   string    `json:"model"`
+	Tenant  string    `json:"tenant,omitempty"`
+	Message string    `json:"message"`
+}
+
+// ModelUpdate is an admin request to enable or disable a model
+type ModelUpdate struct {
+	Enabled *bool `json:"enabled"`
+}
@@ -0,0 +1,30 @@
+package models
+
+// Sources of a model alias's weights
+const (
+	AliasSourceConfig = "config"
+	AliasSourceAdmin  = "admin"
+)
+
+// ModelAlias is a name such as "quality" that routes each request to one of
+// several models, in proportion to their weights
+type ModelAlias struct {
+	Name    string        `json:"name"`
+	Targets []AliasTarget `json:"targets"`
+	// Source is "config" for weights from the config file and "admin" for
+	// weights set through the admin API, which take precedence until reset
+	Source string `json:"source"`
+}
+
+// AliasTarget is a model an alias routes to and its share of the alias's requests
+type AliasTarget struct {
+	Model  string `json:"model"`
+	Weight int    `json:"weight"`
+	// Share is the weight as a percentage of the alias's total weight
+	Share float64 `json:"share"`
+}
+
+// AliasUpdate is an admin request to set the weights of an alias's models
+type AliasUpdate struct {
+	Models map[string]int `json:"models"`
+}
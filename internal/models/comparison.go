@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// ComparisonRequest represents a request to translate text with several models
+type ComparisonRequest struct {
+	Text   string   `json:"text"`
+	Models []string `json:"models"`
+}
+
+// ComparisonResult represents the outcome of a single model in a comparison
+type ComparisonResult struct {
+	Model       string      `json:"model"`
+	Translation string      `json:"translation,omitempty"`
+	LatencyMs   int64       `json:"latency_ms"`
+	Usage       *TokenUsage `json:"usage,omitempty"`
+	// PromptVersion is the prompt template used, as name@version
+	PromptVersion string           `json:"prompt_version,omitempty"`
+	Quality       *QualityEstimate `json:"quality,omitempty"`
+	PII           []PIIFinding     `json:"pii,omitempty"`
+	Error         string           `json:"error,omitempty"`
+}
+
+// ComparisonResponse represents the side-by-side results of a comparison
+type ComparisonResponse struct {
+	ID       string             `json:"id"`
+	Original string             `json:"original"`
+	Results  []ComparisonResult `json:"results"`
+}
+
+// Comparison is a stored comparison, kept so votes can be checked against
+// the models that were actually compared
+type Comparison struct {
+	ID        string             `json:"id"`
+	Tenant    string             `json:"tenant,omitempty"`
+	Original  string             `json:"original"`
+	Results   []ComparisonResult `json:"results"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+// Vote records a reviewer's preferred model for a comparison
+type Vote struct {
+	ID           int64     `json:"id"`
+	ComparisonID string    `json:"comparison_id"`
+	Original     string    `json:"original"`
+	Model        string    `json:"model"`
+	Candidates   []string  `json:"candidates"`
+	Voter        string    `json:"voter"`
+	Tenant       string    `json:"tenant,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// HistoryEntry represents a persisted translation
+type HistoryEntry struct {
+	ID          int64     `json:"id"`
+	Original    string    `json:"original"`
+	Translation string    `json:"translation"`
+	Model       string    `json:"model"`
+	Client      string    `json:"client"`
+	Tenant      string    `json:"tenant,omitempty"`
+	LatencyMs   int64     `json:"latency_ms"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// HistoryQuery describes the filtering and pagination options for a history search
+type HistoryQuery struct {
+	// Tenant restricts the search to one tenant's entries; empty matches entries without a tenant
+	Tenant   string
+	Model    string
+	From     time.Time
+	To       time.Time
+	Search   string
+	Page     int
+	PageSize int
+}
+
+// HistoryPage represents a single page of history search results
+type HistoryPage struct {
+	Entries  []HistoryEntry `json:"entries"`
+	Total    int            `json:"total"`
+	Page     int            `json:"page"`
+	PageSize int            `json:"page_size"`
+}
@@ -0,0 +1,78 @@
+package models
+
+import "time"
+
+// Review states of a translation. Machine output starts in ReviewMachine; an
+// approved translation is returned for identical requests instead of calling a model.
+const (
+	ReviewMachine  = "machine"
+	ReviewInReview = "in_review"
+	ReviewApproved = "approved"
+	ReviewRejected = "rejected"
+)
+
+// Review actions a reviewer can take on an item
+const (
+	ReviewActionCreate  = "create"
+	ReviewActionStart   = "start"
+	ReviewActionEdit    = "edit"
+	ReviewActionApprove = "approve"
+	ReviewActionReject  = "reject"
+)
+
+// ReviewItem is a machine translation awaiting or having passed human review
+type ReviewItem struct {
+	ID int64 `json:"id"`
+	// Key identifies requests that would produce the same translation
+	Key                string `json:"-"`
+	Tenant             string `json:"tenant,omitempty"`
+	Original           string `json:"original"`
+	MachineTranslation string `json:"machine_translation"`
+	// Translation is the reviewed text, which starts as the machine translation
+	Translation string        `json:"translation"`
+	Model       string        `json:"model"`
+	State       string        `json:"state"`
+	Reviewer    string        `json:"reviewer,omitempty"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+	Events      []ReviewEvent `json:"events,omitempty"`
+}
+
+// ReviewEvent is an entry in the audit trail of a review item
+type ReviewEvent struct {
+	ID        int64  `json:"id"`
+	ItemID    int64  `json:"item_id"`
+	Reviewer  string `json:"reviewer"`
+	Action    string `json:"action"`
+	FromState string `json:"from_state,omitempty"`
+	ToState   string `json:"to_state"`
+	// Translation is the text after the action, when the action changed it
+	Translation string    `json:"translation,omitempty"`
+	Comment     string    `json:"comment,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// ReviewAction is a reviewer's action on a review item
+type ReviewAction struct {
+	Action      string `json:"action"`
+	Reviewer    string `json:"reviewer"`
+	Translation string `json:"translation,omitempty"`
+	Comment     string `json:"comment,omitempty"`
+}
+
+// ReviewQuery describes the filtering and pagination options for listing review items
+type ReviewQuery struct {
+	// Tenant restricts the list to one tenant's items; empty matches items without a tenant
+	Tenant   string
+	State    string
+	Page     int
+	PageSize int
+}
+
+// ReviewPage represents a single page of review items, without their events
+type ReviewPage struct {
+	Items    []ReviewItem `json:"items"`
+	Total    int          `json:"total"`
+	Page     int          `json:"page"`
+	PageSize int          `json:"page_size"`
+}
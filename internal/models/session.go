@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// SessionRequest represents a request to start a translation session
+type SessionRequest struct {
+	// Model is used for translations in the session that do not name one
+	Model string `json:"model"`
+	// Terms are known term translations to keep consistent, English to Chinese
+	Terms map[string]string `json:"terms,omitempty"`
+}
+
+// Session represents a multi-turn translation session for one document
+type Session struct {
+	ID        string            `json:"id"`
+	Tenant    string            `json:"-"`
+	Model     string            `json:"model"`
+	Turns     []SessionTurn     `json:"turns"`
+	Terms     map[string]string `json:"terms"`
+	CreatedAt time.Time         `json:"created_at"`
+	ExpiresAt time.Time         `json:"expires_at"`
+}
+
+// SessionTurn is a previous translation in a session
+type SessionTurn struct {
+	Original    string `json:"original"`
+	Translation string `json:"translation"`
+}
+
+// SessionHistory is the part of a session sent to providers with a translation
+type SessionHistory struct {
+	Turns []SessionTurn
+	Terms map[string]string
+}
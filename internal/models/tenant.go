@@ -0,0 +1,75 @@
+package models
+
+import "time"
+
+// Sources of a tenant's definition
+const (
+	TenantSourceConfig = "config"
+	TenantSourceAPI    = "api"
+)
+
+// Target languages. The source language is always English.
+const (
+	LanguageSimplifiedChinese  = "zh-Hans"
+	LanguageTraditionalChinese = "zh-Hant"
+)
+
+// Tenant is a product team using the service, with its own credentials,
+// defaults and data
+type Tenant struct {
+	ID string `json:"id"`
+	// Source is "config" for tenants defined in the config file and "api" for
+	// tenants created through the admin API
+	Source string `json:"source"`
+	// APIKeyHashes are the SHA-256 hashes of the keys that identify the tenant
+	APIKeyHashes []string `json:"-"`
+	// AllowTenantHeader identifies the tenant by the X-Tenant-ID header alone
+	AllowTenantHeader bool `json:"allow_tenant_header,omitempty"`
+	// OpenAIKey and AnthropicKey replace the global provider keys for the
+	// tenant's requests. Tenants created through the admin API store file: or
+	// env: references, which are resolved when a request's tenant is.
+	OpenAIKey    string `json:"-"`
+	AnthropicKey string `json:"-"`
+	// DefaultModel is used for requests that do not name a model
+	DefaultModel string `json:"default_model,omitempty"`
+	// TargetLanguage is used for requests that do not name a target language
+	TargetLanguage   string    `json:"target_language,omitempty"`
+	AllowedProviders []string  `json:"allowed_providers,omitempty"`
+	AllowedEndpoints []string  `json:"allowed_endpoints,omitempty"`
+	Disabled         bool      `json:"disabled"`
+	CreatedAt        time.Time `json:"created_at,omitempty"`
+	UpdatedAt        time.Time `json:"updated_at,omitempty"`
+}
+
+// HasOwnKeys reports whether the tenant replaces any of the global provider keys
+func (t *Tenant) HasOwnKeys() bool {
+	return t.OpenAIKey != "" || t.AnthropicKey != ""
+}
+
+// TenantRequest is an admin request to create a tenant. The provider keys are
+// file: or env: references.
+type TenantRequest struct {
+	ID               string   `json:"id"`
+	OpenAIKey        string   `json:"openai_key,omitempty"`
+	AnthropicKey     string   `json:"anthropic_key,omitempty"`
+	DefaultModel     string   `json:"default_model,omitempty"`
+	TargetLanguage   string   `json:"target_language,omitempty"`
+	AllowedProviders []string `json:"allowed_providers,omitempty"`
+	AllowedEndpoints []string `json:"allowed_endpoints,omitempty"`
+}
+
+// TenantUpdate is an admin request to disable or re-enable a tenant or to
+// replace its provider keys. Fields left out are unchanged; an empty key
+// removes it.
+type TenantUpdate struct {
+	Disabled     *bool   `json:"disabled,omitempty"`
+	OpenAIKey    *string `json:"openai_key,omitempty"`
+	AnthropicKey *string `json:"anthropic_key,omitempty"`
+}
+
+// TenantCreated is returned once when a tenant is created. The API key is
+// not stored and cannot be retrieved again.
+type TenantCreated struct {
+	Tenant
+	APIKey string `json:"api_key"`
+}
@@ -6,6 +6,67 @@ import "context"
 type TranslationRequest struct {
 	Text  string `json:"text"`
 	Model string `json:"model"`
+	// Temperature overrides the sampling temperature; nil uses the default of 0.3
+	Temperature *float64 `json:"temperature,omitempty"`
+	// MaxTokens overrides the output token limit; zero uses the default of 1000
+	MaxTokens int `json:"max_tokens,omitempty"`
+	// Formality is the preferred register: "formal" or "informal"
+	Formality string `json:"formality,omitempty"`
+	// Tone is a short description of the desired tone, e.g. "friendly"
+	Tone string `json:"tone,omitempty"`
+	// Domain is the subject area: "legal", "medical", "marketing" or "ui"
+	Domain string `json:"domain,omitempty"`
+	// Instructions are free-form instructions added to the prompt
+	Instructions string `json:"instructions,omitempty"`
+	// Glossary is the ID of a glossary stored with the provider.
+	// Providers without glossary support ignore it.
+	Glossary string `json:"glossary,omitempty"`
+	// Prompt selects a prompt template as name or name@version; empty uses
+	// the template configured for the model
+	Prompt string `json:"prompt,omitempty"`
+	// Context describes where the text appears, to resolve ambiguous short strings
+	Context *TranslationContext `json:"context,omitempty"`
+	// SessionID translates the text within a session, so earlier translations
+	// and terms of the same document are taken into account
+	SessionID string `json:"session_id,omitempty"`
+	// Quality requests a quality estimate even when it is not enabled in the configuration
+	Quality bool `json:"quality,omitempty"`
+	// Verify selects a verification of the translation; only "back_translation" is supported
+	Verify string `json:"verify,omitempty"`
+	// TargetLanguage is "zh-Hans" for Simplified or "zh-Hant" for Traditional
+	// Chinese; empty uses the tenant's default, then Simplified Chinese
+	TargetLanguage string `json:"target_language,omitempty"`
+
+	// RenderedPrompt is the prompt rendered from the selected template. It is
+	// filled in by the translator service before the provider is called.
+	RenderedPrompt *RenderedPrompt `json:"-"`
+	// SessionHistory holds the session's earlier turns and terms. It is
+	// filled in by the translator service for requests with a SessionID.
+	SessionHistory *SessionHistory `json:"-"`
+}
+
+// TranslationContext describes the surroundings of the text being translated
+type TranslationContext struct {
+	// Preceding and Following are the neighbouring segments, nearest last and first respectively
+	Preceding []string `json:"preceding,omitempty"`
+	Following []string `json:"following,omitempty"`
+	// Screen is the name of the screen or page the text appears on
+	Screen string `json:"screen,omitempty"`
+	// Notes are developer notes for the translator
+	Notes string `json:"notes,omitempty"`
+	// MaxLength is the maximum length of the translation in characters; zero means no limit
+	MaxLength int `json:"max_length,omitempty"`
+}
+
+// RenderedPrompt is a system prompt rendered from a versioned prompt template
+type RenderedPrompt struct {
+	// Version identifies the template as name@version
+	Version string
+	Text    string
+	// Unframed marks prompts for tasks other than translating English into
+	// Chinese, such as back-translation, whose text completion providers
+	// receive as is rather than framed as an English to Chinese pair
+	Unframed bool
 }
 
 // TranslationResponse represents a translation response
@@ -13,6 +74,68 @@ type TranslationResponse struct {
 	Original    string `json:"original"`
 	Translation string `json:"translation"`
 	Model       string `json:"model"`
+	// Alias is the alias the request named when it was routed to Model
+	Alias string      `json:"alias,omitempty"`
+	Usage *TokenUsage `json:"usage,omitempty"`
+	// PromptVersion is the prompt template used, as name@version. It is empty
+	// for providers that do not use prompts.
+	PromptVersion string `json:"prompt_version,omitempty"`
+	// Quality is the estimated quality of the translation, when requested
+	Quality *QualityEstimate `json:"quality,omitempty"`
+	// Verification is the result of the verification selected by the request
+	Verification *Verification `json:"verification,omitempty"`
+	// ReviewID and ReviewState identify the review item of the translation when
+	// the review workflow is enabled. An approved state means the human-approved
+	// translation was returned without calling the model.
+	ReviewID    int64  `json:"review_id,omitempty"`
+	ReviewState string `json:"review_state,omitempty"`
+	// PII lists the personal data found in the request and the policy applied to it
+	PII []PIIFinding `json:"pii,omitempty"`
+}
+
+// PIIFinding reports personal data of one type found in a request and how it was handled
+type PIIFinding struct {
+	// Type is the kind of data: "email", "phone", "credit_card", "iban" or "ip"
+	Type string `json:"type"`
+	// Policy is "allow" (sent as is), "mask" (replaced before sending and restored
+	// in the translation) or "block" (the request was refused)
+	Policy string `json:"policy"`
+	Count  int    `json:"count"`
+}
+
+// Verification reports how well a translation survives being translated back
+// to the source language, for reviewers who cannot read the target language
+type Verification struct {
+	Method          string `json:"method"`
+	Model           string `json:"model"`
+	BackTranslation string `json:"back_translation,omitempty"`
+	// Similarity of the back-translation to the original text, from 0 to 1
+	Similarity float64 `json:"similarity"`
+	Error      string  `json:"error,omitempty"`
+}
+
+// QualityEstimate scores a translation between 0 and 1 and lists the problems found
+type QualityEstimate struct {
+	Score float64 `json:"score"`
+	// Flagged is set when the score is below the configured threshold
+	Flagged bool           `json:"flagged"`
+	Issues  []QualityIssue `json:"issues"`
+	// Judge is the model that reviewed the translation, if any
+	Judge string `json:"judge,omitempty"`
+}
+
+// QualityIssue is a problem found by a quality check
+type QualityIssue struct {
+	Check    string `json:"check"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// TokenUsage represents the number of tokens consumed by a translation
+type TokenUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
 }
 
 // Translator defines the interface for translation services
@@ -26,3 +149,20 @@ type Translator interface {
 	// SupportsModel returns true if the translator supports the given model
 	SupportsModel(model string) bool
 }
+
+// ProviderInfo identifies the external service a translator sends text to
+type ProviderInfo struct {
+	// Name is the provider, e.g. "openai" or "deepl"
+	Name     string `json:"name"`
+	Endpoint string `json:"endpoint"`
+}
+
+// ProviderTranslator is implemented by translators that send text to an
+// external service. Translators that do not implement it, such as mocks,
+// send nothing outside the service.
+type ProviderTranslator interface {
+	Translator
+
+	// Provider returns the provider and endpoint text is sent to
+	Provider() ProviderInfo
+}
@@ -0,0 +1,183 @@
+package prompts
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// Names of the built-in prompts
+const (
+	// DefaultName is the name of the built-in translation prompt
+	DefaultName = "translate"
+	// JudgeName is the name of the prompt used to review translation quality
+	JudgeName = "quality-judge"
+	// BackTranslateName is the name of the prompt used to translate results back to English
+	BackTranslateName = "back-translate"
+)
+
+//go:embed templates
+var builtinTemplates embed.FS
+
+// versionPattern matches template file names such as v1.tmpl
+var versionPattern = regexp.MustCompile(`^v([0-9]+)\.tmpl$`)
+
+// Template is a named, versioned prompt template
+type Template struct {
+	Name    string
+	Version string
+	number  int
+	tmpl    *template.Template
+}
+
+// ID returns the reference of the template in name@version form, which is
+// recorded with each translation
+func (t *Template) ID() string {
+	return t.Name + "@" + t.Version
+}
+
+// Render executes the template with the given data and returns the prompt text
+func (t *Template) Render(data interface{}) (string, error) {
+	var buf bytes.Buffer
+	if err := t.tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render prompt %s: %w", t.ID(), err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// Store holds the prompt templates available for translation, indexed by name.
+// Each name's versions are kept in ascending order.
+type Store struct {
+	templates map[string][]*Template
+}
+
+// NewStore creates a store with the built-in templates and, when dir is set,
+// the templates found in dir. Templates live in dir/<name>/v<N>.tmpl; a
+// directory template may add versions but may not redefine a built-in one.
+func NewStore(dir string) (*Store, error) {
+	store := &Store{templates: make(map[string][]*Template)}
+
+	builtin, err := fs.Sub(builtinTemplates, "templates")
+	if err != nil {
+		return nil, err
+	}
+	if err := store.load(builtin, "built-in"); err != nil {
+		return nil, err
+	}
+
+	if dir != "" {
+		if err := store.load(os.DirFS(dir), dir); err != nil {
+			return nil, err
+		}
+	}
+
+	return store, nil
+}
+
+// Builtin returns a store with only the built-in templates
+func Builtin() *Store {
+	store, err := NewStore("")
+	if err != nil {
+		panic(fmt.Sprintf("built-in prompt templates are invalid: %v", err))
+	}
+	return store
+}
+
+// load parses every template file in fsys and adds it to the store
+func (s *Store) load(fsys fs.FS, source string) error {
+	names, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return fmt.Errorf("failed to read prompt directory %s: %w", source, err)
+	}
+
+	for _, nameEntry := range names {
+		if !nameEntry.IsDir() {
+			continue
+		}
+		name := nameEntry.Name()
+
+		files, err := fs.ReadDir(fsys, name)
+		if err != nil {
+			return fmt.Errorf("failed to read prompt directory %s/%s: %w", source, name, err)
+		}
+
+		for _, file := range files {
+			if file.IsDir() || path.Ext(file.Name()) != ".tmpl" {
+				continue
+			}
+			match := versionPattern.FindStringSubmatch(file.Name())
+			if match == nil {
+				return fmt.Errorf("prompt template %s/%s/%s must be named v<N>.tmpl", source, name, file.Name())
+			}
+			number, _ := strconv.Atoi(match[1])
+
+			data, err := fs.ReadFile(fsys, path.Join(name, file.Name()))
+			if err != nil {
+				return fmt.Errorf("failed to read prompt template %s/%s/%s: %w", source, name, file.Name(), err)
+			}
+
+			t := &Template{Name: name, Version: "v" + match[1], number: number}
+			t.tmpl, err = template.New(t.ID()).Option("missingkey=error").Parse(string(data))
+			if err != nil {
+				return fmt.Errorf("failed to parse prompt template %s: %w", t.ID(), err)
+			}
+
+			if existing, _ := s.Get(t.ID()); existing != nil {
+				return fmt.Errorf("prompt template %s is already defined", t.ID())
+			}
+			s.templates[name] = append(s.templates[name], t)
+		}
+
+		sort.Slice(s.templates[name], func(i, j int) bool {
+			return s.templates[name][i].number < s.templates[name][j].number
+		})
+	}
+
+	return nil
+}
+
+// Get returns the template for a reference of the form name@version, or the
+// latest version of the template when only a name is given
+func (s *Store) Get(ref string) (*Template, error) {
+	name, version, pinned := strings.Cut(ref, "@")
+
+	versions := s.templates[name]
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("unknown prompt template: %s", name)
+	}
+	if !pinned {
+		return versions[len(versions)-1], nil
+	}
+
+	for _, t := range versions {
+		if t.Version == version {
+			return t, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown prompt template version: %s", ref)
+}
+
+// List returns the references of all templates in the store, sorted by name and version
+func (s *Store) List() []string {
+	names := make([]string, 0, len(s.templates))
+	for name := range s.templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var refs []string
+	for _, name := range names {
+		for _, t := range s.templates[name] {
+			refs = append(refs, t.ID())
+		}
+	}
+	return refs
+}
@@ -0,0 +1,156 @@
+package prompts
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"translator-service/internal/models"
+)
+
+// writeTemplate creates dir/name/version.tmpl
+func writeTemplate(t *testing.T, dir, name, file, text string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(dir, name), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name, file), []byte(text), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBuiltin(t *testing.T) {
+	store := Builtin()
+
+	template, err := store.Get(DefaultName)
+	if err != nil {
+		t.Fatalf("Expected built-in default template: %v", err)
+	}
+	if template.ID() != "translate@v2" {
+		t.Errorf("Expected translate@v2, got %s", template.ID())
+	}
+
+	req := &models.TranslationRequest{
+		Text:    "Open",
+		Domain:  "legal",
+		Context: &models.TranslationContext{Screen: "File menu", Following: []string{"Save"}, MaxLength: 4},
+	}
+	text, err := template.Render(req)
+	if err != nil {
+		t.Fatalf("Unexpected render error: %v", err)
+	}
+	for _, part := range []string{"You are a professional English to Chinese translator.", "legal terminology",
+		"Screen: File menu", "Following segment: Save", "at most 4 characters"} {
+		if !strings.Contains(text, part) {
+			t.Errorf("Expected prompt to contain %q, got %q", part, text)
+		}
+	}
+
+	// Version 1 predates context and must keep rendering the same prompt
+	v1, err := store.Get("translate@v1")
+	if err != nil {
+		t.Fatalf("Expected translate@v1: %v", err)
+	}
+	if text, _ := v1.Render(req); strings.Contains(text, "File menu") {
+		t.Errorf("Expected translate@v1 to ignore context, got %q", text)
+	}
+}
+
+func TestNewStore_Versions(t *testing.T) {
+	// Create a directory with versions that sort differently as strings and numbers
+	dir := t.TempDir()
+	writeTemplate(t, dir, "terse", "v2.tmpl", "two")
+	writeTemplate(t, dir, "terse", "v10.tmpl", "ten")
+	writeTemplate(t, dir, "terse", "README.md", "ignored")
+	writeTemplate(t, dir, "translate", "v3.tmpl", "translate three")
+
+	store, err := NewStore(dir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []string{"back-translate@v1", "quality-judge@v1", "terse@v2", "terse@v10", "translate@v1", "translate@v2", "translate@v3"}
+	if refs := store.List(); !reflect.DeepEqual(refs, expected) {
+		t.Errorf("Expected %v, got %v", expected, refs)
+	}
+
+	tests := []struct {
+		ref         string
+		expected    string
+		expectError bool
+	}{
+		{"terse", "terse@v10", false},
+		{"terse@v2", "terse@v2", false},
+		{"translate", "translate@v3", false},
+		{"translate@v1", "translate@v1", false},
+		{"terse@v3", "", true},
+		{"missing", "", true},
+	}
+
+	for _, tt := range tests {
+		template, err := store.Get(tt.ref)
+		if tt.expectError {
+			if err == nil {
+				t.Errorf("Expected error for %s", tt.ref)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Unexpected error for %s: %v", tt.ref, err)
+			continue
+		}
+		if template.ID() != tt.expected {
+			t.Errorf("Get(%s) = %s, expected %s", tt.ref, template.ID(), tt.expected)
+		}
+	}
+}
+
+func TestNewStore_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(dir string)
+	}{
+		{
+			name:  "Redefined built-in version",
+			setup: func(dir string) { writeTemplate(t, dir, "translate", "v1.tmpl", "override") },
+		},
+		{
+			name:  "Invalid version name",
+			setup: func(dir string) { writeTemplate(t, dir, "terse", "latest.tmpl", "text") },
+		},
+		{
+			name:  "Invalid template syntax",
+			setup: func(dir string) { writeTemplate(t, dir, "terse", "v1.tmpl", "{{.Text") },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			tt.setup(dir)
+			if _, err := NewStore(dir); err == nil {
+				t.Errorf("Expected error but got none")
+			}
+		})
+	}
+
+	if _, err := NewStore(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Errorf("Expected error for a missing directory")
+	}
+}
+
+func TestTemplate_RenderMissingField(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplate(t, dir, "terse", "v1.tmpl", "Translate {{.Unknown}}")
+
+	store, err := NewStore(dir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	template, _ := store.Get("terse")
+	if _, err := template.Render(map[string]string{"Text": "Hello"}); err == nil {
+		t.Errorf("Expected error for a missing field")
+	}
+}
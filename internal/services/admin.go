@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"translator-service/internal/models"
+)
+
+// AdminStatus returns the registered models and aliases, provider health,
+// request volume and recent errors of the running service
+func (ts *TranslatorService) AdminStatus() *models.AdminStatus {
+	ts.mu.RLock()
+	translators := ts.translators
+	disabled := make(map[string]bool, len(ts.disabledModels))
+	for model := range ts.disabledModels {
+		disabled[model] = true
+	}
+	ts.mu.RUnlock()
+
+	status := ts.monitor.Status(translators, disabled)
+	status.Aliases = ts.ListAliases()
+	return status
+}
+
+// SetModelEnabled enables or disables a registered model. A disabled model is
+// no longer offered and its requests fail until it is enabled again; the
+// setting is kept across configuration reloads but not restarts.
+func (ts *TranslatorService) SetModelEnabled(model string, enabled bool) (*models.ModelStatus, error) {
+	ts.mu.Lock()
+	if _, ok := ts.translators[model]; !ok {
+		ts.mu.Unlock()
+		return nil, fmt.Errorf("model not registered: %s", model)
+	}
+	if enabled {
+		delete(ts.disabledModels, model)
+	} else {
+		ts.disabledModels[model] = true
+	}
+	ts.mu.Unlock()
+
+	log.Printf("Model %s enabled: %v", model, enabled)
+	for _, status := range ts.AdminStatus().Models {
+		if status.Name == model {
+			return &status, nil
+		}
+	}
+	return nil, fmt.Errorf("model not registered: %s", model)
+}
+
+// modelDisabled reports whether an administrator has disabled a model
+func (ts *TranslatorService) modelDisabled(model string) bool {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+	return ts.disabledModels[model]
+}
+
+// recordProviderCall records the outcome of a call to a translator's provider.
+// Calls cancelled by the client and requests the provider rejected as invalid
+// say nothing about the provider's health.
+func (ts *TranslatorService) recordProviderCall(ctx context.Context, translator models.Translator, err error) {
+	pt, ok := translator.(models.ProviderTranslator)
+	if !ok {
+		return
+	}
+	if err != nil && (ctx.Err() != nil || strings.Contains(err.Error(), "validation error:")) {
+		return
+	}
+	ts.monitor.RecordProviderCall(pt.Provider(), err)
+}
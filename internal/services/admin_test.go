@@ -0,0 +1,220 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"translator-service/internal/config"
+	"translator-service/internal/models"
+)
+
+func TestMonitor_Volume(t *testing.T) {
+	monitor := NewMonitor()
+	now := time.Date(2024, 5, 1, 12, 0, 30, 0, time.UTC)
+	monitor.now = func() time.Time { return now }
+
+	// Two requests two hours ago, three in the current minute and one a minute earlier
+	now = now.Add(-2 * time.Hour)
+	monitor.RecordRequest("gpt-4", "", time.Millisecond, nil)
+	monitor.RecordRequest("gpt-4", "", time.Millisecond, nil)
+	now = now.Add(2*time.Hour - time.Minute)
+	monitor.RecordRequest("gpt-4", "", time.Millisecond, nil)
+	now = now.Add(time.Minute)
+	monitor.RecordRequest("gpt-4", "", 10*time.Millisecond, nil)
+	monitor.RecordRequest("claude", "acme", 20*time.Millisecond, errors.New("first failure"))
+	monitor.RecordRequest("claude", "", 20*time.Millisecond, errors.New("second failure"))
+
+	status := monitor.Status(nil, nil)
+	volume := status.Volume
+	if volume.Total != 6 || volume.Errors != 2 || volume.LastHour != 4 {
+		t.Errorf("Unexpected volume: %+v", volume)
+	}
+	if len(volume.PerMinute) != volumeMinutes || volume.PerMinute[volumeMinutes-1] != 3 || volume.PerMinute[volumeMinutes-2] != 1 {
+		t.Errorf("Unexpected requests per minute: %v", volume.PerMinute)
+	}
+
+	// Errors are listed newest first
+	if len(status.RecentErrors) != 2 || status.RecentErrors[0].Message != "second failure" || status.RecentErrors[1].Tenant != "acme" {
+		t.Errorf("Unexpected recent errors: %+v", status.RecentErrors)
+	}
+}
+
+func TestMonitor_CallLatencyP95(t *testing.T) {
+	monitor := NewMonitor()
+
+	if p95 := monitor.CallLatencyP95("gpt-4"); p95 != 0 {
+		t.Errorf("Expected no latency before the first call, got %v", p95)
+	}
+
+	// 1 to 100 ms; whole requests are not counted
+	for i := 1; i <= latencySamples; i++ {
+		monitor.RecordCallLatency("gpt-4", time.Duration(i)*time.Millisecond)
+	}
+	monitor.RecordRequest("gpt-4", "", time.Minute, nil)
+	if p95 := monitor.CallLatencyP95("gpt-4"); p95 != 95*time.Millisecond {
+		t.Errorf("Expected p95 of 95ms, got %v", p95)
+	}
+
+	// Only the latest calls count
+	for i := 0; i < latencySamples; i++ {
+		monitor.RecordCallLatency("gpt-4", time.Second)
+	}
+	if p95 := monitor.CallLatencyP95("gpt-4"); p95 != time.Second {
+		t.Errorf("Expected p95 of the latest calls, got %v", p95)
+	}
+}
+
+func TestMonitor_ProviderHealth(t *testing.T) {
+	monitor := NewMonitor()
+	provider := models.ProviderInfo{Name: "openai", Endpoint: "https://api.openai.com/v1"}
+
+	tests := []struct {
+		err      error
+		expected string
+	}{
+		{nil, models.ProviderHealthy},
+		{errors.New("timeout"), models.ProviderDegraded},
+		{errors.New("timeout"), models.ProviderDegraded},
+		{errors.New("timeout"), models.ProviderDown},
+		{nil, models.ProviderHealthy},
+	}
+
+	for i, tt := range tests {
+		monitor.RecordProviderCall(provider, tt.err)
+		status := monitor.Status(nil, nil)
+		if len(status.Providers) != 1 || status.Providers[0].Status != tt.expected {
+			t.Errorf("Call %d: expected %s, got %+v", i+1, tt.expected, status.Providers)
+		}
+	}
+
+	health := monitor.Status(nil, nil).Providers[0]
+	if health.Calls != 5 || health.Failures != 3 || health.LastError != "timeout" || health.LastSuccess == nil {
+		t.Errorf("Unexpected provider health: %+v", health)
+	}
+}
+
+func TestTranslatorService_AdminStatus(t *testing.T) {
+	ts := NewTranslatorService(&config.Config{ServerPort: "8080", Timeout: 30})
+
+	failing := true
+	ts.translators["gpt-4"] = &ProviderTranslatorForTesting{
+		MockTranslatorForTesting: MockTranslatorForTesting{
+			name: "gpt-4",
+			translateFunc: func(ctx context.Context, req *models.TranslationRequest) (*models.TranslationResponse, error) {
+				if failing {
+					return nil, errors.New("connection refused")
+				}
+				return &models.TranslationResponse{Original: req.Text, Translation: "保存", Model: req.Model}, nil
+			},
+		},
+		provider: models.ProviderInfo{Name: "openai", Endpoint: "https://api.openai.com/v1"},
+	}
+
+	// The provider is listed before its first call
+	status := ts.AdminStatus()
+	if len(status.Providers) != 1 || status.Providers[0].Status != models.ProviderUnknown {
+		t.Errorf("Expected the registered provider with unknown health, got %+v", status.Providers)
+	}
+
+	if _, err := ts.Translate(context.Background(), &models.TranslationRequest{Text: "Save", Model: "gpt-4"}); err == nil {
+		t.Fatalf("Expected translation to fail")
+	}
+	failing = false
+	if _, err := ts.Translate(context.Background(), &models.TranslationRequest{Text: "Save", Model: "gpt-4"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	status = ts.AdminStatus()
+	var gpt4, llama models.ModelStatus
+	for _, model := range status.Models {
+		switch model.Name {
+		case "gpt-4":
+			gpt4 = model
+		case "llama":
+			llama = model
+		}
+	}
+	if gpt4.Mock || gpt4.Provider != "openai" || gpt4.Requests != 2 || gpt4.Errors != 1 || !gpt4.Enabled {
+		t.Errorf("Unexpected gpt-4 status: %+v", gpt4)
+	}
+	if !llama.Mock || llama.Provider != "" {
+		t.Errorf("Expected llama to be a mock, got %+v", llama)
+	}
+
+	// Every attempt of the failed request counts against the provider
+	health := status.Providers[0]
+	if health.Status != models.ProviderHealthy || health.Calls != 4 || health.Failures != 3 {
+		t.Errorf("Unexpected provider health: %+v", health)
+	}
+	if len(status.RecentErrors) != 1 || !strings.Contains(status.RecentErrors[0].Message, "connection refused") {
+		t.Errorf("Unexpected recent errors: %+v", status.RecentErrors)
+	}
+}
+
+func TestTranslatorService_UnknownModelCounters(t *testing.T) {
+	ts := NewTranslatorService(&config.Config{ServerPort: "8080", Timeout: 30})
+
+	// Requests for models that are not registered share one set of counters
+	for _, model := range []string{"no-such-model", "another-model", "gpt-4"} {
+		ts.Translate(context.Background(), &models.TranslationRequest{Text: "Save", Model: model})
+	}
+
+	ts.monitor.mu.Lock()
+	defer ts.monitor.mu.Unlock()
+	if len(ts.monitor.models) != 2 || ts.monitor.models[unknownModel].requests != 2 || ts.monitor.models["gpt-4"].requests != 1 {
+		t.Errorf("Expected gpt-4 and the unknown bucket only, got %v", ts.monitor.models)
+	}
+	if len(ts.monitor.recent) != 2 || ts.monitor.recent[0].Model != unknownModel {
+		t.Errorf("Expected the errors to name the unknown bucket, got %+v", ts.monitor.recent)
+	}
+}
+
+func TestTranslatorService_SetModelEnabled(t *testing.T) {
+	ts := NewTranslatorService(&config.Config{ServerPort: "8080", Timeout: 30})
+	ctx := context.Background()
+
+	if _, err := ts.SetModelEnabled("no-such-model", false); err == nil || !strings.Contains(err.Error(), "model not registered") {
+		t.Errorf("Expected error for unknown model, got %v", err)
+	}
+
+	status, err := ts.SetModelEnabled("gpt-4", false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if status.Enabled {
+		t.Errorf("Expected gpt-4 to be disabled, got %+v", status)
+	}
+
+	// Disabled models are not offered and refuse requests
+	for _, model := range ts.GetSupportedModels() {
+		if model == "gpt-4" {
+			t.Errorf("Expected disabled model to be left out of the supported models")
+		}
+	}
+	if _, err := ts.Translate(ctx, &models.TranslationRequest{Text: "Save", Model: "gpt-4"}); err == nil || !strings.Contains(err.Error(), "model disabled") {
+		t.Errorf("Expected model disabled error, got %v", err)
+	}
+
+	// The setting survives a reload, and models used on the request's behalf are refused too
+	cfg := *ts.Config()
+	cfg.BackTranslationModel = "gpt-4"
+	ts.Reload(&cfg, nil)
+	response, err := ts.Translate(ctx, &models.TranslationRequest{Text: "Save", Model: "claude", Verify: VerifyBackTranslation})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response.Verification == nil || !strings.Contains(response.Verification.Error, "model disabled") {
+		t.Errorf("Expected back-translation with a disabled model to fail, got %+v", response.Verification)
+	}
+
+	// Enabling the model accepts its requests again
+	if _, err := ts.SetModelEnabled("gpt-4", true); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := ts.Translate(ctx, &models.TranslationRequest{Text: "Save", Model: "gpt-4"}); err != nil {
+		t.Errorf("Unexpected error after enabling: %v", err)
+	}
+}
@@ -0,0 +1,202 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+
+	"translator-service/internal/config"
+	"translator-service/internal/models"
+)
+
+// ListAliases returns the model aliases with their current weights, ordered by name
+func (ts *TranslatorService) ListAliases() []models.ModelAlias {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+
+	aliases := make([]models.ModelAlias, 0, len(ts.config.ModelAliases)+len(ts.aliasWeights))
+	for name := range ts.config.ModelAliases {
+		if _, ok := ts.aliasWeights[name]; !ok {
+			aliases = append(aliases, ts.aliasLocked(name))
+		}
+	}
+	for name := range ts.aliasWeights {
+		aliases = append(aliases, ts.aliasLocked(name))
+	}
+
+	sort.Slice(aliases, func(i, j int) bool {
+		return aliases[i].Name < aliases[j].Name
+	})
+	return aliases
+}
+
+// Alias returns a model alias with its current weights
+func (ts *TranslatorService) Alias(name string) (*models.ModelAlias, error) {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+
+	if ts.weightsLocked(name) == nil {
+		return nil, fmt.Errorf("alias not found: %s", name)
+	}
+	alias := ts.aliasLocked(name)
+	return &alias, nil
+}
+
+// SetAliasWeights sets the weights of an alias's models, creating the alias if
+// needed. The weights take precedence over the config file until they are
+// reset and are kept across configuration reloads but not restarts.
+func (ts *TranslatorService) SetAliasWeights(name string, weights map[string]int) (*models.ModelAlias, error) {
+	if err := config.ValidateModelAlias(name, weights); err != nil {
+		return nil, fmt.Errorf("validation error: %w", &ValidationError{err.Error()})
+	}
+
+	ts.mu.Lock()
+	if _, ok := ts.translators[name]; ok {
+		ts.mu.Unlock()
+		return nil, fmt.Errorf("validation error: %w", &ValidationError{"Alias name is already a model: " + name})
+	}
+	for model := range weights {
+		if _, ok := ts.translators[model]; !ok {
+			ts.mu.Unlock()
+			return nil, fmt.Errorf("validation error: %w", &ValidationError{"Unsupported model in alias: " + model})
+		}
+	}
+
+	copied := make(map[string]int, len(weights))
+	for model, weight := range weights {
+		copied[model] = weight
+	}
+	ts.aliasWeights[name] = copied
+	alias := ts.aliasLocked(name)
+	ts.mu.Unlock()
+
+	log.Printf("Alias %s routes to %v", name, weights)
+	return &alias, nil
+}
+
+// ResetAlias discards the weights set through the admin API, returning the
+// alias to its weights in the config file or removing it if it has none
+func (ts *TranslatorService) ResetAlias(name string) error {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if _, ok := ts.aliasWeights[name]; !ok {
+		return fmt.Errorf("alias not found: %s has no weights set through the admin API", name)
+	}
+	delete(ts.aliasWeights, name)
+
+	log.Printf("Alias %s reset", name)
+	return nil
+}
+
+// isAlias reports whether a name is a model alias. Registered models take
+// precedence over aliases of the same name.
+func (ts *TranslatorService) isAlias(name string) bool {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+
+	_, isModel := ts.translators[name]
+	return !isModel && ts.weightsLocked(name) != nil
+}
+
+// resolveAlias replaces an alias in the request with one of its models, chosen
+// by weight among those the request may use, and returns the alias. Model auto
+// is routed by the routing policy. Requests naming a model are left untouched.
+func (ts *TranslatorService) resolveAlias(ctx context.Context, req *models.TranslationRequest) (string, error) {
+	if req.Model == config.AutoModel {
+		if err := ts.routeAuto(ctx, req); err != nil {
+			return "", err
+		}
+		return config.AutoModel, nil
+	}
+	if !ts.isAlias(req.Model) {
+		return "", nil
+	}
+
+	ts.mu.RLock()
+	weights := ts.weightsLocked(req.Model)
+	ts.mu.RUnlock()
+
+	// Leave out models that are unknown, disabled or not allowed for the tenant
+	var candidates []models.AliasTarget
+	var lastErr error
+	total := 0
+	for _, target := range sortedTargets(weights) {
+		if target.Weight == 0 {
+			continue
+		}
+		translator, exists := ts.translator(ctx, target.Model)
+		if !exists {
+			lastErr = fmt.Errorf("unsupported model: %s", target.Model)
+			continue
+		}
+		if err := ts.authorizeProvider(ctx, target.Model, translator); err != nil {
+			lastErr = err
+			continue
+		}
+		candidates = append(candidates, target)
+		total += target.Weight
+	}
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("no model available for alias %s: %w", req.Model, lastErr)
+	}
+
+	alias := req.Model
+	req.Model = chooseTarget(candidates, ts.randIntN(total))
+	return alias, nil
+}
+
+// weightsLocked returns the weights of an alias, preferring those set through
+// the admin API. ts.mu must be held.
+func (ts *TranslatorService) weightsLocked(name string) map[string]int {
+	if weights, ok := ts.aliasWeights[name]; ok {
+		return weights
+	}
+	return ts.config.ModelAliases[name]
+}
+
+// aliasLocked describes an alias with its current weights. ts.mu must be held.
+func (ts *TranslatorService) aliasLocked(name string) models.ModelAlias {
+	source := models.AliasSourceConfig
+	if _, ok := ts.aliasWeights[name]; ok {
+		source = models.AliasSourceAdmin
+	}
+
+	targets := sortedTargets(ts.weightsLocked(name))
+	total := 0
+	for _, target := range targets {
+		total += target.Weight
+	}
+	for i := range targets {
+		if total > 0 {
+			targets[i].Share = math.Round(float64(targets[i].Weight)*1000/float64(total)) / 10
+		}
+	}
+	return models.ModelAlias{Name: name, Targets: targets, Source: source}
+}
+
+// sortedTargets lists an alias's models ordered by name
+func sortedTargets(weights map[string]int) []models.AliasTarget {
+	targets := make([]models.AliasTarget, 0, len(weights))
+	for model, weight := range weights {
+		targets = append(targets, models.AliasTarget{Model: model, Weight: weight})
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i].Model < targets[j].Model
+	})
+	return targets
+}
+
+// chooseTarget returns the model whose weight range contains n, for n from 0
+// up to the targets' total weight
+func chooseTarget(targets []models.AliasTarget, n int) string {
+	for _, target := range targets {
+		if n < target.Weight {
+			return target.Model
+		}
+		n -= target.Weight
+	}
+	return targets[len(targets)-1].Model
+}
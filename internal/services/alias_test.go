@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"translator-service/internal/config"
+	"translator-service/internal/models"
+)
+
+func TestChooseTarget(t *testing.T) {
+	targets := []models.AliasTarget{{Model: "gpt-4", Weight: 90}, {Model: "gpt-4o", Weight: 10}}
+
+	tests := []struct {
+		n        int
+		expected string
+	}{
+		{0, "gpt-4"},
+		{89, "gpt-4"},
+		{90, "gpt-4o"},
+		{99, "gpt-4o"},
+	}
+	for _, tt := range tests {
+		if got := chooseTarget(targets, tt.n); got != tt.expected {
+			t.Errorf("chooseTarget(%d) = %s, want %s", tt.n, got, tt.expected)
+		}
+	}
+}
+
+func TestTranslatorService_ResolveAlias(t *testing.T) {
+	ts := NewTranslatorService(&config.Config{
+		ServerPort:   "8080",
+		Timeout:      30,
+		ModelAliases: map[string]map[string]int{"quality": {"gpt-4": 90, "gpt-4o": 10, "claude": 0}},
+	})
+	ctx := context.Background()
+
+	// The alias is resolved to the model whose weight range holds the random number
+	var total int
+	ts.randIntN = func(n int) int {
+		total = n
+		return n - 1
+	}
+	response, err := ts.Translate(ctx, &models.TranslationRequest{Text: "Save", Model: "quality"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if total != 100 || response.Model != "gpt-4o" || response.Alias != "quality" {
+		t.Errorf("Expected quality to route to gpt-4o out of 100, got %s via %q out of %d", response.Model, response.Alias, total)
+	}
+
+	// Disabled models are left out and their weight with them
+	if _, err := ts.SetModelEnabled("gpt-4o", false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	response, err = ts.Translate(ctx, &models.TranslationRequest{Text: "Save", Model: "quality"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if total != 90 || response.Model != "gpt-4" {
+		t.Errorf("Expected quality to route to gpt-4 out of 90, got %s out of %d", response.Model, total)
+	}
+
+	if _, err := ts.SetModelEnabled("gpt-4", false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := ts.Translate(ctx, &models.TranslationRequest{Text: "Save", Model: "quality"}); err == nil || !strings.Contains(err.Error(), "no model available for alias quality") {
+		t.Errorf("Expected error when every model of the alias is disabled, got %v", err)
+	}
+
+	// Requests naming a model are not routed
+	response, err = ts.Translate(ctx, &models.TranslationRequest{Text: "Save", Model: "claude"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response.Alias != "" {
+		t.Errorf("Expected no alias for a model request, got %q", response.Alias)
+	}
+}
+
+func TestTranslatorService_SetAliasWeights(t *testing.T) {
+	ts := NewTranslatorService(&config.Config{
+		ServerPort:   "8080",
+		Timeout:      30,
+		ModelAliases: map[string]map[string]int{"quality": {"gpt-4": 1}},
+	})
+
+	invalid := []struct {
+		name    string
+		alias   string
+		weights map[string]int
+	}{
+		{"no models", "fast", map[string]int{}},
+		{"zero weights", "fast", map[string]int{"gpt-3.5": 0}},
+		{"unknown model", "fast", map[string]int{"no-such-model": 1}},
+		{"model name", "gpt-4", map[string]int{"gpt-3.5": 1}},
+	}
+	for _, tt := range invalid {
+		if _, err := ts.SetAliasWeights(tt.alias, tt.weights); err == nil || !strings.Contains(err.Error(), "validation error") {
+			t.Errorf("Expected validation error for %s, got %v", tt.name, err)
+		}
+	}
+
+	// Weights set at runtime override the config file and survive a reload
+	alias, err := ts.SetAliasWeights("quality", map[string]int{"gpt-4": 3, "gpt-4o": 1})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if alias.Source != models.AliasSourceAdmin || len(alias.Targets) != 2 || alias.Targets[0].Share != 75 || alias.Targets[1].Share != 25 {
+		t.Errorf("Unexpected alias: %+v", alias)
+	}
+	if _, err := ts.SetAliasWeights("fast", map[string]int{"gpt-3.5": 1}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	ts.Reload(ts.Config(), nil)
+
+	aliases := ts.ListAliases()
+	if len(aliases) != 2 || aliases[0].Name != "fast" || aliases[1].Name != "quality" || len(aliases[1].Targets) != 2 {
+		t.Errorf("Unexpected aliases: %+v", aliases)
+	}
+	if !ts.IsModelSupported("fast") || !ts.IsModelSupported("quality") {
+		t.Errorf("Expected aliases to be supported")
+	}
+	supported := strings.Join(ts.GetSupportedModels(), ",")
+	if !strings.Contains(supported, "fast") || !strings.Contains(supported, "quality") {
+		t.Errorf("Expected aliases among the supported models, got %s", supported)
+	}
+
+	// Resetting returns to the config file, or removes aliases it does not have
+	if err := ts.ResetAlias("quality"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := ts.ResetAlias("fast"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	alias, err = ts.Alias("quality")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if alias.Source != models.AliasSourceConfig || len(alias.Targets) != 1 || alias.Targets[0].Share != 100 {
+		t.Errorf("Expected config weights after reset, got %+v", alias)
+	}
+	if _, err := ts.Alias("fast"); err == nil || !strings.Contains(err.Error(), "alias not found") {
+		t.Errorf("Expected alias not found, got %v", err)
+	}
+	if err := ts.ResetAlias("quality"); err == nil || !strings.Contains(err.Error(), "alias not found") {
+		t.Errorf("Expected error resetting an alias without admin weights, got %v", err)
+	}
+}
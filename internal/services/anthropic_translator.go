@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"time"
 
 	"translator-service/internal/models"
 )
@@ -20,23 +19,26 @@ type AnthropicTranslator struct {
 }
 
 // NewAnthropicTranslator creates a new Anthropic translator
-func NewAnthropicTranslator(apiKey, endpoint string) *AnthropicTranslator {
+func NewAnthropicTranslator(apiKey, endpoint string, client *http.Client) *AnthropicTranslator {
 	return &AnthropicTranslator{
 		apiKey:   apiKey,
 		endpoint: endpoint,
-		client: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		client:   client,
 	}
 }
 
 // Translate translates text using the Anthropic API
 func (at *AnthropicTranslator) Translate(ctx context.Context, req *models.TranslationRequest) (*models.TranslationResponse, error) {
+	temperature, maxTokens := generationParams(req)
+	prompt := promptFor(req)
+
 	// Create the Anthropic API request
 	apiReq := AnthropicRequest{
-		Model:     req.Model,
-		Messages:  []AnthropicMessage{{Role: "user", Content: at.createPrompt(req.Text)}},
-		MaxTokens: 1000,
+		Model:       req.Model,
+		System:      systemPrompt(prompt, req),
+		Messages:    anthropicMessages(req),
+		MaxTokens:   maxTokens,
+		Temperature: &temperature,
 	}
 
 	// Convert request to JSON
@@ -96,22 +98,28 @@ func (at *AnthropicTranslator) Translate(ctx context.Context, req *models.Transl
 	}
 
 	return &models.TranslationResponse{
-		Original:    req.Text,
-		Translation: translation,
-		Model:       req.Model,
+		Original:      req.Text,
+		Translation:   translation,
+		Model:         req.Model,
+		PromptVersion: prompt.Version,
+		Usage: &models.TokenUsage{
+			PromptTokens:     apiResp.Usage.InputTokens,
+			CompletionTokens: apiResp.Usage.OutputTokens,
+			TotalTokens:      apiResp.Usage.InputTokens + apiResp.Usage.OutputTokens,
+		},
 	}, nil
 }
 
-// createPrompt creates a prompt for translation
-func (at *AnthropicTranslator) createPrompt(text string) string {
-	return fmt.Sprintf("Translate the following English text to Chinese. Provide only the translation without any explanation.\n\nEnglish: %s\n\nChinese:", text)
-}
-
 // Name returns the name of the translator
 func (at *AnthropicTranslator) Name() string {
 	return "Anthropic"
 }
 
+// Provider returns the provider and endpoint text is sent to
+func (at *AnthropicTranslator) Provider() models.ProviderInfo {
+	return models.ProviderInfo{Name: "anthropic", Endpoint: at.endpoint}
+}
+
 // SupportsModel returns true if the translator supports the given model
 func (at *AnthropicTranslator) SupportsModel(model string) bool {
 	switch model {
@@ -124,9 +132,25 @@ func (at *AnthropicTranslator) SupportsModel(model string) bool {
 
 // AnthropicRequest represents the request structure for Anthropic API
 type AnthropicRequest struct {
-	Model     string             `json:"model"`
-	Messages  []AnthropicMessage `json:"messages"`
-	MaxTokens int                `json:"max_tokens"`
+	Model       string             `json:"model"`
+	System      string             `json:"system,omitempty"`
+	Messages    []AnthropicMessage `json:"messages"`
+	MaxTokens   int                `json:"max_tokens"`
+	Temperature *float64           `json:"temperature,omitempty"`
+}
+
+// anthropicMessages returns the session's earlier translations as user and
+// assistant messages, followed by the text to translate
+func anthropicMessages(req *models.TranslationRequest) []AnthropicMessage {
+	turns := sessionTurns(req)
+	messages := make([]AnthropicMessage, 0, len(turns)*2+1)
+	for _, turn := range turns {
+		messages = append(messages,
+			AnthropicMessage{Role: "user", Content: turn.Original},
+			AnthropicMessage{Role: "assistant", Content: turn.Translation},
+		)
+	}
+	return append(messages, AnthropicMessage{Role: "user", Content: req.Text})
 }
 
 // AnthropicMessage represents a single message in the conversation
@@ -142,9 +166,16 @@ type AnthropicResponse struct {
 	Role    string             `json:"role"`
 	Content []AnthropicContent `json:"content"`
 	Model   string             `json:"model"`
+	Usage   AnthropicUsage     `json:"usage"`
 	Error   AnthropicError     `json:"error"`
 }
 
+// AnthropicUsage represents token usage information
+type AnthropicUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
 // AnthropicContent represents the content of a message
 type AnthropicContent struct {
 	Type string `json:"type"`
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"translator-service/internal/models"
+)
+
+// AzureOpenAITranslator implements the Translator interface for Azure OpenAI deployments
+type AzureOpenAITranslator struct {
+	apiKey      string
+	endpoint    string
+	apiVersion  string
+	deployments map[string]string
+	client      *http.Client
+}
+
+// NewAzureOpenAITranslator creates a new Azure OpenAI translator. Deployments map
+// the model names accepted by the service to Azure deployment names.
+func NewAzureOpenAITranslator(apiKey, endpoint, apiVersion string, deployments map[string]string, client *http.Client) *AzureOpenAITranslator {
+	return &AzureOpenAITranslator{
+		apiKey:      apiKey,
+		endpoint:    strings.TrimSuffix(endpoint, "/"),
+		apiVersion:  apiVersion,
+		deployments: deployments,
+		client:      client,
+	}
+}
+
+// Translate translates text using the Azure OpenAI chat completions API
+func (at *AzureOpenAITranslator) Translate(ctx context.Context, req *models.TranslationRequest) (*models.TranslationResponse, error) {
+	deployment, exists := at.deployments[req.Model]
+	if !exists {
+		return nil, fmt.Errorf("no Azure deployment configured for model %s", req.Model)
+	}
+
+	temperature, maxTokens := generationParams(req)
+	prompt := promptFor(req)
+
+	// Create the API request; Azure selects the model by deployment, not by the model field
+	apiReq := OpenAIRequest{
+		Messages:    chatMessages(prompt, req),
+		Temperature: &temperature,
+		MaxTokens:   maxTokens,
+	}
+
+	// Convert request to JSON
+	jsonData, err := json.Marshal(apiReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	// Create HTTP request
+	requestURL := fmt.Sprintf("%s/openai/deployments/%s/chat/completions?api-version=%s",
+		at.endpoint, url.PathEscape(deployment), url.QueryEscape(at.apiVersion))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", requestURL, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+
+	// Set headers
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("api-key", at.apiKey)
+
+	// Make the API call
+	resp, err := at.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make API call: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Read response body
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	// Check for HTTP errors
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	// Parse response
+	var apiResp OpenAIResponse
+	if err := json.Unmarshal(body, &apiResp); err != nil {
+		return nil, fmt.Errorf("failed to parse API response: %w", err)
+	}
+
+	// Check for API errors
+	if len(apiResp.Error.Message) > 0 {
+		return nil, fmt.Errorf("API error: %s", apiResp.Error.Message)
+	}
+
+	// Extract translation from response
+	if len(apiResp.Choices) == 0 {
+		return nil, fmt.Errorf("API returned no translation choices")
+	}
+
+	translation := apiResp.Choices[0].Message.Content
+	if translation == "" {
+		return nil, fmt.Errorf("API returned empty translation")
+	}
+
+	return &models.TranslationResponse{
+		Original:      req.Text,
+		Translation:   translation,
+		Model:         req.Model,
+		PromptVersion: prompt.Version,
+		Usage: &models.TokenUsage{
+			PromptTokens:     apiResp.Usage.PromptTokens,
+			CompletionTokens: apiResp.Usage.CompletionTokens,
+			TotalTokens:      apiResp.Usage.TotalTokens,
+		},
+	}, nil
+}
+
+// Name returns the name of the translator
+func (at *AzureOpenAITranslator) Name() string {
+	return "Azure OpenAI"
+}
+
+// Provider returns the provider and endpoint text is sent to
+func (at *AzureOpenAITranslator) Provider() models.ProviderInfo {
+	return models.ProviderInfo{Name: "azure", Endpoint: at.endpoint}
+}
+
+// SupportsModel returns true if the translator supports the given model
+func (at *AzureOpenAITranslator) SupportsModel(model string) bool {
+	_, exists := at.deployments[model]
+	return exists
+}
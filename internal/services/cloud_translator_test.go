@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"translator-service/internal/config"
+	"translator-service/internal/models"
+)
+
+// loadFixture reads a file from the testdata directory
+func loadFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("Failed to read fixture %s: %v", name, err)
+	}
+	return data
+}
+
+// assertJSONBody checks that a request body matches a JSON fixture
+func assertJSONBody(t *testing.T, r *http.Request, fixture string) {
+	t.Helper()
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		t.Fatalf("Failed to read request body: %v", err)
+	}
+
+	var actual, expected interface{}
+	if err := json.Unmarshal(body, &actual); err != nil {
+		t.Fatalf("Request body is not valid JSON: %v", err)
+	}
+	if err := json.Unmarshal(loadFixture(t, fixture), &expected); err != nil {
+		t.Fatalf("Fixture %s is not valid JSON: %v", fixture, err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Request body does not match %s: got %s", fixture, body)
+	}
+}
+
+func TestAzureOpenAITranslator_Translate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/openai/deployments/prod-gpt4o/chat/completions" {
+			t.Errorf("Unexpected path: %s", r.URL.Path)
+		}
+		if version := r.URL.Query().Get("api-version"); version != "2024-02-01" {
+			t.Errorf("Expected api-version 2024-02-01, got %s", version)
+		}
+		if key := r.Header.Get("api-key"); key != "azure-key" {
+			t.Errorf("Expected api-key header, got %q", key)
+		}
+		if auth := r.Header.Get("Authorization"); auth != "" {
+			t.Errorf("Expected no Authorization header, got %q", auth)
+		}
+		assertJSONBody(t, r, "azure_chat_request.json")
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(loadFixture(t, "azure_chat_response.json"))
+	}))
+	defer server.Close()
+
+	translator := NewAzureOpenAITranslator("azure-key", server.URL, "2024-02-01", map[string]string{"gpt-4o": "prod-gpt4o"}, server.Client())
+	response, err := translator.Translate(context.Background(), &models.TranslationRequest{Text: "Hello, world!", Model: "gpt-4o"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if response.Translation != "你好，世界！" {
+		t.Errorf("Unexpected translation: %q", response.Translation)
+	}
+	if response.Model != "gpt-4o" {
+		t.Errorf("Expected requested model in response, got %s", response.Model)
+	}
+	if response.Usage == nil || response.Usage.TotalTokens != 44 {
+		t.Errorf("Expected token usage of 44, got %+v", response.Usage)
+	}
+}
+
+func TestAzureOpenAITranslator_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write(loadFixture(t, "azure_error_response.json"))
+	}))
+	defer server.Close()
+
+	translator := NewAzureOpenAITranslator("azure-key", server.URL, "2024-02-01", map[string]string{"gpt-4o": "missing"}, server.Client())
+
+	_, err := translator.Translate(context.Background(), &models.TranslationRequest{Text: "Hello", Model: "gpt-4o"})
+	if err == nil || !strings.Contains(err.Error(), "DeploymentNotFound") {
+		t.Errorf("Expected deployment not found error, got %v", err)
+	}
+
+	_, err = translator.Translate(context.Background(), &models.TranslationRequest{Text: "Hello", Model: "gpt-4"})
+	if err == nil || !strings.Contains(err.Error(), "no Azure deployment") {
+		t.Errorf("Expected missing deployment error, got %v", err)
+	}
+}
+
+func TestGeminiTranslator_Translate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1beta/models/gemini-1.5-pro:generateContent" {
+			t.Errorf("Unexpected path: %s", r.URL.Path)
+		}
+		if key := r.Header.Get("x-goog-api-key"); key != "gemini-key" {
+			t.Errorf("Expected x-goog-api-key header, got %q", key)
+		}
+		assertJSONBody(t, r, "gemini_generate_content_request.json")
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(loadFixture(t, "gemini_generate_content_response.json"))
+	}))
+	defer server.Close()
+
+	translator := NewGeminiTranslator("gemini-key", server.URL+"/v1beta", []string{"gemini-1.5-pro"}, server.Client())
+	response, err := translator.Translate(context.Background(), &models.TranslationRequest{Text: "Hello, world!", Model: "gemini-1.5-pro"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if response.Translation != "你好，世界！" {
+		t.Errorf("Unexpected translation: %q", response.Translation)
+	}
+	if response.Usage == nil || response.Usage.TotalTokens != 36 {
+		t.Errorf("Expected token usage of 36, got %+v", response.Usage)
+	}
+}
+
+func TestGeminiTranslator_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(loadFixture(t, "gemini_error_response.json"))
+	}))
+	defer server.Close()
+
+	translator := NewGeminiTranslator("bad-key", server.URL, []string{"gemini-1.5-pro"}, server.Client())
+	_, err := translator.Translate(context.Background(), &models.TranslationRequest{Text: "Hello", Model: "gemini-1.5-pro"})
+	if err == nil || !strings.Contains(err.Error(), "API key not valid") {
+		t.Errorf("Expected invalid key error, got %v", err)
+	}
+}
+
+func TestTranslatorService_CloudProviders(t *testing.T) {
+	cfg := &config.Config{
+		ServerPort:       "8080",
+		Timeout:          30,
+		AzureEndpoint:    "https://example.openai.azure.com",
+		AzureKey:         "azure-key",
+		AzureAPIVersion:  "2024-02-01",
+		AzureDeployments: map[string]string{"gpt-4o": "prod-gpt4o"},
+		GeminiEndpoint:   "https://generativelanguage.googleapis.com/v1beta",
+		GeminiKey:        "gemini-key",
+		GeminiModels:     []string{"gemini-1.5-flash"},
+	}
+
+	ts := NewTranslatorService(cfg)
+
+	if translator, _ := ts.translator(context.Background(), "gpt-4o"); translator.Name() != "Azure OpenAI" {
+		t.Errorf("Expected gpt-4o to be served by Azure OpenAI, got %s", translator.Name())
+	}
+	if translator, _ := ts.translator(context.Background(), "gemini-1.5-flash"); translator == nil || translator.Name() != "Gemini" {
+		t.Errorf("Expected gemini-1.5-flash to be served by Gemini")
+	}
+}
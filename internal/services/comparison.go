@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"translator-service/internal/models"
+)
+
+// MaxComparisonModels is the largest number of models a single comparison may use
+const MaxComparisonModels = 5
+
+// Compare translates the same text with several models concurrently and returns
+// the results side by side, in the order the models were requested. The
+// comparison is stored for the tenant so votes can be checked against it.
+func (ts *TranslatorService) Compare(ctx context.Context, req *models.ComparisonRequest) (*models.ComparisonResponse, error) {
+	// Validate input once rather than reporting the same error for every model
+	if err := ts.validationService.ValidateTextInput(req.Text); err != nil {
+		return nil, fmt.Errorf("validation error: %w", err)
+	}
+
+	comparisonModels := uniqueModels(req.Models)
+	if len(comparisonModels) < 2 {
+		return nil, fmt.Errorf("validation error: %w", &ValidationError{"At least two models are required for a comparison"})
+	}
+	if len(comparisonModels) > MaxComparisonModels {
+		return nil, fmt.Errorf("validation error: %w", &ValidationError{fmt.Sprintf("A comparison can use at most %d models", MaxComparisonModels)})
+	}
+
+	response := &models.ComparisonResponse{
+		ID:       newID(),
+		Original: req.Text,
+		Results:  make([]models.ComparisonResult, len(comparisonModels)),
+	}
+
+	// Fan out to every model, each writing to its own result slot
+	var wg sync.WaitGroup
+	for i, model := range comparisonModels {
+		wg.Add(1)
+		go func(i int, model string) {
+			defer wg.Done()
+
+			start := time.Now()
+			translation, err := ts.Translate(ctx, &models.TranslationRequest{Text: req.Text, Model: model})
+
+			result := models.ComparisonResult{
+				Model:     model,
+				LatencyMs: time.Since(start).Milliseconds(),
+			}
+			if err != nil {
+				result.Error = err.Error()
+			} else {
+				result.Translation = translation.Translation
+				result.Usage = translation.Usage
+				result.PromptVersion = translation.PromptVersion
+				result.Quality = translation.Quality
+				result.PII = translation.PII
+			}
+			response.Results[i] = result
+		}(i, model)
+	}
+	wg.Wait()
+
+	comparison := &models.Comparison{
+		ID:        response.ID,
+		Tenant:    TenantFromContext(ctx),
+		Original:  response.Original,
+		Results:   response.Results,
+		CreatedAt: time.Now().UTC(),
+	}
+	// Use a fresh context so a comparison whose models ran out of time can still be voted on
+	if err := ts.votes.SaveComparison(context.WithoutCancel(ctx), comparison); err != nil {
+		return nil, fmt.Errorf("failed to save comparison: %w", err)
+	}
+
+	return response, nil
+}
+
+// uniqueModels trims and de-duplicates a list of model names, preserving order
+func uniqueModels(requested []string) []string {
+	seen := make(map[string]bool)
+	unique := make([]string, 0, len(requested))
+	for _, model := range requested {
+		model = strings.TrimSpace(model)
+		if model == "" || seen[model] {
+			continue
+		}
+		seen[model] = true
+		unique = append(unique, model)
+	}
+	return unique
+}
+
+// newID generates a random identifier for comparisons and sessions
+func newID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"translator-service/internal/config"
+	"translator-service/internal/models"
+)
+
+func TestTranslatorService_Compare(t *testing.T) {
+	cfg := &config.Config{
+		ServerPort: "8080",
+		Timeout:    30,
+	}
+
+	ts := NewTranslatorService(cfg)
+	ts.translators["fast-model"] = &MockTranslatorForTesting{name: "fast-model"}
+	ts.translators["slow-model"] = &MockTranslatorForTesting{
+		name: "slow-model",
+		translateFunc: func(ctx context.Context, req *models.TranslationRequest) (*models.TranslationResponse, error) {
+			time.Sleep(50 * time.Millisecond)
+			return &models.TranslationResponse{
+				Original:    req.Text,
+				Translation: "slow translation",
+				Model:       "slow-model",
+				Usage:       &models.TokenUsage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15},
+			}, nil
+		},
+	}
+
+	req := &models.ComparisonRequest{
+		Text:   "Hello, world!",
+		Models: []string{"slow-model", "fast-model", "slow-model", "unsupported-model"},
+	}
+
+	response, err := ts.Compare(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if response.ID == "" {
+		t.Errorf("Expected comparison ID to be set")
+	}
+
+	// Duplicates are removed and request order is preserved
+	if len(response.Results) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(response.Results))
+	}
+	if response.Results[0].Model != "slow-model" || response.Results[1].Model != "fast-model" {
+		t.Errorf("Expected results in request order, got %+v", response.Results)
+	}
+
+	if response.Results[0].Usage == nil || response.Results[0].Usage.TotalTokens != 15 {
+		t.Errorf("Expected token usage to be reported for slow-model")
+	}
+	if response.Results[0].LatencyMs < 50 {
+		t.Errorf("Expected latency of at least 50ms, got %d", response.Results[0].LatencyMs)
+	}
+
+	// A failing model reports its error without failing the comparison
+	if response.Results[2].Error == "" {
+		t.Errorf("Expected error for unsupported model")
+	}
+}
+
+func TestTranslatorService_Compare_Validation(t *testing.T) {
+	cfg := &config.Config{
+		ServerPort: "8080",
+		Timeout:    30,
+	}
+
+	ts := NewTranslatorService(cfg)
+
+	tests := []struct {
+		name    string
+		request *models.ComparisonRequest
+	}{
+		{
+			name:    "Empty text",
+			request: &models.ComparisonRequest{Text: "", Models: []string{"gpt-4", "claude"}},
+		},
+		{
+			name:    "Single model",
+			request: &models.ComparisonRequest{Text: "Hello", Models: []string{"gpt-4", "gpt-4"}},
+		},
+		{
+			name:    "Too many models",
+			request: &models.ComparisonRequest{Text: "Hello", Models: []string{"a", "b", "c", "d", "e", "f"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ts.Compare(context.Background(), tt.request)
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Errorf("Expected validation error, got %v", err)
+			}
+		})
+	}
+}
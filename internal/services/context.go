@@ -0,0 +1,47 @@
+package services
+
+import (
+	"context"
+
+	"translator-service/internal/models"
+)
+
+// contextKey is the type used for values stored in a request context
+type contextKey string
+
+const (
+	clientContextKey contextKey = "client"
+	tenantContextKey contextKey = "tenant"
+)
+
+// WithClient returns a context carrying the identifier of the calling client
+func WithClient(ctx context.Context, client string) context.Context {
+	return context.WithValue(ctx, clientContextKey, client)
+}
+
+// ClientFromContext returns the client identifier stored in the context, if any
+func ClientFromContext(ctx context.Context) string {
+	client, _ := ctx.Value(clientContextKey).(string)
+	return client
+}
+
+// WithTenant returns a context carrying the tenant the request is made for, as
+// resolved once by ResolveTenant
+func WithTenant(ctx context.Context, tenant *models.Tenant) context.Context {
+	return context.WithValue(ctx, tenantContextKey, tenant)
+}
+
+// TenantFromContext returns the ID of the tenant stored in the context, if any
+func TenantFromContext(ctx context.Context) string {
+	if tenant := requestTenant(ctx); tenant != nil {
+		return tenant.ID
+	}
+	return ""
+}
+
+// requestTenant returns the tenant stored in the context, or nil for requests
+// without a tenant
+func requestTenant(ctx context.Context) *models.Tenant {
+	tenant, _ := ctx.Value(tenantContextKey).(*models.Tenant)
+	return tenant
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"translator-service/internal/models"
+)
+
+// DeepLTranslator implements the Translator interface for the DeepL API and
+// servers compatible with it
+type DeepLTranslator struct {
+	apiKey   string
+	endpoint string
+	client   *http.Client
+}
+
+// NewDeepLTranslator creates a new DeepL translator
+func NewDeepLTranslator(apiKey, endpoint string, client *http.Client) *DeepLTranslator {
+	return &DeepLTranslator{
+		apiKey:   apiKey,
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		client:   client,
+	}
+}
+
+// Translate translates text using the DeepL translate API
+func (dt *DeepLTranslator) Translate(ctx context.Context, req *models.TranslationRequest) (*models.TranslationResponse, error) {
+	// Create the DeepL API request. The source language is given explicitly
+	// because DeepL requires it whenever a glossary is used.
+	apiReq := DeepLRequest{
+		Text:       []string{req.Text},
+		SourceLang: "EN",
+		TargetLang: deepLTargetLang(req.TargetLanguage),
+		Formality:  deepLFormality(req.Formality),
+		GlossaryID: req.Glossary,
+	}
+
+	// Convert request to JSON
+	jsonData, err := json.Marshal(apiReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	// Create HTTP request
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", dt.endpoint+"/translate", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+
+	// Set headers
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "DeepL-Auth-Key "+dt.apiKey)
+
+	// Make the API call
+	resp, err := dt.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make API call: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Read response body
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	// Check for HTTP errors
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	// Parse response
+	var apiResp DeepLResponse
+	if err := json.Unmarshal(body, &apiResp); err != nil {
+		return nil, fmt.Errorf("failed to parse API response: %w", err)
+	}
+
+	// Check for API errors
+	if apiResp.Message != "" {
+		return nil, fmt.Errorf("API error: %s", apiResp.Message)
+	}
+
+	// Extract translation from response
+	if len(apiResp.Translations) == 0 || apiResp.Translations[0].Text == "" {
+		return nil, fmt.Errorf("API returned empty translation")
+	}
+
+	return &models.TranslationResponse{
+		Original:    req.Text,
+		Translation: apiResp.Translations[0].Text,
+		Model:       req.Model,
+	}, nil
+}
+
+// deepLTargetLang maps a target language to the DeepL target language code
+func deepLTargetLang(language string) string {
+	if language == models.LanguageTraditionalChinese {
+		return "ZH-HANT"
+	}
+	return "ZH"
+}
+
+// deepLFormality maps a request formality to the DeepL option. The prefer_
+// variants are used because DeepL rejects plain more/less for target
+// languages without a formal register, which includes Chinese.
+func deepLFormality(formality string) string {
+	switch formality {
+	case "formal":
+		return "prefer_more"
+	case "informal":
+		return "prefer_less"
+	default:
+		return ""
+	}
+}
+
+// Name returns the name of the translator
+func (dt *DeepLTranslator) Name() string {
+	return "DeepL"
+}
+
+// Provider returns the provider and endpoint text is sent to
+func (dt *DeepLTranslator) Provider() models.ProviderInfo {
+	return models.ProviderInfo{Name: "deepl", Endpoint: dt.endpoint}
+}
+
+// SupportsModel returns true if the translator supports the given model
+func (dt *DeepLTranslator) SupportsModel(model string) bool {
+	return model == "deepl"
+}
+
+// DeepLRequest represents the request structure for the DeepL translate API
+type DeepLRequest struct {
+	Text       []string `json:"text"`
+	SourceLang string   `json:"source_lang"`
+	TargetLang string   `json:"target_lang"`
+	Formality  string   `json:"formality,omitempty"`
+	GlossaryID string   `json:"glossary_id,omitempty"`
+}
+
+// DeepLResponse represents the response structure from the DeepL translate API
+type DeepLResponse struct {
+	Translations []DeepLTranslation `json:"translations"`
+	Message      string             `json:"message"`
+}
+
+// DeepLTranslation represents a single translated text
+type DeepLTranslation struct {
+	DetectedSourceLanguage string `json:"detected_source_language"`
+	Text                   string `json:"text"`
+}
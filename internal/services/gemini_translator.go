@@ -0,0 +1,212 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"translator-service/internal/models"
+)
+
+// GeminiTranslator implements the Translator interface for Google Gemini models
+type GeminiTranslator struct {
+	apiKey   string
+	endpoint string
+	models   map[string]bool
+	client   *http.Client
+}
+
+// NewGeminiTranslator creates a new Gemini translator for the given models
+func NewGeminiTranslator(apiKey, endpoint string, geminiModels []string, client *http.Client) *GeminiTranslator {
+	supported := make(map[string]bool, len(geminiModels))
+	for _, model := range geminiModels {
+		supported[model] = true
+	}
+
+	return &GeminiTranslator{
+		apiKey:   apiKey,
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		models:   supported,
+		client:   client,
+	}
+}
+
+// Translate translates text using the Gemini generateContent API
+func (gt *GeminiTranslator) Translate(ctx context.Context, req *models.TranslationRequest) (*models.TranslationResponse, error) {
+	temperature, maxTokens := generationParams(req)
+	prompt := promptFor(req)
+
+	// Create the Gemini API request
+	apiReq := GeminiRequest{
+		SystemInstruction: &GeminiContent{
+			Parts: []GeminiPart{{Text: systemPrompt(prompt, req)}},
+		},
+		Contents: geminiContents(req),
+		GenerationConfig: GeminiGenerationConfig{
+			Temperature:     &temperature,
+			MaxOutputTokens: maxTokens,
+		},
+	}
+
+	// Convert request to JSON
+	jsonData, err := json.Marshal(apiReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	// Create HTTP request
+	requestURL := fmt.Sprintf("%s/models/%s:generateContent", gt.endpoint, url.PathEscape(req.Model))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", requestURL, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+
+	// Set headers
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("x-goog-api-key", gt.apiKey)
+
+	// Make the API call
+	resp, err := gt.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make API call: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Read response body
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	// Check for HTTP errors
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	// Parse response
+	var apiResp GeminiResponse
+	if err := json.Unmarshal(body, &apiResp); err != nil {
+		return nil, fmt.Errorf("failed to parse API response: %w", err)
+	}
+
+	// Check for API errors
+	if apiResp.Error.Message != "" {
+		return nil, fmt.Errorf("API error (%s): %s", apiResp.Error.Status, apiResp.Error.Message)
+	}
+
+	// Extract translation from response
+	if len(apiResp.Candidates) == 0 {
+		if apiResp.PromptFeedback.BlockReason != "" {
+			return nil, fmt.Errorf("API blocked the request: %s", apiResp.PromptFeedback.BlockReason)
+		}
+		return nil, fmt.Errorf("API returned no translation candidates")
+	}
+
+	var translation strings.Builder
+	for _, part := range apiResp.Candidates[0].Content.Parts {
+		translation.WriteString(part.Text)
+	}
+	if translation.Len() == 0 {
+		return nil, fmt.Errorf("API returned empty translation")
+	}
+
+	return &models.TranslationResponse{
+		Original:      req.Text,
+		Translation:   strings.TrimSpace(translation.String()),
+		Model:         req.Model,
+		PromptVersion: prompt.Version,
+		Usage: &models.TokenUsage{
+			PromptTokens:     apiResp.UsageMetadata.PromptTokenCount,
+			CompletionTokens: apiResp.UsageMetadata.CandidatesTokenCount,
+			TotalTokens:      apiResp.UsageMetadata.TotalTokenCount,
+		},
+	}, nil
+}
+
+// Name returns the name of the translator
+func (gt *GeminiTranslator) Name() string {
+	return "Gemini"
+}
+
+// Provider returns the provider and endpoint text is sent to
+func (gt *GeminiTranslator) Provider() models.ProviderInfo {
+	return models.ProviderInfo{Name: "gemini", Endpoint: gt.endpoint}
+}
+
+// SupportsModel returns true if the translator supports the given model
+func (gt *GeminiTranslator) SupportsModel(model string) bool {
+	return gt.models[model]
+}
+
+// GeminiRequest represents the request structure for the Gemini generateContent API
+type GeminiRequest struct {
+	SystemInstruction *GeminiContent         `json:"systemInstruction,omitempty"`
+	Contents          []GeminiContent        `json:"contents"`
+	GenerationConfig  GeminiGenerationConfig `json:"generationConfig"`
+}
+
+// geminiContents returns the session's earlier translations as user and model
+// turns, followed by the text to translate
+func geminiContents(req *models.TranslationRequest) []GeminiContent {
+	turns := sessionTurns(req)
+	contents := make([]GeminiContent, 0, len(turns)*2+1)
+	for _, turn := range turns {
+		contents = append(contents,
+			GeminiContent{Role: "user", Parts: []GeminiPart{{Text: turn.Original}}},
+			GeminiContent{Role: "model", Parts: []GeminiPart{{Text: turn.Translation}}},
+		)
+	}
+	return append(contents, GeminiContent{Role: "user", Parts: []GeminiPart{{Text: req.Text}}})
+}
+
+// GeminiContent represents a message in a Gemini conversation
+type GeminiContent struct {
+	Role  string       `json:"role,omitempty"`
+	Parts []GeminiPart `json:"parts"`
+}
+
+// GeminiPart represents a text part of a Gemini message
+type GeminiPart struct {
+	Text string `json:"text"`
+}
+
+// GeminiGenerationConfig represents the generation options for the Gemini API
+type GeminiGenerationConfig struct {
+	Temperature     *float64 `json:"temperature,omitempty"`
+	MaxOutputTokens int      `json:"maxOutputTokens,omitempty"`
+}
+
+// GeminiResponse represents the response structure from the Gemini generateContent API
+type GeminiResponse struct {
+	Candidates     []GeminiCandidate `json:"candidates"`
+	PromptFeedback struct {
+		BlockReason string `json:"blockReason"`
+	} `json:"promptFeedback"`
+	UsageMetadata GeminiUsage `json:"usageMetadata"`
+	Error         GeminiError `json:"error"`
+}
+
+// GeminiCandidate represents a single candidate in the Gemini response
+type GeminiCandidate struct {
+	Content      GeminiContent `json:"content"`
+	FinishReason string        `json:"finishReason"`
+}
+
+// GeminiUsage represents token usage information
+type GeminiUsage struct {
+	PromptTokenCount     int `json:"promptTokenCount"`
+	CandidatesTokenCount int `json:"candidatesTokenCount"`
+	TotalTokenCount      int `json:"totalTokenCount"`
+}
+
+// GeminiError represents an error returned by the API
+type GeminiError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Status  string `json:"status"`
+}
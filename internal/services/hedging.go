@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"translator-service/internal/models"
+)
+
+// hedgeMinSamples is the number of successful provider calls a model needs
+// before their latency percentile replaces the configured hedging delay
+const hedgeMinSamples = 20
+
+// hedgeResult is the outcome of one of the requests of a hedged call
+type hedgeResult struct {
+	response *models.TranslationResponse
+	err      error
+	hedge    bool
+}
+
+// callProvider sends a request to a translator. With hedging enabled, a second
+// request goes to the same or the alternate model when the first has not
+// answered within the model's usual latency; the first successful answer is
+// returned and the other request is cancelled.
+func (ts *TranslatorService) callProvider(ctx context.Context, translator models.Translator, req *models.TranslationRequest) (*models.TranslationResponse, error) {
+	hedge, hedgeReq, delay, ok := ts.hedgePlan(ctx, translator, req)
+	if !ok {
+		start := time.Now()
+		response, err := translator.Translate(ctx, req)
+		ts.recordCall(ctx, translator, req.Model, time.Since(start), err)
+		return response, err
+	}
+
+	// Each request gets its own copy, since the loser may still be reading it
+	// after the caller has moved on
+	results := make(chan hedgeResult, 2)
+	send := func(ctx context.Context, translator models.Translator, req models.TranslationRequest, isHedge bool) {
+		start := time.Now()
+		response, err := translator.Translate(ctx, &req)
+		ts.recordCall(ctx, translator, req.Model, time.Since(start), err)
+		results <- hedgeResult{response: response, err: err, hedge: isHedge}
+	}
+
+	// Whichever request loses is cancelled on return
+	primaryCtx, cancelPrimary := context.WithCancel(ctx)
+	defer cancelPrimary()
+	go send(primaryCtx, translator, *req, false)
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case result := <-results:
+		return result.response, result.err
+	case <-timer.C:
+	}
+
+	hedgeCtx, cancelHedge := context.WithCancel(ctx)
+	defer cancelHedge()
+	go send(hedgeCtx, hedge, *hedgeReq, true)
+
+	// Wait for the other request when the first to answer failed
+	var first hedgeResult
+	for i := 0; i < 2; i++ {
+		result := <-results
+		if result.err == nil {
+			ts.monitor.RecordHedge(req.Model, result.hedge)
+			return result.response, nil
+		}
+		if i == 0 {
+			first = result
+		}
+	}
+	ts.monitor.RecordHedge(req.Model, false)
+	return nil, first.err
+}
+
+// recordCall records the outcome of a call to a model's translator and, when
+// it succeeded, its latency. Only the provider call is timed, not the retries,
+// verification, cache hits and coalesced waits around it, so that hedging and
+// routing go by how fast the provider answers.
+func (ts *TranslatorService) recordCall(ctx context.Context, translator models.Translator, model string, latency time.Duration, err error) {
+	ts.recordProviderCall(ctx, translator, err)
+	if err == nil {
+		ts.monitor.RecordCallLatency(model, latency)
+	}
+}
+
+// hedgePlan returns the translator and request a hedged call sends its second
+// request with and the delay before sending it, or false when the call is not
+// hedged. Mocks answer locally and are never hedged. The alternate model is
+// only used when the request may be sent to it; otherwise the second request
+// goes to the same model.
+func (ts *TranslatorService) hedgePlan(ctx context.Context, translator models.Translator, req *models.TranslationRequest) (models.Translator, *models.TranslationRequest, time.Duration, bool) {
+	ts.mu.RLock()
+	cfg := ts.config
+	ts.mu.RUnlock()
+
+	if !cfg.HedgingEnabled {
+		return nil, nil, 0, false
+	}
+	if _, ok := translator.(models.ProviderTranslator); !ok {
+		return nil, nil, 0, false
+	}
+
+	hedge, hedgeReq := translator, req
+	if alternate := cfg.HedgingAlternates[req.Model]; alternate != "" && alternate != req.Model {
+		if t, exists := ts.translator(ctx, alternate); exists && ts.authorizeProvider(ctx, alternate, t) == nil {
+			options := *req
+			options.Model = alternate
+			if ts.validationService.ValidateGenerationOptions(&options) == nil {
+				if rendered, err := ts.renderPrompt(&options); err == nil {
+					options.RenderedPrompt = rendered
+					hedge, hedgeReq = t, &options
+				}
+			}
+		}
+	}
+
+	delay := time.Duration(cfg.HedgingDelay) * time.Millisecond
+	if latency, samples := ts.monitor.CallLatencyPercentile(req.Model, cfg.HedgingPercentile); samples >= hedgeMinSamples {
+		delay = latency
+	}
+	return hedge, hedgeReq, delay, true
+}
@@ -0,0 +1,188 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"translator-service/internal/config"
+	"translator-service/internal/models"
+)
+
+// newHedgingTestService creates a service hedging after 20ms whose gpt-4
+// requests hang until cancelled on the first call and answer at once afterwards
+func newHedgingTestService(t *testing.T) (*TranslatorService, *atomic.Int32, chan struct{}) {
+	t.Helper()
+
+	ts := NewTranslatorService(&config.Config{
+		ServerPort:        "8080",
+		Timeout:           30,
+		HedgingEnabled:    true,
+		HedgingPercentile: 95,
+		HedgingDelay:      20,
+	})
+
+	var calls atomic.Int32
+	cancelled := make(chan struct{}, 1)
+	for model, provider := range map[string]string{"gpt-4": "openai", "claude": "anthropic"} {
+		ts.translators[model] = &ProviderTranslatorForTesting{
+			MockTranslatorForTesting: MockTranslatorForTesting{
+				name: model,
+				translateFunc: func(ctx context.Context, req *models.TranslationRequest) (*models.TranslationResponse, error) {
+					if calls.Add(1) == 1 {
+						<-ctx.Done()
+						cancelled <- struct{}{}
+						return nil, ctx.Err()
+					}
+					return &models.TranslationResponse{Original: req.Text, Translation: "保存", Model: req.Model}, nil
+				},
+			},
+			provider: models.ProviderInfo{Name: provider, Endpoint: "https://api." + provider + ".com"},
+		}
+	}
+	return ts, &calls, cancelled
+}
+
+// modelStatus returns the admin status of a model
+func modelStatus(t *testing.T, ts *TranslatorService, model string) models.ModelStatus {
+	t.Helper()
+	for _, status := range ts.AdminStatus().Models {
+		if status.Name == model {
+			return status
+		}
+	}
+	t.Fatalf("Model %s not found", model)
+	return models.ModelStatus{}
+}
+
+func TestTranslatorService_Hedging(t *testing.T) {
+	ts, calls, cancelled := newHedgingTestService(t)
+
+	response, err := ts.Translate(context.Background(), &models.TranslationRequest{Text: "Save", Model: "gpt-4"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response.Model != "gpt-4" || calls.Load() != 2 {
+		t.Errorf("Expected the second gpt-4 request to answer, got %s after %d calls", response.Model, calls.Load())
+	}
+
+	// The slow request is cancelled once the second one has answered
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the slow request to be cancelled")
+	}
+
+	status := modelStatus(t, ts, "gpt-4")
+	if status.Hedges != 1 || status.HedgeWins != 1 {
+		t.Errorf("Expected one hedge won, got %+v", status)
+	}
+
+	// Requests answering in time are not hedged
+	if _, err := ts.Translate(context.Background(), &models.TranslationRequest{Text: "Save", Model: "gpt-4"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if calls.Load() != 3 || modelStatus(t, ts, "gpt-4").Hedges != 1 {
+		t.Errorf("Expected no hedge for a fast answer, got %d calls", calls.Load())
+	}
+}
+
+func TestTranslatorService_HedgingAlternate(t *testing.T) {
+	ts, _, cancelled := newHedgingTestService(t)
+	cfg := *ts.Config()
+	cfg.HedgingAlternates = map[string]string{"gpt-4": "claude"}
+	cfg.Tenants = map[string]config.TenantConfig{"acme": {AllowedProviders: []string{"openai"}}}
+	ts.config = &cfg
+
+	response, err := ts.Translate(context.Background(), &models.TranslationRequest{Text: "Save", Model: "gpt-4"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response.Model != "claude" {
+		t.Errorf("Expected the alternate model to answer, got %s", response.Model)
+	}
+	<-cancelled
+
+	// The alternate is skipped when the tenant may not use it
+	ctx := tenantContext(t, ts, "acme")
+	hedge, hedgeReq, _, ok := ts.hedgePlan(ctx, ts.translators["gpt-4"], &models.TranslationRequest{Text: "Save", Model: "gpt-4"})
+	if !ok || hedge != ts.translators["gpt-4"] || hedgeReq.Model != "gpt-4" {
+		t.Errorf("Expected the second request to go to gpt-4 for the tenant, got %v", hedgeReq)
+	}
+}
+
+func TestTranslatorService_HedgingFailures(t *testing.T) {
+	ts, _, _ := newHedgingTestService(t)
+	ts.translators["gpt-4"].(*ProviderTranslatorForTesting).translateFunc = func(ctx context.Context, req *models.TranslationRequest) (*models.TranslationResponse, error) {
+		time.Sleep(30 * time.Millisecond)
+		return nil, errors.New("validation error: rejected")
+	}
+
+	if _, err := ts.Translate(context.Background(), &models.TranslationRequest{Text: "Save", Model: "gpt-4"}); err == nil {
+		t.Fatal("Expected error when both requests fail")
+	}
+	if status := modelStatus(t, ts, "gpt-4"); status.Hedges != 1 || status.HedgeWins != 0 {
+		t.Errorf("Expected one hedge lost, got %+v", status)
+	}
+}
+
+func TestTranslatorService_HedgePlan(t *testing.T) {
+	ts, _, _ := newHedgingTestService(t)
+	req := &models.TranslationRequest{Text: "Save", Model: "gpt-4"}
+
+	// The configured delay applies until the model has enough provider calls;
+	// end-to-end request latencies do not count
+	for i := 1; i <= hedgeMinSamples; i++ {
+		ts.monitor.RecordRequest("gpt-4", "", time.Minute, nil)
+	}
+	if _, _, delay, ok := ts.hedgePlan(context.Background(), ts.translators["gpt-4"], req); !ok || delay != 20*time.Millisecond {
+		t.Errorf("Expected the configured delay, got %v", delay)
+	}
+	for i := 1; i <= hedgeMinSamples; i++ {
+		ts.monitor.RecordCallLatency("gpt-4", time.Duration(i)*10*time.Millisecond)
+	}
+	if _, _, delay, _ := ts.hedgePlan(context.Background(), ts.translators["gpt-4"], req); delay != 190*time.Millisecond {
+		t.Errorf("Expected the p95 latency, got %v", delay)
+	}
+
+	// Mocks are not hedged, nor is anything with hedging disabled
+	if _, _, _, ok := ts.hedgePlan(context.Background(), &MockTranslatorForTesting{name: "mock"}, req); ok {
+		t.Errorf("Expected mocks not to be hedged")
+	}
+	cfg := *ts.Config()
+	cfg.HedgingEnabled = false
+	ts.config = &cfg
+	if _, _, _, ok := ts.hedgePlan(context.Background(), ts.translators["gpt-4"], req); ok {
+		t.Errorf("Expected no hedging when disabled")
+	}
+}
+
+func TestTranslatorService_RecordsCallLatency(t *testing.T) {
+	ts := NewTranslatorService(&config.Config{ServerPort: "8080", Timeout: 30})
+
+	// Fail twice, then answer; only the successful call is timed
+	calls := 0
+	ts.translators["test-model"] = &MockTranslatorForTesting{
+		name: "test-model",
+		translateFunc: func(ctx context.Context, req *models.TranslationRequest) (*models.TranslationResponse, error) {
+			calls++
+			if calls < 3 {
+				return nil, errors.New("temporary error")
+			}
+			return &models.TranslationResponse{Original: req.Text, Translation: "保存", Model: req.Model}, nil
+		},
+	}
+
+	if _, err := ts.Translate(context.Background(), &models.TranslationRequest{Text: "Save", Model: "test-model"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	call, samples := ts.monitor.CallLatencyPercentile("test-model", 95)
+	if samples != 1 {
+		t.Fatalf("Expected one provider call latency, got %d", samples)
+	}
+	if request := modelStatus(t, ts, "test-model").P95LatencyMs; call.Milliseconds() >= request {
+		t.Errorf("Expected the provider call (%v) to be faster than the request with its retries (%dms)", call, request)
+	}
+}
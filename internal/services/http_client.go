@@ -0,0 +1,66 @@
+package services
+
+import (
+	"crypto/tls"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"translator-service/internal/config"
+)
+
+// newHTTPClient creates the HTTP client shared by all providers. Its transport
+// pools connections per provider host and applies the proxy, TLS and HTTP/2
+// settings of the configuration.
+//
+// The client has no timeout of its own: a call ends with the context of the
+// translation it is made for, whose deadline of llm.timeout covers all of its
+// calls together, retries and hedged requests included. Only opening a
+// connection has its own limits, the dial timeout and the TLS handshake
+// timeout, so that an unreachable provider fails in time for a retry.
+func newHTTPClient(cfg *config.Config) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   cfg.GetHTTPDialTimeout(),
+		KeepAlive: cfg.GetHTTPKeepAlive(),
+	}
+
+	transport := &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     !cfg.HTTPDisableHTTP2,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   cfg.GetHTTPMaxIdleConnsPerHost(),
+		IdleConnTimeout:       cfg.GetHTTPIdleConnTimeout(),
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: time.Second,
+		TLSClientConfig:       &tls.Config{},
+	}
+	if cfg.HTTPDisableHTTP2 {
+		// A non-nil map keeps the transport from upgrading to HTTP/2
+		transport.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
+	}
+
+	// The settings were validated when the configuration was loaded; should a
+	// file have changed since, the transport falls back to the defaults
+	if cfg.HTTPProxy != "" {
+		if proxy, err := url.Parse(cfg.HTTPProxy); err == nil {
+			transport.Proxy = http.ProxyURL(proxy)
+		} else {
+			log.Printf("Ignoring invalid HTTP proxy: %v", err)
+		}
+	}
+	if version, err := config.TLSVersion(cfg.HTTPTLSMinVersion); err == nil {
+		transport.TLSClientConfig.MinVersion = version
+	}
+	if cfg.HTTPCABundle != "" {
+		if pool, err := config.LoadCABundle(cfg.HTTPCABundle); err == nil {
+			transport.TLSClientConfig.RootCAs = pool
+		} else {
+			log.Printf("Using system CA certificates only: %v", err)
+		}
+	}
+
+	return &http.Client{Transport: transport}
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"translator-service/internal/config"
+)
+
+func TestNewHTTPClient_CABundle(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// The test server's certificate is only trusted once it is in the bundle
+	if resp, err := newHTTPClient(&config.Config{Timeout: 30}).Get(server.URL); err == nil {
+		resp.Body.Close()
+		t.Fatal("Expected the test server's certificate to be rejected")
+	}
+
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(bundle, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := newHTTPClient(&config.Config{Timeout: 30, HTTPCABundle: bundle}).Get(server.URL)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	resp.Body.Close()
+}
+
+func TestNewHTTPClient_Proxy(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer proxy.Close()
+
+	// Calls are bounded by the context of their translation, not by the client
+	client := newHTTPClient(&config.Config{Timeout: 45, HTTPProxy: proxy.URL})
+	if client.Timeout != 0 {
+		t.Errorf("Expected no client timeout, got %s", client.Timeout)
+	}
+
+	resp, err := client.Get("http://provider.invalid/v1/chat/completions")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	if proxied != "http://provider.invalid/v1/chat/completions" {
+		t.Errorf("Expected the request to go through the proxy, got %q", proxied)
+	}
+}
+
+func TestTranslatorService_SharesHTTPClient(t *testing.T) {
+	ts := NewTranslatorService(&config.Config{ServerPort: "8080", Timeout: 30, OpenAIKey: "key", AnthropicKey: "key", LocalEndpoint: "http://localhost:11434"})
+
+	openai := ts.translators["gpt-4"].(*OpenAITranslator)
+	anthropic := ts.translators["claude"].(*AnthropicTranslator)
+	ollama := ts.translators["llama"].(*OllamaTranslator)
+	if openai.client != ts.httpClient || anthropic.client != ts.httpClient || ollama.client != ts.httpClient {
+		t.Errorf("Expected all providers to share the service's HTTP client")
+	}
+
+	// A reload builds a client from the new configuration
+	previous := ts.httpClient
+	ts.Reload(&config.Config{ServerPort: "8080", Timeout: 60, OpenAIKey: "key"}, nil)
+	if ts.httpClient == previous {
+		t.Errorf("Expected a new HTTP client after the reload")
+	}
+	if ts.translators["gpt-4"].(*OpenAITranslator).client != ts.httpClient {
+		t.Errorf("Expected reloaded providers to use the new HTTP client")
+	}
+}
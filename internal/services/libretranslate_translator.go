@@ -0,0 +1,137 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"translator-service/internal/models"
+)
+
+// LibreTranslateTranslator implements the Translator interface for
+// LibreTranslate-compatible servers. LibreTranslate has no formality or
+// glossary options, so those request fields are ignored.
+type LibreTranslateTranslator struct {
+	apiKey   string
+	endpoint string
+	client   *http.Client
+}
+
+// NewLibreTranslateTranslator creates a new LibreTranslate translator.
+// The API key is optional because self-hosted servers usually run without one.
+func NewLibreTranslateTranslator(apiKey, endpoint string, client *http.Client) *LibreTranslateTranslator {
+	return &LibreTranslateTranslator{
+		apiKey:   apiKey,
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		client:   client,
+	}
+}
+
+// Translate translates text using the LibreTranslate translate API
+func (lt *LibreTranslateTranslator) Translate(ctx context.Context, req *models.TranslationRequest) (*models.TranslationResponse, error) {
+	// Create the LibreTranslate API request
+	apiReq := LibreTranslateRequest{
+		Q:      req.Text,
+		Source: "en",
+		Target: libreTranslateTarget(req.TargetLanguage),
+		Format: "text",
+		APIKey: lt.apiKey,
+	}
+
+	// Convert request to JSON
+	jsonData, err := json.Marshal(apiReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	// Create HTTP request
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", lt.endpoint+"/translate", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+
+	// Set headers
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	// Make the API call
+	resp, err := lt.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make API call: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Read response body
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	// Check for HTTP errors
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	// Parse response
+	var apiResp LibreTranslateResponse
+	if err := json.Unmarshal(body, &apiResp); err != nil {
+		return nil, fmt.Errorf("failed to parse API response: %w", err)
+	}
+
+	// Check for API errors
+	if apiResp.Error != "" {
+		return nil, fmt.Errorf("API error: %s", apiResp.Error)
+	}
+
+	// Extract translation from response
+	if apiResp.TranslatedText == "" {
+		return nil, fmt.Errorf("API returned empty translation")
+	}
+
+	return &models.TranslationResponse{
+		Original:    req.Text,
+		Translation: apiResp.TranslatedText,
+		Model:       req.Model,
+	}, nil
+}
+
+// Name returns the name of the translator
+func (lt *LibreTranslateTranslator) Name() string {
+	return "LibreTranslate"
+}
+
+// Provider returns the provider and endpoint text is sent to
+func (lt *LibreTranslateTranslator) Provider() models.ProviderInfo {
+	return models.ProviderInfo{Name: "libretranslate", Endpoint: lt.endpoint}
+}
+
+// SupportsModel returns true if the translator supports the given model
+func (lt *LibreTranslateTranslator) SupportsModel(model string) bool {
+	return model == "libretranslate"
+}
+
+// LibreTranslateRequest represents the request structure for the LibreTranslate API
+type LibreTranslateRequest struct {
+	Q      string `json:"q"`
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Format string `json:"format"`
+	APIKey string `json:"api_key,omitempty"`
+}
+
+// LibreTranslateResponse represents the response structure from the LibreTranslate API
+type LibreTranslateResponse struct {
+	TranslatedText string `json:"translatedText"`
+	Error          string `json:"error"`
+}
+
+// libreTranslateTarget maps a target language to the LibreTranslate language code
+func libreTranslateTarget(language string) string {
+	if language == models.LanguageTraditionalChinese {
+		return "zt"
+	}
+	return "zh"
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"translator-service/internal/models"
+)
+
+// LlamaCppTranslator implements the Translator interface for a llama.cpp server
+type LlamaCppTranslator struct {
+	endpoint string
+	client   *http.Client
+}
+
+// NewLlamaCppTranslator creates a new llama.cpp translator
+func NewLlamaCppTranslator(endpoint string, client *http.Client) *LlamaCppTranslator {
+	return &LlamaCppTranslator{
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		client:   client,
+	}
+}
+
+// Translate translates text using the llama.cpp completion API
+func (lt *LlamaCppTranslator) Translate(ctx context.Context, req *models.TranslationRequest) (*models.TranslationResponse, error) {
+	temperature, maxTokens := generationParams(req)
+	prompt := promptFor(req)
+
+	// Create the llama.cpp API request
+	apiReq := LlamaCppRequest{
+		Prompt:      completionPrompt(prompt, req),
+		NPredict:    maxTokens,
+		Temperature: temperature,
+	}
+	if !prompt.Unframed {
+		// Stop before the model invents the next pair
+		apiReq.Stop = []string{"\nEnglish:"}
+	}
+
+	// Convert request to JSON
+	jsonData, err := json.Marshal(apiReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	// Create HTTP request
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", lt.endpoint+"/completion", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+
+	// Set headers
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	// Make the API call
+	resp, err := lt.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make API call: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Read response body
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	// Check for HTTP errors
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	// Parse response
+	var apiResp LlamaCppResponse
+	if err := json.Unmarshal(body, &apiResp); err != nil {
+		return nil, fmt.Errorf("failed to parse API response: %w", err)
+	}
+
+	// Extract translation from response
+	translation := strings.TrimSpace(apiResp.Content)
+	if translation == "" {
+		return nil, fmt.Errorf("API returned empty translation")
+	}
+
+	return &models.TranslationResponse{
+		Original:      req.Text,
+		Translation:   translation,
+		Model:         req.Model,
+		PromptVersion: prompt.Version,
+		Usage: &models.TokenUsage{
+			PromptTokens:     apiResp.TokensEvaluated,
+			CompletionTokens: apiResp.TokensPredicted,
+			TotalTokens:      apiResp.TokensEvaluated + apiResp.TokensPredicted,
+		},
+	}, nil
+}
+
+// Name returns the name of the translator
+func (lt *LlamaCppTranslator) Name() string {
+	return "llama.cpp"
+}
+
+// Provider returns the provider and endpoint text is sent to
+func (lt *LlamaCppTranslator) Provider() models.ProviderInfo {
+	return models.ProviderInfo{Name: "llamacpp", Endpoint: lt.endpoint}
+}
+
+// SupportsModel returns true if the translator supports the given model
+func (lt *LlamaCppTranslator) SupportsModel(model string) bool {
+	return model == "llama"
+}
+
+// LlamaCppRequest represents the request structure for the llama.cpp completion API
+type LlamaCppRequest struct {
+	Prompt      string   `json:"prompt"`
+	NPredict    int      `json:"n_predict"`
+	Temperature float64  `json:"temperature"`
+	Stop        []string `json:"stop,omitempty"`
+}
+
+// LlamaCppResponse represents the response structure from the llama.cpp completion API
+type LlamaCppResponse struct {
+	Content         string `json:"content"`
+	Stop            bool   `json:"stop"`
+	TokensEvaluated int    `json:"tokens_evaluated"`
+	TokensPredicted int    `json:"tokens_predicted"`
+}
@@ -0,0 +1,251 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"translator-service/internal/config"
+	"translator-service/internal/models"
+)
+
+func TestOllamaTranslator_Translate(t *testing.T) {
+	// Stand in for an Ollama server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/chat" {
+			t.Errorf("Expected request to /api/chat, got %s", r.URL.Path)
+		}
+
+		var req OllamaRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+		if req.Model != "llama3:8b" {
+			t.Errorf("Expected model tag llama3:8b, got %s", req.Model)
+		}
+		if req.Stream {
+			t.Errorf("Expected streaming to be disabled")
+		}
+		if len(req.Messages) != 2 || req.Messages[1].Content != "Hello, world!" {
+			t.Errorf("Unexpected messages: %+v", req.Messages)
+		}
+
+		json.NewEncoder(w).Encode(OllamaResponse{
+			Model:           "llama3:8b",
+			Message:         Message{Role: "assistant", Content: "你好，世界！\n"},
+			Done:            true,
+			PromptEvalCount: 30,
+			EvalCount:       5,
+		})
+	}))
+	defer server.Close()
+
+	translator := NewOllamaTranslator(server.URL+"/", "llama3:8b", server.Client())
+	response, err := translator.Translate(context.Background(), &models.TranslationRequest{Text: "Hello, world!", Model: "llama"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if response.Translation != "你好，世界！" {
+		t.Errorf("Expected trimmed translation, got %q", response.Translation)
+	}
+	if response.Model != "llama" {
+		t.Errorf("Expected requested model in response, got %s", response.Model)
+	}
+	if response.Usage == nil || response.Usage.TotalTokens != 35 {
+		t.Errorf("Expected token usage of 35, got %+v", response.Usage)
+	}
+}
+
+func TestOllamaTranslator_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":"model 'llama3:70b' not found"}`))
+	}))
+	defer server.Close()
+
+	translator := NewOllamaTranslator(server.URL, "llama3:70b", server.Client())
+	_, err := translator.Translate(context.Background(), &models.TranslationRequest{Text: "Hello", Model: "llama"})
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Expected model not found error, got %v", err)
+	}
+}
+
+func TestLlamaCppTranslator_Translate(t *testing.T) {
+	// Stand in for a llama.cpp server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/completion" {
+			t.Errorf("Expected request to /completion, got %s", r.URL.Path)
+		}
+
+		var req LlamaCppRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+		if !strings.Contains(req.Prompt, "English: Good morning") {
+			t.Errorf("Expected prompt to contain the text, got %q", req.Prompt)
+		}
+
+		json.NewEncoder(w).Encode(LlamaCppResponse{
+			Content:         " 早上好",
+			Stop:            true,
+			TokensEvaluated: 25,
+			TokensPredicted: 3,
+		})
+	}))
+	defer server.Close()
+
+	translator := NewLlamaCppTranslator(server.URL, server.Client())
+	response, err := translator.Translate(context.Background(), &models.TranslationRequest{Text: "Good morning", Model: "llama"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if response.Translation != "早上好" {
+		t.Errorf("Expected trimmed translation, got %q", response.Translation)
+	}
+	if response.Usage == nil || response.Usage.PromptTokens != 25 {
+		t.Errorf("Expected prompt token usage of 25, got %+v", response.Usage)
+	}
+}
+
+func TestLlamaCppTranslator_HTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "model is loading", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	translator := NewLlamaCppTranslator(server.URL, server.Client())
+	_, err := translator.Translate(context.Background(), &models.TranslationRequest{Text: "Hello", Model: "llama"})
+	if err == nil || !strings.Contains(err.Error(), "503") {
+		t.Errorf("Expected status 503 error, got %v", err)
+	}
+}
+
+func TestTranslatorService_LocalProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(OllamaResponse{Message: Message{Role: "assistant", Content: "你好"}, Done: true})
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		ServerPort:    "8080",
+		Timeout:       30,
+		LocalProvider: "ollama",
+		LocalEndpoint: server.URL,
+		LocalModel:    "qwen2:7b",
+	}
+
+	ts := NewTranslatorService(cfg)
+
+	// Both the llama alias and the model tag route to the local server
+	for _, model := range []string{"llama", "qwen2:7b"} {
+		response, err := ts.Translate(context.Background(), &models.TranslationRequest{Text: "Hello", Model: model})
+		if err != nil {
+			t.Fatalf("Unexpected error for %s: %v", model, err)
+		}
+		if response.Translation != "你好" {
+			t.Errorf("Expected translation from local server for %s, got %q", model, response.Translation)
+		}
+	}
+}
+
+func TestTranslatorService_LlamaCppBackTranslation(t *testing.T) {
+	// Stand in for a llama.cpp server that records the prompts it receives
+	var prompts []LlamaCppRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req LlamaCppRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+		prompts = append(prompts, req)
+
+		content := "保存您的更改。"
+		if len(prompts) > 1 {
+			content = "Save your changes."
+		}
+		json.NewEncoder(w).Encode(LlamaCppResponse{Content: content, Stop: true})
+	}))
+	defer server.Close()
+
+	ts := NewTranslatorService(&config.Config{
+		ServerPort:    "8080",
+		Timeout:       30,
+		LocalProvider: "llamacpp",
+		LocalEndpoint: server.URL,
+	})
+
+	response, err := ts.Translate(context.Background(), &models.TranslationRequest{Text: "Save your changes.", Model: "llama", Verify: "back_translation"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response.Verification == nil || response.Verification.BackTranslation != "Save your changes." {
+		t.Fatalf("Unexpected verification: %+v", response.Verification)
+	}
+	if len(prompts) != 2 {
+		t.Fatalf("Expected a translation and a back-translation, got %d requests", len(prompts))
+	}
+
+	// The translation is framed as an English to Chinese pair
+	if !strings.HasSuffix(prompts[0].Prompt, "English: Save your changes.\n\nChinese:") {
+		t.Errorf("Unexpected translation prompt: %q", prompts[0].Prompt)
+	}
+
+	// The back-translation sends the Chinese text without that framing
+	back := prompts[1]
+	if !strings.HasSuffix(back.Prompt, "\n\n保存您的更改。") || strings.Contains(back.Prompt, "English:") || strings.Contains(back.Prompt, "Chinese:") {
+		t.Errorf("Unexpected back-translation prompt: %q", back.Prompt)
+	}
+	if len(back.Stop) != 0 {
+		t.Errorf("Expected no translation stop sequence, got %v", back.Stop)
+	}
+}
+
+func TestTranslatorService_LlamaCppJudge(t *testing.T) {
+	// Stand in for a llama.cpp server that translates and reviews translations
+	var judged *LlamaCppRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req LlamaCppRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+
+		content := "保存您的更改。"
+		if strings.HasPrefix(req.Prompt, "You are a professional reviewer") {
+			judged = &req
+			content = `{"score": 90, "issues": []}`
+		}
+		json.NewEncoder(w).Encode(LlamaCppResponse{Content: content, Stop: true})
+	}))
+	defer server.Close()
+
+	ts := NewTranslatorService(&config.Config{
+		ServerPort:        "8080",
+		Timeout:           30,
+		LocalProvider:     "llamacpp",
+		LocalEndpoint:     server.URL,
+		QualityJudgeModel: "llama",
+	})
+
+	response, err := ts.Translate(context.Background(), &models.TranslationRequest{Text: "Save your changes.", Model: "llama", Quality: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response.Quality == nil || response.Quality.Judge != "llama" {
+		t.Fatalf("Expected an estimate from the judge, got %+v", response.Quality)
+	}
+
+	// The judge gets the pair to review, not a translation prompt
+	if judged == nil {
+		t.Fatal("Expected the judge to be called")
+	}
+	if !strings.HasSuffix(judged.Prompt, "\n\nEnglish:\nSave your changes.\n\nChinese:\n保存您的更改。") {
+		t.Errorf("Unexpected judge prompt: %q", judged.Prompt)
+	}
+	if strings.Contains(judged.Prompt, "English: ") || strings.HasSuffix(judged.Prompt, "Chinese:") || len(judged.Stop) != 0 {
+		t.Errorf("Expected no translation framing, got %q (stop %v)", judged.Prompt, judged.Stop)
+	}
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"unicode/utf8"
+
+	"translator-service/internal/models"
+)
+
+// maxShortenAttempts is how many times a translation that exceeds max_length is re-requested
+const maxShortenAttempts = 2
+
+// enforceMaxLength re-requests a shorter translation while the response exceeds
+// the max_length of the request's context. It gives up early when the provider
+// returns the same translation again, as machine translation engines do.
+func (ts *TranslatorService) enforceMaxLength(ctx context.Context, translator models.Translator, req *models.TranslationRequest, response *models.TranslationResponse) (*models.TranslationResponse, error) {
+	if req.Context == nil || req.Context.MaxLength == 0 {
+		return response, nil
+	}
+	maxLength := req.Context.MaxLength
+
+	for attempt := 0; attempt < maxShortenAttempts && utf8.RuneCountInString(response.Translation) > maxLength; attempt++ {
+		log.Printf("Translation with %s is %d characters, over max_length %d; requesting a shorter one",
+			req.Model, utf8.RuneCountInString(response.Translation), maxLength)
+
+		shorter, err := ts.shortenRequest(req, response.Translation)
+		if err != nil {
+			return nil, err
+		}
+
+		retry, err := ts.translateWithRetry(ctx, translator, shorter)
+		if err != nil {
+			return nil, err
+		}
+		retry.Usage = addUsage(response.Usage, retry.Usage)
+
+		if retry.Translation == response.Translation {
+			response = retry
+			break
+		}
+		response = retry
+	}
+
+	if length := utf8.RuneCountInString(response.Translation); length > maxLength {
+		return nil, fmt.Errorf("translation with %s exceeds max_length: %d characters, limit %d", req.Model, length, maxLength)
+	}
+
+	return response, nil
+}
+
+// shortenRequest returns a copy of the request whose prompt asks for a
+// translation shorter than a previous one
+func (ts *TranslatorService) shortenRequest(req *models.TranslationRequest, previous string) (*models.TranslationRequest, error) {
+	shorter := *req
+	shorter.Instructions = strings.TrimSpace(fmt.Sprintf("%s The translation %q is %d characters long, which is too long. Give a shorter translation of at most %d characters.",
+		req.Instructions, previous, utf8.RuneCountInString(previous), req.Context.MaxLength))
+
+	rendered, err := ts.renderPrompt(&shorter)
+	if err != nil {
+		return nil, err
+	}
+	shorter.RenderedPrompt = rendered
+
+	return &shorter, nil
+}
+
+// addUsage returns the sum of two token usages, either of which may be nil
+func addUsage(a, b *models.TokenUsage) *models.TokenUsage {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	return &models.TokenUsage{
+		PromptTokens:     a.PromptTokens + b.PromptTokens,
+		CompletionTokens: a.CompletionTokens + b.CompletionTokens,
+		TotalTokens:      a.TotalTokens + b.TotalTokens,
+	}
+}
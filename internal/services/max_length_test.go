@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"translator-service/internal/config"
+	"translator-service/internal/models"
+)
+
+func TestTranslatorService_EnforceMaxLength(t *testing.T) {
+	tests := []struct {
+		name          string
+		translations  []string
+		maxLength     int
+		expected      string
+		expectedCalls int
+		expectError   bool
+	}{
+		{
+			name:          "Within limit",
+			translations:  []string{"打开"},
+			maxLength:     4,
+			expected:      "打开",
+			expectedCalls: 1,
+		},
+		{
+			name:          "Shortened on retry",
+			translations:  []string{"打开这个文件", "打开"},
+			maxLength:     4,
+			expected:      "打开",
+			expectedCalls: 2,
+		},
+		{
+			name:          "Same translation returned again",
+			translations:  []string{"打开这个文件", "打开这个文件"},
+			maxLength:     4,
+			expectedCalls: 2,
+			expectError:   true,
+		},
+		{
+			name:          "Never short enough",
+			translations:  []string{"打开这个文件", "打开这文件", "打开文件"},
+			maxLength:     2,
+			expectedCalls: 3,
+			expectError:   true,
+		},
+		{
+			name:          "No limit",
+			translations:  []string{"打开这个文件"},
+			maxLength:     0,
+			expected:      "打开这个文件",
+			expectedCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := NewTranslatorService(&config.Config{ServerPort: "8080", Timeout: 30})
+
+			// Create a translator that returns the scripted translations in order
+			var prompts []string
+			ts.translators["test-model"] = &MockTranslatorForTesting{
+				name: "Test Model",
+				translateFunc: func(ctx context.Context, req *models.TranslationRequest) (*models.TranslationResponse, error) {
+					translation := tt.translations[len(prompts)]
+					prompts = append(prompts, promptFor(req).Text)
+					return &models.TranslationResponse{
+						Original:    req.Text,
+						Translation: translation,
+						Model:       req.Model,
+						Usage:       &models.TokenUsage{PromptTokens: 10, CompletionTokens: 2, TotalTokens: 12},
+					}, nil
+				},
+			}
+
+			req := &models.TranslationRequest{
+				Text:    "Open",
+				Model:   "test-model",
+				Context: &models.TranslationContext{Screen: "File menu", MaxLength: tt.maxLength},
+			}
+			response, err := ts.Translate(context.Background(), req)
+
+			if len(prompts) != tt.expectedCalls {
+				t.Errorf("Expected %d provider calls, got %d", tt.expectedCalls, len(prompts))
+			}
+			if tt.expectError {
+				if err == nil || !strings.Contains(err.Error(), "exceeds max_length") {
+					t.Errorf("Expected max_length error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if response.Translation != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, response.Translation)
+			}
+			if response.Usage.TotalTokens != 12*tt.expectedCalls {
+				t.Errorf("Expected usage summed over %d calls, got %+v", tt.expectedCalls, response.Usage)
+			}
+			for _, prompt := range prompts[1:] {
+				if !strings.Contains(prompt, "which is too long") {
+					t.Errorf("Expected retry prompt to ask for a shorter translation, got %q", prompt)
+				}
+			}
+			if req.Instructions != "" {
+				t.Errorf("Expected the caller's request to be left untouched")
+			}
+		})
+	}
+}
@@ -36,6 +36,8 @@ func (mt *MockTranslator) Translate(ctx context.Context, req *models.Translation
 		Original:    req.Text,
 		Translation: translation,
 		Model:       mt.name,
+		// Mocks stand in for LLM providers, so they report the prompt they would have used
+		PromptVersion: promptFor(req).Version,
 	}, nil
 }
 
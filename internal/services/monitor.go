@@ -0,0 +1,328 @@
+package services
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"translator-service/internal/models"
+)
+
+const (
+	// maxRecentErrors is the number of failed requests kept for the admin dashboard
+	maxRecentErrors = 50
+
+	// volumeMinutes is the number of minutes covered by the request volume
+	volumeMinutes = 60
+
+	// providerDownAfter is the number of consecutive failures after which a provider is down
+	providerDownAfter = 3
+
+	// latencySamples is the number of successful requests, and of successful
+	// provider calls, per model that latency percentiles are taken from
+	latencySamples = 100
+
+	// unknownModel counts the requests for models that are not registered, so
+	// that clients cannot add counters by sending arbitrary model names
+	unknownModel = "unknown"
+)
+
+// Monitor collects the request volume, per-model counters, provider health
+// and recent errors of the running service. It is kept in memory only.
+type Monitor struct {
+	mu        sync.Mutex
+	startedAt time.Time
+	now       func() time.Time
+
+	total     int64
+	errors    int64
+	coalesced int64
+	models    map[string]*modelCounters
+	providers map[models.ProviderInfo]*models.ProviderHealth
+	recent    []models.ErrorEvent
+
+	// minutes holds the start of the minute each volume bucket counts
+	minutes [volumeMinutes]int64
+	volume  [volumeMinutes]int64
+}
+
+// modelCounters are the requests made with one model
+type modelCounters struct {
+	requests  int64
+	errors    int64
+	latencyMs int64
+
+	// latencies holds the end-to-end latency of the latest successful requests,
+	// including retries, verification and cached or coalesced answers
+	latencies latencySeries
+	// callLatencies holds the latency of the latest successful calls to the
+	// model's provider alone, which hedging and routing go by
+	callLatencies latencySeries
+
+	// hedges counts the requests sent a second time, hedgeWins those the second request answered
+	hedges    int64
+	hedgeWins int64
+}
+
+// latencySeries holds the latest latencySamples latencies, overwritten from next on
+type latencySeries struct {
+	samples []time.Duration
+	next    int
+}
+
+// add records a latency, replacing the oldest once the series is full
+func (s *latencySeries) add(latency time.Duration) {
+	if len(s.samples) < latencySamples {
+		s.samples = append(s.samples, latency)
+	} else {
+		s.samples[s.next] = latency
+	}
+	s.next = (s.next + 1) % latencySamples
+}
+
+// percentile returns the given percentile of the latest latencies, or zero without any
+func (s *latencySeries) percentile(p float64) time.Duration {
+	if len(s.samples) == 0 {
+		return 0
+	}
+	sorted := make([]time.Duration, len(s.samples))
+	copy(sorted, s.samples)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+	rank := int(math.Ceil(float64(len(sorted)) * p / 100))
+	return sorted[min(max(rank, 1), len(sorted))-1]
+}
+
+// NewMonitor creates a monitor starting now
+func NewMonitor() *Monitor {
+	return &Monitor{
+		startedAt: time.Now().UTC(),
+		now:       time.Now,
+		models:    make(map[string]*modelCounters),
+		providers: make(map[models.ProviderInfo]*models.ProviderHealth),
+	}
+}
+
+// RecordRequest counts a translation request and keeps its error, if any
+func (m *Monitor) RecordRequest(model, tenant string, latency time.Duration, err error) {
+	now := m.now().UTC()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.total++
+	counters := m.modelLocked(model)
+	counters.requests++
+	counters.latencyMs += latency.Milliseconds()
+
+	minute := now.Unix() / 60
+	slot := minute % volumeMinutes
+	if m.minutes[slot] != minute {
+		m.minutes[slot] = minute
+		m.volume[slot] = 0
+	}
+	m.volume[slot]++
+
+	if err == nil {
+		counters.latencies.add(latency)
+		return
+	}
+	m.errors++
+	counters.errors++
+
+	m.recent = append(m.recent, models.ErrorEvent{Time: now, Model: model, Tenant: tenant, Message: err.Error()})
+	if len(m.recent) > maxRecentErrors {
+		m.recent = m.recent[len(m.recent)-maxRecentErrors:]
+	}
+}
+
+// RecordCoalesced counts a request that shared the provider call of an
+// identical request in flight instead of making its own
+func (m *Monitor) RecordCoalesced() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.coalesced++
+}
+
+// RecordProviderCall records the outcome of a call to a provider
+func (m *Monitor) RecordProviderCall(provider models.ProviderInfo, err error) {
+	now := m.now().UTC()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	health := m.providerLocked(provider)
+	health.Calls++
+	if err == nil {
+		health.ConsecutiveFailures = 0
+		health.LastSuccess = &now
+	} else {
+		health.Failures++
+		health.ConsecutiveFailures++
+		health.LastFailure = &now
+		health.LastError = err.Error()
+	}
+	health.Status = providerStatus(health)
+}
+
+// RecordCallLatency records the latency of a successful call to a model's provider
+func (m *Monitor) RecordCallLatency(model string, latency time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.modelLocked(model).callLatencies.add(latency)
+}
+
+// RecordHedge counts a request to a model that was sent a second time, and
+// whether the second request answered first
+func (m *Monitor) RecordHedge(model string, won bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	counters := m.modelLocked(model)
+	counters.hedges++
+	if won {
+		counters.hedgeWins++
+	}
+}
+
+// modelLocked returns the counters of a model, adding them if they are new.
+// m.mu must be held.
+func (m *Monitor) modelLocked(model string) *modelCounters {
+	counters, ok := m.models[model]
+	if !ok {
+		counters = &modelCounters{}
+		m.models[model] = counters
+	}
+	return counters
+}
+
+// CallLatencyP95 returns the 95th percentile latency of the latest successful
+// calls to a model's provider, or zero before the first one
+func (m *Monitor) CallLatencyP95(model string) time.Duration {
+	latency, _ := m.CallLatencyPercentile(model, 95)
+	return latency
+}
+
+// CallLatencyPercentile returns a percentile of the latency of the latest
+// successful calls to a model's provider along with the number of calls it is
+// taken from
+func (m *Monitor) CallLatencyPercentile(model string, p float64) (time.Duration, int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if counters, ok := m.models[model]; ok {
+		return counters.callLatencies.percentile(p), len(counters.callLatencies.samples)
+	}
+	return 0, 0
+}
+
+// ProviderStatus returns the health status of a provider
+func (m *Monitor) ProviderStatus(provider models.ProviderInfo) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if health, ok := m.providers[provider]; ok {
+		return health.Status
+	}
+	return models.ProviderUnknown
+}
+
+// providerLocked returns the health of a provider, adding it if it is new.
+// m.mu must be held.
+func (m *Monitor) providerLocked(provider models.ProviderInfo) *models.ProviderHealth {
+	health, ok := m.providers[provider]
+	if !ok {
+		health = &models.ProviderHealth{Name: provider.Name, Endpoint: provider.Endpoint, Status: models.ProviderUnknown}
+		m.providers[provider] = health
+	}
+	return health
+}
+
+// Status returns the collected state for the given registered translators,
+// whose providers are listed even before their first call
+func (m *Monitor) Status(translators map[string]models.Translator, disabled map[string]bool) *models.AdminStatus {
+	now := m.now().UTC()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	status := &models.AdminStatus{
+		StartedAt: m.startedAt,
+		Models:    make([]models.ModelStatus, 0, len(translators)),
+		Volume: models.RequestVolume{
+			Total:     m.total,
+			Errors:    m.errors,
+			Coalesced: m.coalesced,
+			PerMinute: make([]int64, volumeMinutes),
+		},
+	}
+
+	for name, translator := range translators {
+		model := models.ModelStatus{Name: name, Mock: true, Enabled: !disabled[name]}
+		if pt, ok := translator.(models.ProviderTranslator); ok {
+			provider := pt.Provider()
+			model.Mock = false
+			model.Provider = provider.Name
+			model.Endpoint = provider.Endpoint
+			m.providerLocked(provider)
+		}
+		if counters, ok := m.models[name]; ok {
+			model.Requests = counters.requests
+			model.Errors = counters.errors
+			if counters.requests > 0 {
+				model.AvgLatencyMs = counters.latencyMs / counters.requests
+			}
+			model.P95LatencyMs = counters.latencies.percentile(95).Milliseconds()
+			model.Hedges = counters.hedges
+			model.HedgeWins = counters.hedgeWins
+		}
+		status.Models = append(status.Models, model)
+	}
+	sort.Slice(status.Models, func(i, j int) bool {
+		return status.Models[i].Name < status.Models[j].Name
+	})
+
+	status.Providers = make([]models.ProviderHealth, 0, len(m.providers))
+	for _, health := range m.providers {
+		status.Providers = append(status.Providers, *health)
+	}
+	sort.Slice(status.Providers, func(i, j int) bool {
+		a, b := status.Providers[i], status.Providers[j]
+		return a.Name < b.Name || a.Name == b.Name && a.Endpoint < b.Endpoint
+	})
+
+	// Only count the buckets of the last hour, oldest first
+	current := now.Unix() / 60
+	for i := 0; i < volumeMinutes; i++ {
+		minute := current - volumeMinutes + 1 + int64(i)
+		slot := minute % volumeMinutes
+		if m.minutes[slot] == minute {
+			status.Volume.PerMinute[i] = m.volume[slot]
+			status.Volume.LastHour += m.volume[slot]
+		}
+	}
+
+	// Newest errors first
+	status.RecentErrors = make([]models.ErrorEvent, len(m.recent))
+	for i, event := range m.recent {
+		status.RecentErrors[len(m.recent)-1-i] = event
+	}
+
+	return status
+}
+
+// providerStatus derives a provider's status from its latest calls
+func providerStatus(health *models.ProviderHealth) string {
+	switch {
+	case health.Calls == 0:
+		return models.ProviderUnknown
+	case health.ConsecutiveFailures == 0:
+		return models.ProviderHealthy
+	case health.ConsecutiveFailures < providerDownAfter:
+		return models.ProviderDegraded
+	default:
+		return models.ProviderDown
+	}
+}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"translator-service/internal/config"
+	"translator-service/internal/models"
+)
+
+func TestDeepLTranslator_Translate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/translate" {
+			t.Errorf("Unexpected path: %s", r.URL.Path)
+		}
+		if auth := r.Header.Get("Authorization"); auth != "DeepL-Auth-Key deepl-key" {
+			t.Errorf("Expected DeepL-Auth-Key authorization, got %q", auth)
+		}
+		assertJSONBody(t, r, "deepl_translate_request.json")
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(loadFixture(t, "deepl_translate_response.json"))
+	}))
+	defer server.Close()
+
+	translator := NewDeepLTranslator("deepl-key", server.URL+"/v2/", server.Client())
+	response, err := translator.Translate(context.Background(), &models.TranslationRequest{
+		Text:      "Save changes",
+		Model:     "deepl",
+		Formality: "formal",
+		Glossary:  "def3a26b-3e84-45b3-84ae-0c0aaf3525f7",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if response.Translation != "保存更改" {
+		t.Errorf("Unexpected translation: %q", response.Translation)
+	}
+	if response.Usage != nil {
+		t.Errorf("Expected no token usage, got %+v", response.Usage)
+	}
+}
+
+func TestDeepLTranslator_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write(loadFixture(t, "deepl_error_response.json"))
+	}))
+	defer server.Close()
+
+	translator := NewDeepLTranslator("free-key:fx", server.URL, server.Client())
+	_, err := translator.Translate(context.Background(), &models.TranslationRequest{Text: "Hello", Model: "deepl"})
+	if err == nil || !strings.Contains(err.Error(), "status 403") {
+		t.Errorf("Expected forbidden error, got %v", err)
+	}
+}
+
+func TestDeepLFormality(t *testing.T) {
+	tests := []struct {
+		formality string
+		expected  string
+	}{
+		{"", ""},
+		{"formal", "prefer_more"},
+		{"informal", "prefer_less"},
+	}
+
+	for _, tt := range tests {
+		if got := deepLFormality(tt.formality); got != tt.expected {
+			t.Errorf("deepLFormality(%q) = %q, expected %q", tt.formality, got, tt.expected)
+		}
+	}
+}
+
+func TestLibreTranslateTranslator_Translate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/translate" {
+			t.Errorf("Unexpected path: %s", r.URL.Path)
+		}
+		assertJSONBody(t, r, "libretranslate_translate_request.json")
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(loadFixture(t, "libretranslate_translate_response.json"))
+	}))
+	defer server.Close()
+
+	// Formality and glossary are not supported by LibreTranslate and must not be sent
+	translator := NewLibreTranslateTranslator("libre-key", server.URL, server.Client())
+	response, err := translator.Translate(context.Background(), &models.TranslationRequest{
+		Text:      "Save changes",
+		Model:     "libretranslate",
+		Formality: "informal",
+		Glossary:  "ui-terms",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if response.Translation != "保存更改" {
+		t.Errorf("Unexpected translation: %q", response.Translation)
+	}
+	if response.Model != "libretranslate" {
+		t.Errorf("Expected requested model in response, got %s", response.Model)
+	}
+}
+
+func TestLibreTranslateTranslator_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write(loadFixture(t, "libretranslate_error_response.json"))
+	}))
+	defer server.Close()
+
+	translator := NewLibreTranslateTranslator("bad-key", server.URL, server.Client())
+	_, err := translator.Translate(context.Background(), &models.TranslationRequest{Text: "Hello", Model: "libretranslate"})
+	if err == nil || !strings.Contains(err.Error(), "Invalid API key") {
+		t.Errorf("Expected invalid key error, got %v", err)
+	}
+}
+
+func TestTranslatorService_MachineTranslationProviders(t *testing.T) {
+	// Create a config without machine translation providers
+	cfg := &config.Config{ServerPort: "8080", Timeout: 30}
+	ts := NewTranslatorService(cfg)
+	if ts.IsModelSupported("deepl") || ts.IsModelSupported("libretranslate") {
+		t.Errorf("Expected machine translation models to be unregistered without configuration")
+	}
+
+	// Create a config with both providers
+	cfg = &config.Config{
+		ServerPort:             "8080",
+		Timeout:                30,
+		DeepLEndpoint:          "https://api.deepl.com/v2",
+		DeepLKey:               "deepl-key",
+		LibreTranslateEndpoint: "http://localhost:5000",
+	}
+	ts = NewTranslatorService(cfg)
+
+	if translator, _ := ts.translator(context.Background(), "deepl"); translator == nil || translator.Name() != "DeepL" {
+		t.Errorf("Expected deepl to be served by DeepL")
+	}
+	if translator, _ := ts.translator(context.Background(), "libretranslate"); translator == nil || translator.Name() != "LibreTranslate" {
+		t.Errorf("Expected libretranslate to be served by LibreTranslate")
+	}
+}
+
+func TestTranslatorService_RejectsUnknownFormality(t *testing.T) {
+	ts := NewTranslatorService(&config.Config{ServerPort: "8080", Timeout: 30})
+
+	_, err := ts.Translate(context.Background(), &models.TranslationRequest{Text: "Hello", Model: "gpt-4", Formality: "casual"})
+	if err == nil || !strings.Contains(err.Error(), "validation error") {
+		t.Errorf("Expected validation error, got %v", err)
+	}
+}
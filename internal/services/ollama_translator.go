@@ -0,0 +1,148 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"translator-service/internal/models"
+)
+
+// OllamaTranslator implements the Translator interface for models served by Ollama
+type OllamaTranslator struct {
+	endpoint string
+	modelTag string
+	client   *http.Client
+}
+
+// NewOllamaTranslator creates a new Ollama translator for the given model tag
+func NewOllamaTranslator(endpoint, modelTag string, client *http.Client) *OllamaTranslator {
+	return &OllamaTranslator{
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		modelTag: modelTag,
+		client:   client,
+	}
+}
+
+// Translate translates text using the Ollama chat API
+func (ot *OllamaTranslator) Translate(ctx context.Context, req *models.TranslationRequest) (*models.TranslationResponse, error) {
+	temperature, maxTokens := generationParams(req)
+	prompt := promptFor(req)
+
+	// Create the Ollama API request
+	apiReq := OllamaRequest{
+		Model:    ot.modelTag,
+		Messages: chatMessages(prompt, req),
+		Stream:   false,
+		Options: OllamaOptions{
+			Temperature: &temperature,
+			NumPredict:  maxTokens,
+		},
+	}
+
+	// Convert request to JSON
+	jsonData, err := json.Marshal(apiReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	// Create HTTP request
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", ot.endpoint+"/api/chat", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+
+	// Set headers
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	// Make the API call
+	resp, err := ot.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make API call: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Read response body
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	// Parse response
+	var apiResp OllamaResponse
+	if err := json.Unmarshal(body, &apiResp); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+		}
+		return nil, fmt.Errorf("failed to parse API response: %w", err)
+	}
+
+	// Check for API errors
+	if apiResp.Error != "" {
+		return nil, fmt.Errorf("API error: %s", apiResp.Error)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	// Extract translation from response
+	translation := strings.TrimSpace(apiResp.Message.Content)
+	if translation == "" {
+		return nil, fmt.Errorf("API returned empty translation")
+	}
+
+	return &models.TranslationResponse{
+		Original:      req.Text,
+		Translation:   translation,
+		Model:         req.Model,
+		PromptVersion: prompt.Version,
+		Usage: &models.TokenUsage{
+			PromptTokens:     apiResp.PromptEvalCount,
+			CompletionTokens: apiResp.EvalCount,
+			TotalTokens:      apiResp.PromptEvalCount + apiResp.EvalCount,
+		},
+	}, nil
+}
+
+// Name returns the name of the translator
+func (ot *OllamaTranslator) Name() string {
+	return "Ollama"
+}
+
+// Provider returns the provider and endpoint text is sent to
+func (ot *OllamaTranslator) Provider() models.ProviderInfo {
+	return models.ProviderInfo{Name: "ollama", Endpoint: ot.endpoint}
+}
+
+// SupportsModel returns true if the translator supports the given model
+func (ot *OllamaTranslator) SupportsModel(model string) bool {
+	return model == "llama" || model == ot.modelTag
+}
+
+// OllamaRequest represents the request structure for the Ollama chat API
+type OllamaRequest struct {
+	Model    string        `json:"model"`
+	Messages []Message     `json:"messages"`
+	Stream   bool          `json:"stream"`
+	Options  OllamaOptions `json:"options,omitempty"`
+}
+
+// OllamaOptions represents the generation options for the Ollama chat API
+type OllamaOptions struct {
+	Temperature *float64 `json:"temperature,omitempty"`
+	NumPredict  int      `json:"num_predict,omitempty"`
+}
+
+// OllamaResponse represents the response structure from the Ollama chat API
+type OllamaResponse struct {
+	Model           string  `json:"model"`
+	Message         Message `json:"message"`
+	Done            bool    `json:"done"`
+	PromptEvalCount int     `json:"prompt_eval_count"`
+	EvalCount       int     `json:"eval_count"`
+	Error           string  `json:"error"`
+}
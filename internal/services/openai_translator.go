@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"time"
 
 	"translator-service/internal/models"
 )
@@ -20,33 +19,25 @@ type OpenAITranslator struct {
 }
 
 // NewOpenAITranslator creates a new OpenAI translator
-func NewOpenAITranslator(apiKey, endpoint string) *OpenAITranslator {
+func NewOpenAITranslator(apiKey, endpoint string, client *http.Client) *OpenAITranslator {
 	return &OpenAITranslator{
 		apiKey:   apiKey,
 		endpoint: endpoint,
-		client: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		client:   client,
 	}
 }
 
 // Translate translates text using the OpenAI API
 func (ot *OpenAITranslator) Translate(ctx context.Context, req *models.TranslationRequest) (*models.TranslationResponse, error) {
+	temperature, maxTokens := generationParams(req)
+	prompt := promptFor(req)
+
 	// Create the OpenAI API request
 	apiReq := OpenAIRequest{
-		Model: req.Model,
-		Messages: []Message{
-			{
-				Role:    "system",
-				Content: "You are a professional English to Chinese translator. Translate the following English text to Chinese. Provide only the translation without any explanation.",
-			},
-			{
-				Role:    "user",
-				Content: req.Text,
-			},
-		},
-		Temperature: 0.3,
-		MaxTokens:   1000,
+		Model:       req.Model,
+		Messages:    chatMessages(prompt, req),
+		Temperature: &temperature,
+		MaxTokens:   maxTokens,
 	}
 
 	// Convert request to JSON
@@ -105,9 +96,15 @@ func (ot *OpenAITranslator) Translate(ctx context.Context, req *models.Translati
 	}
 
 	return &models.TranslationResponse{
-		Original:    req.Text,
-		Translation: translation,
-		Model:       req.Model,
+		Original:      req.Text,
+		Translation:   translation,
+		Model:         req.Model,
+		PromptVersion: prompt.Version,
+		Usage: &models.TokenUsage{
+			PromptTokens:     apiResp.Usage.PromptTokens,
+			CompletionTokens: apiResp.Usage.CompletionTokens,
+			TotalTokens:      apiResp.Usage.TotalTokens,
+		},
 	}, nil
 }
 
@@ -116,6 +113,11 @@ func (ot *OpenAITranslator) Name() string {
 	return "OpenAI"
 }
 
+// Provider returns the provider and endpoint text is sent to
+func (ot *OpenAITranslator) Provider() models.ProviderInfo {
+	return models.ProviderInfo{Name: "openai", Endpoint: ot.endpoint}
+}
+
 // SupportsModel returns true if the translator supports the given model
 func (ot *OpenAITranslator) SupportsModel(model string) bool {
 	switch model {
@@ -128,9 +130,9 @@ func (ot *OpenAITranslator) SupportsModel(model string) bool {
 
 // OpenAIRequest represents the request structure for OpenAI API
 type OpenAIRequest struct {
-	Model       string    `json:"model"`
+	Model       string    `json:"model,omitempty"`
 	Messages    []Message `json:"messages"`
-	Temperature float64   `json:"temperature,omitempty"`
+	Temperature *float64  `json:"temperature,omitempty"`
 	MaxTokens   int       `json:"max_tokens,omitempty"`
 }
 
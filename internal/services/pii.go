@@ -0,0 +1,360 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"math/big"
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+
+	"translator-service/internal/config"
+	"translator-service/internal/models"
+)
+
+// PII policies
+const (
+	PIIAllow = "allow"
+	PIIMask  = "mask"
+	PIIBlock = "block"
+)
+
+// ContentFilter inspects a request before its text is sent to a translation provider
+type ContentFilter interface {
+	// Filter may rewrite the text fields of the request. It returns an error,
+	// along with what it found, when the request must not be sent.
+	Filter(req *models.TranslationRequest) (*FilterResult, error)
+}
+
+// FilterResult describes what a content filter found and how to undo its changes
+type FilterResult struct {
+	Findings []models.PIIFinding
+	// Restore reverses the filter's changes in a translation; nil when nothing was changed
+	Restore func(text string) string
+}
+
+// SetContentFilter replaces the filter applied to requests before they are
+// sent to a provider. A nil filter restores the PII filter configured under llm.pii.
+func (ts *TranslatorService) SetContentFilter(filter ContentFilter) {
+	ts.mu.Lock()
+	ts.filter = filter
+	ts.mu.Unlock()
+}
+
+// contentFilter returns the filter applied to requests before they are sent to a provider
+func (ts *TranslatorService) contentFilter() ContentFilter {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+	if ts.filter != nil {
+		return ts.filter
+	}
+	return NewPIIFilter(ts.config)
+}
+
+// filterContent applies the content filter to a request and logs what it found
+func (ts *TranslatorService) filterContent(req *models.TranslationRequest) (*FilterResult, error) {
+	result, err := ts.contentFilter().Filter(req)
+	if result != nil {
+		for _, finding := range result.Findings {
+			log.Printf("Content filter: %d %s match(es) in request for %s, policy %s", finding.Count, finding.Type, req.Model, finding.Policy)
+		}
+	}
+	return result, err
+}
+
+// restoreContent reverses the content filter's changes in a response and
+// reports its findings, returning the caller's original text
+func restoreContent(response *models.TranslationResponse, original string, result *FilterResult) {
+	response.Original = original
+	if result == nil {
+		return
+	}
+
+	response.PII = result.Findings
+	if result.Restore == nil {
+		return
+	}
+	response.Translation = result.Restore(response.Translation)
+	if response.Verification != nil {
+		response.Verification.BackTranslation = result.Restore(response.Verification.BackTranslation)
+	}
+}
+
+// piiDetector finds personal data of one type. valid, when set, rejects
+// candidates the pattern matches by accident.
+type piiDetector struct {
+	piiType string
+	pattern *regexp.Regexp
+	valid   func(match string) bool
+}
+
+// piiDetectors are tried in order; a match never overlaps one found by an earlier detector
+var piiDetectors = []piiDetector{
+	{"email", regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}`), nil},
+	{"iban", regexp.MustCompile(`\b[A-Z]{2}\d{2}(?: ?[A-Z0-9]{4}){2,7}(?: ?[A-Z0-9]{1,3})?\b`), validIBAN},
+	{"credit_card", regexp.MustCompile(`\b(?:\d{13,19}|\d{4}(?:[ -]\d{4}){2,3}(?:[ -]?\d{1,3})?|\d{4}[ -]\d{6}[ -]\d{5})\b`), validCardNumber},
+	{"ip", regexp.MustCompile(`\b(?:(?:25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)\.){3}(?:25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)\b`), nil},
+	{"ip", regexp.MustCompile(`[0-9A-Fa-f]{0,4}(?::[0-9A-Fa-f]{0,4}){2,7}`), validIPv6},
+	{"phone", regexp.MustCompile(`(?:\+\d{1,3}[ .-]?)?(?:\(\d{1,4}\)[ .-]?)?\d{2,4}(?:[ .-]?\d{2,4}){1,4}`), validPhone},
+}
+
+// datePattern matches dates, which look like phone numbers to the phone detector
+var datePattern = regexp.MustCompile(`^(?:\d{4}[./-]\d{1,2}[./-]\d{1,2}|\d{1,2}[./-]\d{1,2}[./-]\d{2,4})$`)
+
+// PIIFilter detects emails, phone numbers, credit card numbers, IBANs and IP
+// addresses and applies the configured policy to each type: allow sends the
+// data as is, mask replaces it with placeholders that are restored in the
+// translation, and block refuses the request
+type PIIFilter struct {
+	policy func(piiType string) string
+}
+
+// NewPIIFilter creates a PII filter applying the policies of a configuration
+func NewPIIFilter(cfg *config.Config) *PIIFilter {
+	return &PIIFilter{policy: cfg.GetPIIPolicy}
+}
+
+// Filter masks the personal data in the text, instructions and context of a request
+func (f *PIIFilter) Filter(req *models.TranslationRequest) (*FilterResult, error) {
+	masker := &piiMasker{
+		policy:       f.policy,
+		placeholders: make(map[string]string),
+		values:       make(map[string]string),
+		counts:       make(map[string]int),
+		next:         make(map[string]int),
+	}
+
+	req.Text = masker.mask(req.Text)
+	// Only placeholders in the text are expected back in the translation
+	expected := make([]string, 0, len(masker.values))
+	for placeholder := range masker.values {
+		expected = append(expected, placeholder)
+	}
+	req.Instructions = masker.mask(req.Instructions)
+	if req.Context != nil {
+		// Copy the context so the caller's request is left untouched
+		tc := *req.Context
+		tc.Preceding = masker.maskAll(tc.Preceding)
+		tc.Following = masker.maskAll(tc.Following)
+		tc.Notes = masker.mask(tc.Notes)
+		req.Context = &tc
+	}
+
+	result := &FilterResult{}
+	var blocked []string
+	for _, detector := range piiDetectors {
+		count := masker.counts[detector.piiType]
+		if count == 0 || containsFinding(result.Findings, detector.piiType) {
+			continue
+		}
+		policy := f.policy(detector.piiType)
+		result.Findings = append(result.Findings, models.PIIFinding{Type: detector.piiType, Policy: policy, Count: count})
+		if policy == PIIBlock {
+			blocked = append(blocked, detector.piiType)
+		}
+	}
+	if len(blocked) > 0 {
+		return result, fmt.Errorf("content blocked: request contains %s", strings.Join(blocked, ", "))
+	}
+
+	if len(masker.values) > 0 {
+		pairs := make([]string, 0, 2*len(masker.values))
+		for placeholder, value := range masker.values {
+			pairs = append(pairs, placeholder, value)
+		}
+		replacer := strings.NewReplacer(pairs...)
+		result.Restore = func(text string) string {
+			for _, placeholder := range expected {
+				if !strings.Contains(text, placeholder) {
+					log.Printf("Masked %s is missing from the translation", placeholder)
+				}
+			}
+			return replacer.Replace(text)
+		}
+	}
+	return result, nil
+}
+
+// piiMasker masks the personal data of a request, giving each distinct value
+// one placeholder across all of the request's fields
+type piiMasker struct {
+	policy func(piiType string) string
+	// placeholders maps values to their placeholder and values maps placeholders back
+	placeholders map[string]string
+	values       map[string]string
+	counts       map[string]int
+	// next numbers the placeholders of each type
+	next map[string]int
+}
+
+// mask counts the personal data in text and replaces the data whose policy is mask
+func (m *piiMasker) mask(text string) string {
+	matches := findPII(text)
+	if len(matches) == 0 {
+		return text
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, match := range matches {
+		m.counts[match.piiType]++
+		if m.policy(match.piiType) != PIIMask {
+			continue
+		}
+
+		value := text[match.start:match.end]
+		placeholder, ok := m.placeholders[value]
+		if !ok {
+			m.next[match.piiType]++
+			placeholder = fmt.Sprintf("[%s_%d]", strings.ToUpper(match.piiType), m.next[match.piiType])
+			m.placeholders[value] = placeholder
+			m.values[placeholder] = value
+		}
+		b.WriteString(text[last:match.start])
+		b.WriteString(placeholder)
+		last = match.end
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// maskAll masks a list of texts into a new list
+func (m *piiMasker) maskAll(texts []string) []string {
+	if texts == nil {
+		return nil
+	}
+	masked := make([]string, len(texts))
+	for i, text := range texts {
+		masked[i] = m.mask(text)
+	}
+	return masked
+}
+
+// piiMatch is the position of personal data in a text
+type piiMatch struct {
+	piiType    string
+	start, end int
+}
+
+// findPII returns the non-overlapping personal data in text, in order of position
+func findPII(text string) []piiMatch {
+	var matches []piiMatch
+	for _, detector := range piiDetectors {
+		for _, loc := range detector.pattern.FindAllStringIndex(text, -1) {
+			start, end := loc[0], loc[1]
+			if !standsAlone(text, start, end) || overlaps(matches, start, end) {
+				continue
+			}
+			if detector.valid != nil && !detector.valid(text[start:end]) {
+				continue
+			}
+			matches = append(matches, piiMatch{piiType: detector.piiType, start: start, end: end})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].start < matches[j].start
+	})
+	return matches
+}
+
+// standsAlone reports whether a match is not part of a longer word or number
+func standsAlone(text string, start, end int) bool {
+	if start > 0 && isWordByte(text[start-1]) {
+		return false
+	}
+	return end >= len(text) || !isWordByte(text[end])
+}
+
+// isWordByte reports whether b is an ASCII letter or digit
+func isWordByte(b byte) bool {
+	return b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+}
+
+// overlaps reports whether the range overlaps one of the matches
+func overlaps(matches []piiMatch, start, end int) bool {
+	for _, match := range matches {
+		if start < match.end && match.start < end {
+			return true
+		}
+	}
+	return false
+}
+
+// containsFinding reports whether findings already include a type
+func containsFinding(findings []models.PIIFinding, piiType string) bool {
+	for _, finding := range findings {
+		if finding.Type == piiType {
+			return true
+		}
+	}
+	return false
+}
+
+// digitsOf returns the digits of s
+func digitsOf(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, s)
+}
+
+// validCardNumber checks the length and Luhn checksum of a card number
+func validCardNumber(match string) bool {
+	digits := digitsOf(match)
+	if len(digits) < 13 || len(digits) > 19 {
+		return false
+	}
+
+	sum := 0
+	for i := 0; i < len(digits); i++ {
+		digit := int(digits[len(digits)-1-i] - '0')
+		if i%2 == 1 {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+	}
+	return sum%10 == 0
+}
+
+// validIBAN checks the length and mod-97 checksum of an IBAN
+func validIBAN(match string) bool {
+	iban := strings.ReplaceAll(match, " ", "")
+	if len(iban) < 15 || len(iban) > 34 {
+		return false
+	}
+
+	// Move the country code and check digits to the end and convert letters to numbers
+	var numeric strings.Builder
+	for _, r := range iban[4:] + iban[:4] {
+		if r >= 'A' && r <= 'Z' {
+			numeric.WriteString(fmt.Sprint(r - 'A' + 10))
+		} else {
+			numeric.WriteRune(r)
+		}
+	}
+	value, ok := new(big.Int).SetString(numeric.String(), 10)
+	return ok && new(big.Int).Mod(value, big.NewInt(97)).Int64() == 1
+}
+
+// validIPv6 checks that a match is an IPv6 address rather than, say, a time of day
+func validIPv6(match string) bool {
+	ip := net.ParseIP(match)
+	return ip != nil && ip.To4() == nil && strings.ContainsAny(match, "0123456789abcdefABCDEF")
+}
+
+// validPhone accepts matches with 7 to 15 digits that are written like a phone
+// number, with a country code, an area code in brackets or separators, and are not dates
+func validPhone(match string) bool {
+	digits := digitsOf(match)
+	if len(digits) < 7 || len(digits) > 15 || datePattern.MatchString(match) {
+		return false
+	}
+	return strings.ContainsAny(match, "+( .-")
+}
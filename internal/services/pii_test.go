@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"translator-service/internal/config"
+	"translator-service/internal/models"
+)
+
+func TestFindPII(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{"Email", "Write to jane.doe+billing@example.co.uk today", "email"},
+		{"International phone", "Call +49 151 1234 5678 for help", "phone"},
+		{"Phone with area code", "Call (555) 123-4567 for help", "phone"},
+		{"Credit card", "Card 4111 1111 1111 1111 was declined", "credit_card"},
+		{"IBAN", "Pay to DE89 3704 0044 0532 0130 00 by Friday", "iban"},
+		{"IPv4", "The server at 192.168.10.200 is down", "ip"},
+		{"IPv6", "The server at 2001:db8::8a2e:370:7334 is down", "ip"},
+		{"Card failing checksum", "Order 4111 1111 1111 1112 shipped", ""},
+		{"Date", "Released on 2024-01-15", ""},
+		{"Time", "The meeting starts at 10:30:00", ""},
+		{"Plain number", "There are 12345678 records", ""},
+		{"Version", "Update to version 1.2.3", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var types []string
+			for _, match := range findPII(tt.text) {
+				types = append(types, match.piiType)
+			}
+			if got := strings.Join(types, ","); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestTranslatorService_PIIPolicies(t *testing.T) {
+	cfg := &config.Config{ServerPort: "8080", Timeout: 30, PIIPolicies: map[string]string{"email": "mask", "phone": "mask", "credit_card": "block"}}
+	ts := NewTranslatorService(cfg)
+
+	// Create a translator that records what it was sent
+	var sent *models.TranslationRequest
+	ts.translators["gpt-4"] = &MockTranslatorForTesting{
+		name: "Recording Translator",
+		translateFunc: func(ctx context.Context, req *models.TranslationRequest) (*models.TranslationResponse, error) {
+			sent = req
+			return &models.TranslationResponse{Original: req.Text, Translation: "请通过 " + strings.TrimPrefix(req.Text, "Contact ") + " 联系我们", Model: req.Model}, nil
+		},
+	}
+
+	// Masked data is replaced before sending and restored in the translation
+	req := &models.TranslationRequest{
+		Text:    "Contact jane@example.com or +1 415 555 0100, jane@example.com",
+		Model:   "gpt-4",
+		Context: &models.TranslationContext{Notes: "Support address is help@example.com"},
+	}
+	response, err := ts.Translate(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if sent.Text != "Contact [EMAIL_1] or [PHONE_1], [EMAIL_1]" || sent.Context.Notes != "Support address is [EMAIL_2]" {
+		t.Errorf("Expected personal data to be masked, sent %q and %q", sent.Text, sent.Context.Notes)
+	}
+	if response.Translation != "请通过 jane@example.com or +1 415 555 0100, jane@example.com 联系我们" || response.Original != req.Text {
+		t.Errorf("Expected personal data to be restored, got %+v", response)
+	}
+	if req.Context.Notes != "Support address is help@example.com" {
+		t.Errorf("Expected the caller's request to be left untouched, got %q", req.Context.Notes)
+	}
+	expected := []models.PIIFinding{{Type: "email", Policy: "mask", Count: 3}, {Type: "phone", Policy: "mask", Count: 1}}
+	if len(response.PII) != len(expected) || response.PII[0] != expected[0] || response.PII[1] != expected[1] {
+		t.Errorf("Expected findings %+v, got %+v", expected, response.PII)
+	}
+
+	// Allowed data is sent as is but still reported
+	sent = nil
+	response, err = ts.Translate(context.Background(), &models.TranslationRequest{Text: "Contact the server at 10.0.0.1", Model: "gpt-4"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if sent.Text != "Contact the server at 10.0.0.1" || len(response.PII) != 1 || response.PII[0].Policy != "allow" {
+		t.Errorf("Expected allowed data to be sent and reported, sent %q, got %+v", sent.Text, response.PII)
+	}
+
+	// Blocked data never reaches the provider
+	sent = nil
+	_, err = ts.Translate(context.Background(), &models.TranslationRequest{Text: "Contact me, my card is 4111 1111 1111 1111", Model: "gpt-4"})
+	if err == nil || !strings.Contains(err.Error(), "content blocked") {
+		t.Errorf("Expected content blocked error, got %v", err)
+	}
+	if sent != nil {
+		t.Errorf("Expected the blocked request not to be sent, sent %q", sent.Text)
+	}
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"translator-service/internal/models"
+	"translator-service/internal/prompts"
+)
+
+// Generation settings used when a request does not override them
+const (
+	defaultTemperature = 0.3
+	defaultMaxTokens   = 1000
+)
+
+// builtinPrompts renders the default prompt for providers called without the
+// translator service, which normally renders the prompt for them
+var builtinPrompts = prompts.Builtin()
+
+// renderPrompt renders the prompt template referenced by ref for a request
+func renderPrompt(store *prompts.Store, ref string, req *models.TranslationRequest) (*models.RenderedPrompt, error) {
+	template, err := store.Get(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	text, err := template.Render(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.RenderedPrompt{Version: template.ID(), Text: text}, nil
+}
+
+// promptFor returns the rendered prompt of a request, rendering the default
+// template when the translator service has not already done so
+func promptFor(req *models.TranslationRequest) *models.RenderedPrompt {
+	if req.RenderedPrompt != nil {
+		return req.RenderedPrompt
+	}
+
+	rendered, err := renderPrompt(builtinPrompts, prompts.DefaultName, req)
+	if err != nil {
+		// The built-in template only fails on programming errors
+		log.Printf("Failed to render default prompt: %v", err)
+		return &models.RenderedPrompt{}
+	}
+	return rendered
+}
+
+// systemPrompt returns the instructions for a request, followed by the script
+// to write in when it is not Simplified Chinese and the term pairs of its
+// session so recurring terms are translated consistently
+func systemPrompt(prompt *models.RenderedPrompt, req *models.TranslationRequest) string {
+	var b strings.Builder
+	b.WriteString(prompt.Text)
+	if req.TargetLanguage == models.LanguageTraditionalChinese {
+		b.WriteString("\n\nWrite the translation in Traditional Chinese characters.")
+	}
+	if req.SessionHistory == nil || len(req.SessionHistory.Terms) == 0 {
+		return b.String()
+	}
+
+	sources := make([]string, 0, len(req.SessionHistory.Terms))
+	for source := range req.SessionHistory.Terms {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+
+	b.WriteString("\n\nUse these translations for recurring terms:")
+	for _, source := range sources {
+		fmt.Fprintf(&b, "\n- %s: %s", source, req.SessionHistory.Terms[source])
+	}
+	return b.String()
+}
+
+// sessionTurns returns the earlier translations of a request's session, oldest first
+func sessionTurns(req *models.TranslationRequest) []models.SessionTurn {
+	if req.SessionHistory == nil {
+		return nil
+	}
+	return req.SessionHistory.Turns
+}
+
+// chatMessages returns the system prompt, the session's earlier translations
+// as user and assistant messages, and the text to translate
+func chatMessages(prompt *models.RenderedPrompt, req *models.TranslationRequest) []Message {
+	turns := sessionTurns(req)
+	messages := make([]Message, 0, len(turns)*2+2)
+	messages = append(messages, Message{Role: "system", Content: systemPrompt(prompt, req)})
+	for _, turn := range turns {
+		messages = append(messages,
+			Message{Role: "user", Content: turn.Original},
+			Message{Role: "assistant", Content: turn.Translation},
+		)
+	}
+	return append(messages, Message{Role: "user", Content: req.Text})
+}
+
+// completionPrompt returns a single prompt containing the instructions, the
+// session's earlier translations and the text, for providers without a
+// separate system prompt. The text of an unframed prompt follows the
+// instructions as is.
+func completionPrompt(prompt *models.RenderedPrompt, req *models.TranslationRequest) string {
+	var b strings.Builder
+	b.WriteString(systemPrompt(prompt, req))
+	if prompt.Unframed {
+		fmt.Fprintf(&b, "\n\n%s", req.Text)
+		return b.String()
+	}
+	for _, turn := range sessionTurns(req) {
+		fmt.Fprintf(&b, "\n\nEnglish: %s\n\nChinese: %s", turn.Original, turn.Translation)
+	}
+	fmt.Fprintf(&b, "\n\nEnglish: %s\n\nChinese:", req.Text)
+	return b.String()
+}
+
+// generationParams returns the temperature and token limit for a request, applying the defaults
+func generationParams(req *models.TranslationRequest) (float64, int) {
+	temperature := defaultTemperature
+	if req.Temperature != nil {
+		temperature = *req.Temperature
+	}
+
+	maxTokens := defaultMaxTokens
+	if req.MaxTokens > 0 {
+		maxTokens = req.MaxTokens
+	}
+
+	return temperature, maxTokens
+}
@@ -0,0 +1,249 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"translator-service/internal/config"
+	"translator-service/internal/models"
+	"translator-service/internal/prompts"
+)
+
+func TestPromptFor(t *testing.T) {
+	// Create a request without options
+	prompt := promptFor(&models.TranslationRequest{Text: "Hello"})
+	if prompt.Version != "translate@v2" {
+		t.Errorf("Expected the built-in template, got %s", prompt.Version)
+	}
+	if prompt.Text != "You are a professional English to Chinese translator. Translate the following English text to Chinese. Provide only the translation without any explanation." {
+		t.Errorf("Unexpected default prompt: %q", prompt.Text)
+	}
+
+	// Create a request with every prompt option
+	prompt = promptFor(&models.TranslationRequest{
+		Text:         "Hello",
+		Formality:    "informal",
+		Tone:         "playful",
+		Domain:       "marketing",
+		Instructions: "Keep the brand name in English.",
+	})
+
+	expected := []string{
+		"Use an informal, conversational register.",
+		"Use a playful tone.",
+		"The text is marketing copy",
+		"Additional instructions: Keep the brand name in English.",
+	}
+	for _, part := range expected {
+		if !strings.Contains(prompt.Text, part) {
+			t.Errorf("Expected prompt to contain %q, got %q", part, prompt.Text)
+		}
+	}
+
+	// Traditional Chinese is requested on top of the template
+	traditional := &models.TranslationRequest{Text: "Hello", TargetLanguage: models.LanguageTraditionalChinese}
+	if system := systemPrompt(promptFor(traditional), traditional); !strings.HasSuffix(system, "Traditional Chinese characters.") {
+		t.Errorf("Expected the Traditional Chinese instruction, got %q", system)
+	}
+
+	// A prompt rendered by the service is used as is
+	rendered := &models.RenderedPrompt{Version: "terse@v2", Text: "Translate."}
+	if prompt := promptFor(&models.TranslationRequest{Text: "Hello", RenderedPrompt: rendered}); prompt != rendered {
+		t.Errorf("Expected the rendered prompt to be used, got %+v", prompt)
+	}
+}
+
+func TestGenerationParams(t *testing.T) {
+	temperature, maxTokens := generationParams(&models.TranslationRequest{})
+	if temperature != defaultTemperature || maxTokens != defaultMaxTokens {
+		t.Errorf("Expected defaults, got temperature %v and max tokens %d", temperature, maxTokens)
+	}
+
+	// An explicit zero temperature must not fall back to the default
+	zero := 0.0
+	temperature, maxTokens = generationParams(&models.TranslationRequest{Temperature: &zero, MaxTokens: 200})
+	if temperature != 0 || maxTokens != 200 {
+		t.Errorf("Expected overrides, got temperature %v and max tokens %d", temperature, maxTokens)
+	}
+}
+
+func TestProviders_MapGenerationOptions(t *testing.T) {
+	zero := 0.0
+	req := &models.TranslationRequest{
+		Text:        "Hello",
+		Temperature: &zero,
+		MaxTokens:   200,
+		Domain:      "ui",
+	}
+
+	tests := []struct {
+		name      string
+		model     string
+		response  string
+		translate func(url string) models.Translator
+		check     func(t *testing.T, body map[string]interface{})
+	}{
+		{
+			name:     "OpenAI",
+			model:    "gpt-4",
+			response: `{"choices":[{"message":{"role":"assistant","content":"你好"}}]}`,
+			translate: func(url string) models.Translator {
+				return NewOpenAITranslator("key", url, http.DefaultClient)
+			},
+			check: func(t *testing.T, body map[string]interface{}) {
+				if body["temperature"] != 0.0 || body["max_tokens"] != 200.0 {
+					t.Errorf("Unexpected generation options: %v", body)
+				}
+				system := body["messages"].([]interface{})[0].(map[string]interface{})["content"].(string)
+				if !strings.Contains(system, "user interface string") {
+					t.Errorf("Expected domain instruction in system prompt, got %q", system)
+				}
+			},
+		},
+		{
+			name:     "Anthropic",
+			model:    "claude-3-haiku",
+			response: `{"content":[{"type":"text","text":"你好"}]}`,
+			translate: func(url string) models.Translator {
+				return NewAnthropicTranslator("key", url, http.DefaultClient)
+			},
+			check: func(t *testing.T, body map[string]interface{}) {
+				if body["temperature"] != 0.0 || body["max_tokens"] != 200.0 {
+					t.Errorf("Unexpected generation options: %v", body)
+				}
+				if system, _ := body["system"].(string); !strings.Contains(system, "user interface string") {
+					t.Errorf("Expected domain instruction in system prompt, got %q", system)
+				}
+			},
+		},
+		{
+			name:     "Ollama",
+			model:    "llama",
+			response: `{"message":{"role":"assistant","content":"你好"},"done":true}`,
+			translate: func(url string) models.Translator {
+				return NewOllamaTranslator(url, "llama3", http.DefaultClient)
+			},
+			check: func(t *testing.T, body map[string]interface{}) {
+				options := body["options"].(map[string]interface{})
+				if options["temperature"] != 0.0 || options["num_predict"] != 200.0 {
+					t.Errorf("Unexpected generation options: %v", options)
+				}
+			},
+		},
+		{
+			name:     "Gemini",
+			model:    "gemini-1.5-flash",
+			response: `{"candidates":[{"content":{"parts":[{"text":"你好"}]}}]}`,
+			translate: func(url string) models.Translator {
+				return NewGeminiTranslator("key", url, []string{"gemini-1.5-flash"}, http.DefaultClient)
+			},
+			check: func(t *testing.T, body map[string]interface{}) {
+				config := body["generationConfig"].(map[string]interface{})
+				if config["temperature"] != 0.0 || config["maxOutputTokens"] != 200.0 {
+					t.Errorf("Unexpected generation options: %v", config)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var body map[string]interface{}
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Fatalf("Request body is not valid JSON: %v", err)
+				}
+				tt.check(t, body)
+
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			modelReq := *req
+			modelReq.Model = tt.model
+			if _, err := tt.translate(server.URL).Translate(context.Background(), &modelReq); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestTranslatorService_PromptSelection(t *testing.T) {
+	// Create a prompt directory with two versions of a custom template
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "terse"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for version, text := range map[string]string{"v1": "Translate to Chinese.", "v2": "Translate to Chinese. Be brief."} {
+		if err := os.WriteFile(filepath.Join(dir, "terse", version+".tmpl"), []byte(text), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	store, err := prompts.NewStore(dir)
+	if err != nil {
+		t.Fatalf("Failed to load prompts: %v", err)
+	}
+
+	cfg := &config.Config{ServerPort: "8080", Timeout: 30, PromptModels: map[string]string{"gpt-4": "terse@v1"}}
+	ts := NewTranslatorService(cfg)
+	ts.SetPromptStore(store)
+
+	// Create a translator that records the prompt it was given
+	var received *models.RenderedPrompt
+	recorder := &MockTranslatorForTesting{
+		name: "Prompt Recorder",
+		translateFunc: func(ctx context.Context, req *models.TranslationRequest) (*models.TranslationResponse, error) {
+			received = promptFor(req)
+			return &models.TranslationResponse{Original: req.Text, Translation: "你好", Model: req.Model, PromptVersion: received.Version}, nil
+		},
+	}
+	ts.translators["gpt-4"] = recorder
+	ts.translators["claude"] = recorder
+
+	tests := []struct {
+		name        string
+		req         models.TranslationRequest
+		expected    string
+		expectError bool
+	}{
+		{"Default template", models.TranslationRequest{Text: "Hello", Model: "claude"}, "translate@v2", false},
+		{"Model template", models.TranslationRequest{Text: "Hello", Model: "gpt-4"}, "terse@v1", false},
+		{"Latest version by name", models.TranslationRequest{Text: "Hello", Model: "gpt-4", Prompt: "terse"}, "terse@v2", false},
+		{"Pinned version", models.TranslationRequest{Text: "Hello", Model: "claude", Prompt: "terse@v1"}, "terse@v1", false},
+		{"Unknown template", models.TranslationRequest{Text: "Hello", Model: "claude", Prompt: "missing"}, "", true},
+		{"Unknown version", models.TranslationRequest{Text: "Hello", Model: "claude", Prompt: "terse@v9"}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			received = nil
+			response, err := ts.Translate(context.Background(), &tt.req)
+			if tt.expectError {
+				if err == nil || !strings.Contains(err.Error(), "validation error") {
+					t.Errorf("Expected validation error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if received == nil || received.Version != tt.expected {
+				t.Errorf("Expected provider to receive %s, got %+v", tt.expected, received)
+			}
+			if response.PromptVersion != tt.expected {
+				t.Errorf("Expected prompt_version %s, got %s", tt.expected, response.PromptVersion)
+			}
+			if tt.req.RenderedPrompt != nil {
+				t.Errorf("Expected the caller's request to be left untouched")
+			}
+		})
+	}
+}
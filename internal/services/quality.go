@@ -0,0 +1,257 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"translator-service/internal/models"
+	"translator-service/internal/prompts"
+)
+
+// Severities of quality issues and the amount each deducts from the score
+const (
+	severityMajor = "major"
+	severityMinor = "minor"
+
+	majorPenalty = 0.4
+	minorPenalty = 0.15
+)
+
+// Length ratio bounds for English to Chinese. Chinese usually needs between a
+// quarter and a half as many characters as the English source; translations
+// far outside this range are likely truncated or padded with explanations.
+const (
+	minLengthRatio = 0.1
+	maxLengthRatio = 1.2
+	// minRatioLength is the shortest source checked, as short strings vary widely
+	minRatioLength = 20
+)
+
+// untranslatedMinWords is the shortest run of source words in a translation
+// reported as untranslated; shorter runs are usually names and brands
+const untranslatedMinWords = 3
+
+// judgeMaxTokens bounds the judge's reply, which is a short JSON object
+const judgeMaxTokens = 300
+
+var (
+	// linkPattern matches URLs and email addresses, which must be kept verbatim
+	linkPattern = regexp.MustCompile(`(?:https?://|www\.)[^\s<>"'()（）]+|[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	// numberPattern matches numbers, including decimals and thousands separators
+	numberPattern = regexp.MustCompile(`\d+(?:[.,:]\d+)*`)
+	// latinRunPattern matches runs of Latin-script words
+	latinRunPattern = regexp.MustCompile(`[A-Za-z]+(?:['’-][A-Za-z]+)*(?:\s+[A-Za-z]+(?:['’-][A-Za-z]+)*)*`)
+)
+
+// judgeVerdict is the reply expected from the judge model
+type judgeVerdict struct {
+	Score  *float64 `json:"score"`
+	Issues []string `json:"issues"`
+}
+
+// qualityEnabled reports whether a translation's quality should be estimated
+func (ts *TranslatorService) qualityEnabled(req *models.TranslationRequest) bool {
+	return req.Quality || ts.Config().QualityEnabled
+}
+
+// estimateQuality scores a translation with the heuristic checks and, when a
+// judge model is configured, a review by that model. The lower of the two
+// scores is used, so either can flag a translation.
+func (ts *TranslatorService) estimateQuality(ctx context.Context, req *models.TranslationRequest, response *models.TranslationResponse) *models.QualityEstimate {
+	cfg := ts.Config()
+
+	issues := checkQuality(req.Text, response.Translation)
+	estimate := &models.QualityEstimate{
+		Score:  scoreIssues(issues),
+		Issues: issues,
+	}
+
+	if cfg.QualityJudgeModel != "" {
+		verdict, err := ts.judgeQuality(ctx, cfg.QualityJudgeModel, req.Text, response.Translation)
+		if err != nil {
+			// The review is best effort; the heuristic estimate still stands
+			log.Printf("Quality review with %s failed: %v", cfg.QualityJudgeModel, err)
+		} else {
+			estimate.Judge = cfg.QualityJudgeModel
+			estimate.Score = math.Min(estimate.Score, *verdict.Score/100)
+			for _, issue := range verdict.Issues {
+				estimate.Issues = append(estimate.Issues, models.QualityIssue{Check: "judge", Severity: severityMinor, Message: issue})
+			}
+		}
+	}
+
+	estimate.Score = math.Round(estimate.Score*100) / 100
+	estimate.Flagged = estimate.Score < cfg.QualityThreshold
+	if estimate.Flagged {
+		log.Printf("Translation with %s flagged for review: quality score %.2f below %.2f (%d issues)",
+			req.Model, estimate.Score, cfg.QualityThreshold, len(estimate.Issues))
+	}
+
+	return estimate
+}
+
+// judgeQuality asks the judge model to review a translation
+func (ts *TranslatorService) judgeQuality(ctx context.Context, model, original, translation string) (*judgeVerdict, error) {
+	judge, exists := ts.translator(ctx, model)
+	if !exists {
+		return nil, fmt.Errorf("unsupported model: %s", model)
+	}
+	if ts.validationService.LimitsForModel(model).MaxTokens == 0 {
+		return nil, fmt.Errorf("%s is not a language model and cannot review translations", model)
+	}
+	if err := ts.authorizeProvider(ctx, model, judge); err != nil {
+		return nil, err
+	}
+
+	ts.mu.RLock()
+	store := ts.prompts
+	ts.mu.RUnlock()
+
+	temperature := 0.0
+	req := &models.TranslationRequest{
+		Text:        fmt.Sprintf("English:\n%s\n\nChinese:\n%s", original, translation),
+		Model:       model,
+		Temperature: &temperature,
+		MaxTokens:   judgeMaxTokens,
+	}
+	rendered, err := renderPrompt(store, prompts.JudgeName, req)
+	if err != nil {
+		return nil, err
+	}
+	rendered.Unframed = true
+	req.RenderedPrompt = rendered
+
+	response, err := judge.Translate(ctx, req)
+	ts.recordProviderCall(ctx, judge, err)
+	if err != nil {
+		return nil, err
+	}
+	return parseJudgeVerdict(response.Translation)
+}
+
+// parseJudgeVerdict extracts the JSON verdict from the judge's reply, which
+// models sometimes wrap in prose or a code block
+func parseJudgeVerdict(reply string) (*judgeVerdict, error) {
+	start, end := strings.Index(reply, "{"), strings.LastIndex(reply, "}")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("judge reply is not JSON: %q", reply)
+	}
+
+	var verdict judgeVerdict
+	if err := json.Unmarshal([]byte(reply[start:end+1]), &verdict); err != nil {
+		return nil, fmt.Errorf("failed to parse judge reply: %w", err)
+	}
+	if verdict.Score == nil || *verdict.Score < 0 || *verdict.Score > 100 {
+		return nil, fmt.Errorf("judge reply has no score between 0 and 100: %q", reply)
+	}
+	return &verdict, nil
+}
+
+// checkQuality runs the heuristic checks on an English to Chinese translation
+func checkQuality(original, translation string) []models.QualityIssue {
+	issues := []models.QualityIssue{}
+	original = strings.TrimSpace(original)
+	translation = strings.TrimSpace(translation)
+
+	if translation == "" {
+		return append(issues, models.QualityIssue{Check: "empty", Severity: severityMajor, Message: "Translation is empty"})
+	}
+	if strings.EqualFold(original, translation) {
+		return append(issues, models.QualityIssue{Check: "untranslated", Severity: severityMajor, Message: "Translation is identical to the source"})
+	}
+
+	// URLs and email addresses must be kept verbatim
+	for _, link := range sourceLinks(original) {
+		if !strings.Contains(translation, link) {
+			issues = append(issues, models.QualityIssue{Check: "links", Severity: severityMajor, Message: fmt.Sprintf("Link %s is missing from the translation", link)})
+		}
+	}
+
+	// The remaining checks ignore links, which are not translated
+	originalText := linkPattern.ReplaceAllString(original, " ")
+	translationText := linkPattern.ReplaceAllString(translation, " ")
+
+	// Numbers must be preserved. Single digits are skipped, as they are often
+	// written as Chinese numerals.
+	translatedNumbers := strings.ReplaceAll(translationText, ",", "")
+	for _, number := range numberPattern.FindAllString(originalText, -1) {
+		if len(number) < 2 {
+			continue
+		}
+		if !strings.Contains(translatedNumbers, strings.ReplaceAll(number, ",", "")) {
+			issues = append(issues, models.QualityIssue{Check: "numbers", Severity: severityMinor, Message: fmt.Sprintf("Number %s is missing from the translation", number)})
+		}
+	}
+
+	// The translation must be written mostly in Chinese
+	han, latin := scriptCounts(translationText)
+	if han == 0 && latin > 0 {
+		issues = append(issues, models.QualityIssue{Check: "script", Severity: severityMajor, Message: "Translation contains no Chinese characters"})
+	} else if latin > han {
+		issues = append(issues, models.QualityIssue{Check: "script", Severity: severityMinor, Message: "Translation is mostly in Latin script"})
+	}
+
+	// Runs of source words left in the translation are likely untranslated
+	lowerOriginal := strings.ToLower(originalText)
+	for _, run := range latinRunPattern.FindAllString(translationText, -1) {
+		if len(strings.Fields(run)) >= untranslatedMinWords && strings.Contains(lowerOriginal, strings.ToLower(run)) {
+			issues = append(issues, models.QualityIssue{Check: "untranslated", Severity: severityMinor, Message: fmt.Sprintf("Segment %q appears untranslated", run)})
+		}
+	}
+
+	// The length ratio catches truncated translations and added explanations
+	if sourceLength := utf8.RuneCountInString(originalText); sourceLength >= minRatioLength {
+		ratio := float64(utf8.RuneCountInString(translationText)) / float64(sourceLength)
+		if ratio < minLengthRatio {
+			issues = append(issues, models.QualityIssue{Check: "length", Severity: severityMajor, Message: fmt.Sprintf("Translation is unusually short (length ratio %.2f); it may be truncated", ratio)})
+		} else if ratio > maxLengthRatio {
+			issues = append(issues, models.QualityIssue{Check: "length", Severity: severityMinor, Message: fmt.Sprintf("Translation is unusually long (length ratio %.2f); it may contain explanations", ratio)})
+		}
+	}
+
+	return issues
+}
+
+// scoreIssues returns a score between 0 and 1, deducting a penalty per issue
+func scoreIssues(issues []models.QualityIssue) float64 {
+	score := 1.0
+	for _, issue := range issues {
+		if issue.Severity == severityMajor {
+			score -= majorPenalty
+		} else {
+			score -= minorPenalty
+		}
+	}
+	return math.Max(score, 0)
+}
+
+// sourceLinks returns the URLs and email addresses in a text without trailing punctuation
+func sourceLinks(text string) []string {
+	matches := linkPattern.FindAllString(text, -1)
+	links := make([]string, 0, len(matches))
+	for _, link := range matches {
+		links = append(links, strings.TrimRight(link, ".,;:!?"))
+	}
+	return links
+}
+
+// scriptCounts returns the number of Han and Latin letters in a text
+func scriptCounts(text string) (int, int) {
+	han, latin := 0, 0
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			han++
+		case unicode.Is(unicode.Latin, r):
+			latin++
+		}
+	}
+	return han, latin
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"translator-service/internal/config"
+	"translator-service/internal/models"
+)
+
+func TestCheckQuality(t *testing.T) {
+	tests := []struct {
+		name        string
+		original    string
+		translation string
+		expected    []string
+	}{
+		{"Good translation", "Your order of 1,250 items ships on 2024-03-01.", "您订购的1250件商品将于2024-03-01发货。", nil},
+		{"Empty", "Hello", "", []string{"empty"}},
+		{"Identical", "Hello", "hello", []string{"untranslated"}},
+		{"Missing link", "See https://example.com/help.", "请参阅帮助页面。", []string{"links"}},
+		{"Kept link", "See https://example.com/help.", "请参阅 https://example.com/help。", nil},
+		{"Missing number", "Wait 30 seconds.", "请稍候。", []string{"numbers"}},
+		{"Single digit as numeral", "Wait 3 seconds.", "请等待三秒。", nil},
+		{"Wrong script", "Good morning", "Bonjour", []string{"script"}},
+		{"Untranslated segment", "Click the Save button to keep your changes.", "点击保存按钮 to keep your changes。", []string{"script", "untranslated"}},
+		{"Truncated", "This is a long sentence that should have a long translation.", "这", []string{"length"}},
+		{"Explanation added", "Save your changes now.", "立即保存您的更改。注：这里的保存指的是将所有修改写入磁盘，以便下次打开时仍然可用，并且不会丢失任何内容。", []string{"length"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := checkQuality(tt.original, tt.translation)
+
+			checks := make([]string, 0, len(issues))
+			for _, issue := range issues {
+				checks = append(checks, issue.Check)
+			}
+			if strings.Join(checks, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("Expected checks %v, got %+v", tt.expected, issues)
+			}
+		})
+	}
+}
+
+func TestParseJudgeVerdict(t *testing.T) {
+	verdict, err := parseJudgeVerdict("```json\n{\"score\": 72, \"issues\": [\"Tone is too casual\"]}\n```")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if *verdict.Score != 72 || len(verdict.Issues) != 1 {
+		t.Errorf("Unexpected verdict: %+v", verdict)
+	}
+
+	for _, reply := range []string{"Looks good", `{"issues": []}`, `{"score": 150}`} {
+		if _, err := parseJudgeVerdict(reply); err == nil {
+			t.Errorf("Expected error for reply %q", reply)
+		}
+	}
+}
+
+func TestTranslatorService_Quality(t *testing.T) {
+	cfg := &config.Config{ServerPort: "8080", Timeout: 30, QualityThreshold: 0.6}
+	ts := NewTranslatorService(cfg)
+
+	// Create a translator that leaves part of the text in English
+	translator := &MockTranslatorForTesting{
+		name: "Partial Translator",
+		translateFunc: func(ctx context.Context, req *models.TranslationRequest) (*models.TranslationResponse, error) {
+			return &models.TranslationResponse{Original: req.Text, Translation: "点击保存 to keep your changes", Model: req.Model}, nil
+		},
+	}
+	ts.translators["gpt-4"] = translator
+
+	// Create a judge that records the prompt it was given
+	var judged *models.TranslationRequest
+	judge := &MockTranslatorForTesting{
+		name: "Judge",
+		translateFunc: func(ctx context.Context, req *models.TranslationRequest) (*models.TranslationResponse, error) {
+			judged = req
+			return &models.TranslationResponse{Translation: `{"score": 20, "issues": ["Half of the text is not translated"]}`}, nil
+		},
+	}
+
+	req := &models.TranslationRequest{Text: "Click Save to keep your changes", Model: "gpt-4"}
+
+	// Quality is not estimated unless enabled or requested
+	response, err := ts.Translate(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response.Quality != nil {
+		t.Errorf("Expected no quality estimate, got %+v", response.Quality)
+	}
+
+	// A request can ask for an estimate from the heuristic checks
+	req.Quality = true
+	response, err = ts.Translate(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response.Quality == nil || response.Quality.Judge != "" || len(response.Quality.Issues) == 0 {
+		t.Fatalf("Expected a heuristic estimate with issues, got %+v", response.Quality)
+	}
+	heuristicScore := response.Quality.Score
+
+	// A judge model lowers the score and adds its issues
+	reloaded := *cfg
+	reloaded.QualityJudgeModel = "claude"
+	ts.Reload(&reloaded, nil)
+	ts.translators["gpt-4"] = translator
+	ts.translators["claude"] = judge
+
+	response, err = ts.Translate(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	quality := response.Quality
+	if quality.Judge != "claude" || quality.Score != 0.2 || !quality.Flagged {
+		t.Errorf("Expected a flagged estimate from the judge, got %+v (heuristic score %.2f)", quality, heuristicScore)
+	}
+	if last := quality.Issues[len(quality.Issues)-1]; last.Check != "judge" {
+		t.Errorf("Expected the judge's issue to be listed, got %+v", quality.Issues)
+	}
+	if judged == nil || judged.RenderedPrompt.Version != "quality-judge@v1" || !strings.Contains(judged.Text, "点击保存") {
+		t.Errorf("Unexpected judge request: %+v", judged)
+	}
+
+	// A failing judge leaves the heuristic estimate in place
+	ts.translators["claude"] = &MockTranslatorForTesting{
+		name: "Broken Judge",
+		translateFunc: func(ctx context.Context, req *models.TranslationRequest) (*models.TranslationResponse, error) {
+			return &models.TranslationResponse{Translation: "I cannot help with that."}, nil
+		},
+	}
+	response, err = ts.Translate(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response.Quality.Judge != "" || response.Quality.Score != heuristicScore {
+		t.Errorf("Expected the heuristic estimate, got %+v", response.Quality)
+	}
+}
@@ -0,0 +1,245 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"translator-service/internal/models"
+	"translator-service/internal/storage"
+)
+
+// reviewTransition describes the states a review action applies to and the state it leads to
+type reviewTransition struct {
+	from []string
+	to   string
+	// edits is set for actions that may replace the translation
+	edits bool
+}
+
+// reviewTransitions lists the actions reviewers can take. Starting a review
+// also reopens approved and rejected items.
+var reviewTransitions = map[string]reviewTransition{
+	models.ReviewActionStart:   {from: []string{models.ReviewMachine, models.ReviewApproved, models.ReviewRejected}, to: models.ReviewInReview},
+	models.ReviewActionEdit:    {from: []string{models.ReviewInReview}, to: models.ReviewInReview, edits: true},
+	models.ReviewActionApprove: {from: []string{models.ReviewMachine, models.ReviewInReview}, to: models.ReviewApproved, edits: true},
+	models.ReviewActionReject:  {from: []string{models.ReviewMachine, models.ReviewInReview}, to: models.ReviewRejected},
+}
+
+// SetReviewStore enables the review workflow: machine translations are stored
+// for review and approved translations are reused for identical requests
+func (ts *TranslatorService) SetReviewStore(store storage.ReviewStore) {
+	ts.reviews = store
+}
+
+// ReviewStore returns the review store, or nil when the review workflow is disabled
+func (ts *TranslatorService) ReviewStore() storage.ReviewStore {
+	return ts.reviews
+}
+
+// GetReview returns a review item of the request's tenant with its audit trail
+func (ts *TranslatorService) GetReview(ctx context.Context, id int64) (*models.ReviewItem, error) {
+	if ts.reviews == nil {
+		return nil, fmt.Errorf("review workflow is disabled")
+	}
+
+	item, err := ts.reviews.GetReview(ctx, id)
+	if err == nil && item.Tenant != TenantFromContext(ctx) {
+		err = storage.ErrReviewNotFound
+	}
+	if errors.Is(err, storage.ErrReviewNotFound) {
+		return nil, fmt.Errorf("review item %d: %w", id, err)
+	}
+	return item, err
+}
+
+// ListReviews returns the review items of the request's tenant matching the query
+func (ts *TranslatorService) ListReviews(ctx context.Context, query models.ReviewQuery) (*models.ReviewPage, error) {
+	if ts.reviews == nil {
+		return nil, fmt.Errorf("review workflow is disabled")
+	}
+
+	query.Tenant = TenantFromContext(ctx)
+	return ts.reviews.ListReviews(ctx, query)
+}
+
+// reviewKey identifies requests of a tenant that should receive the same
+// translation: the text and every option that changes the translation, but not the model
+func reviewKey(tenant string, req *models.TranslationRequest) string {
+	parts := []string{tenant, req.Text, req.Formality, req.Tone, req.Domain, req.Instructions, req.Glossary, req.TargetLanguage}
+	if tc := req.Context; tc != nil {
+		parts = append(parts, tc.Screen, tc.Notes, strconv.Itoa(tc.MaxLength),
+			strings.Join(tc.Preceding, "\x1e"), strings.Join(tc.Following, "\x1e"))
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+// approvedTranslation returns the human-approved translation of an identical
+// request, or nil when there is none or the review workflow is disabled
+func (ts *TranslatorService) approvedTranslation(ctx context.Context, req *models.TranslationRequest) *models.TranslationResponse {
+	if ts.reviews == nil {
+		return nil
+	}
+
+	item, err := ts.reviews.FindReview(ctx, reviewKey(TenantFromContext(ctx), req), models.ReviewApproved)
+	if err != nil {
+		log.Printf("Failed to look up approved translation: %v", err)
+		return nil
+	}
+	if item == nil {
+		return nil
+	}
+
+	return &models.TranslationResponse{
+		Original:    req.Text,
+		Translation: item.Translation,
+		Model:       req.Model,
+		ReviewID:    item.ID,
+		ReviewState: item.State,
+	}
+}
+
+// submitForReview stores a machine translation for review, unless an identical
+// request is already awaiting review, and reports the item on the response
+func (ts *TranslatorService) submitForReview(ctx context.Context, req *models.TranslationRequest, response *models.TranslationResponse) {
+	if ts.reviews == nil {
+		return
+	}
+
+	// Use a fresh context so a cancelled request is still submitted
+	ctx = context.WithoutCancel(ctx)
+	tenant := TenantFromContext(ctx)
+	key := reviewKey(tenant, req)
+
+	item, err := ts.reviews.FindReview(ctx, key, models.ReviewMachine, models.ReviewInReview)
+	if err != nil {
+		log.Printf("Failed to look up review item: %v", err)
+		return
+	}
+
+	if item == nil {
+		now := time.Now().UTC()
+		item = &models.ReviewItem{
+			Key:                key,
+			Tenant:             tenant,
+			Original:           req.Text,
+			MachineTranslation: response.Translation,
+			Translation:        response.Translation,
+			Model:              req.Model,
+			State:              models.ReviewMachine,
+			CreatedAt:          now,
+			UpdatedAt:          now,
+		}
+		event := &models.ReviewEvent{
+			Reviewer:    ClientFromContext(ctx),
+			Action:      models.ReviewActionCreate,
+			ToState:     models.ReviewMachine,
+			Translation: response.Translation,
+			CreatedAt:   now,
+		}
+		if err := ts.reviews.CreateReview(ctx, item, event); err != nil {
+			log.Printf("Failed to submit translation for review: %v", err)
+			return
+		}
+	}
+
+	response.ReviewID = item.ID
+	response.ReviewState = item.State
+}
+
+// ReviewTranslation applies a reviewer's action to a review item and records
+// it in the item's audit trail
+func (ts *TranslatorService) ReviewTranslation(ctx context.Context, id int64, action *models.ReviewAction) (*models.ReviewItem, error) {
+	if ts.reviews == nil {
+		return nil, fmt.Errorf("review workflow is disabled")
+	}
+
+	transition, err := ts.validationService.validateReviewAction(action)
+	if err != nil {
+		return nil, fmt.Errorf("validation error: %w", err)
+	}
+
+	// Serialize reviews so concurrent actions on an item see each other's state
+	ts.reviewMu.Lock()
+	defer ts.reviewMu.Unlock()
+
+	item, err := ts.GetReview(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !containsString(transition.from, item.State) {
+		return nil, fmt.Errorf("review conflict: cannot %s an item that is %s", action.Action, item.State)
+	}
+
+	event := &models.ReviewEvent{
+		Reviewer:  action.Reviewer,
+		Action:    action.Action,
+		FromState: item.State,
+		ToState:   transition.to,
+		Comment:   action.Comment,
+		CreatedAt: time.Now().UTC(),
+	}
+	if transition.edits && action.Translation != "" && action.Translation != item.Translation {
+		item.Translation = action.Translation
+		event.Translation = action.Translation
+	}
+
+	item.State = transition.to
+	item.Reviewer = action.Reviewer
+	item.UpdatedAt = event.CreatedAt
+	if err := ts.reviews.UpdateReview(ctx, item, event); err != nil {
+		return nil, err
+	}
+
+	item.Events = append(item.Events, *event)
+	return item, nil
+}
+
+// validateReviewAction validates a reviewer's action and returns its transition
+func (vs *ValidationService) validateReviewAction(action *models.ReviewAction) (reviewTransition, error) {
+	action.Action = strings.TrimSpace(action.Action)
+	action.Reviewer = strings.TrimSpace(action.Reviewer)
+	action.Translation = strings.TrimSpace(action.Translation)
+	action.Comment = strings.TrimSpace(action.Comment)
+
+	transition, ok := reviewTransitions[action.Action]
+	if !ok {
+		return reviewTransition{}, &ValidationError{"Action must be one of: start, edit, approve, reject"}
+	}
+	if action.Reviewer == "" || len(action.Reviewer) > 100 || strings.ContainsAny(action.Reviewer, "\r\n") {
+		return reviewTransition{}, &ValidationError{"Reviewer must be a single line of at most 100 characters"}
+	}
+	if action.Translation != "" && !transition.edits {
+		return reviewTransition{}, &ValidationError{"Only edit and approve actions may change the translation"}
+	}
+	if action.Action == models.ReviewActionEdit && action.Translation == "" {
+		return reviewTransition{}, &ValidationError{"An edit requires a translation"}
+	}
+	if len(action.Translation) > 10000 || vs.containsInvalidCharacters(action.Translation) {
+		return reviewTransition{}, &ValidationError{"Translation must be at most 10000 characters without control characters"}
+	}
+	if len(action.Comment) > 1000 || vs.containsInvalidCharacters(action.Comment) {
+		return reviewTransition{}, &ValidationError{"Comment must be at most 1000 characters without control characters"}
+	}
+
+	return transition, nil
+}
+
+// containsString reports whether s is one of values
+func containsString(values []string, s string) bool {
+	for _, value := range values {
+		if value == s {
+			return true
+		}
+	}
+	return false
+}
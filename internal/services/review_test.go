@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"translator-service/internal/config"
+	"translator-service/internal/models"
+	"translator-service/internal/storage"
+)
+
+func TestTranslatorService_ReviewWorkflow(t *testing.T) {
+	cfg := &config.Config{ServerPort: "8080", Timeout: 30}
+	ts := NewTranslatorService(cfg)
+
+	// Reviewing requires the review store
+	if _, err := ts.ReviewTranslation(context.Background(), 1, &models.ReviewAction{Action: "start", Reviewer: "alice"}); err == nil {
+		t.Error("Expected an error while the review workflow is disabled")
+	}
+	ts.SetReviewStore(storage.NewMemoryReviewStore())
+
+	// Create a translator that counts its calls
+	calls := 0
+	ts.translators["gpt-4"] = &MockTranslatorForTesting{
+		name: "Counting Translator",
+		translateFunc: func(ctx context.Context, req *models.TranslationRequest) (*models.TranslationResponse, error) {
+			calls++
+			return &models.TranslationResponse{Original: req.Text, Translation: "保存", Model: req.Model}, nil
+		},
+	}
+	request := func() *models.TranslationRequest {
+		return &models.TranslationRequest{Text: "Save", Model: "gpt-4", Domain: "ui"}
+	}
+
+	// Machine translations are queued for review once per identical request
+	first, err := ts.Translate(context.Background(), request())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if first.ReviewID == 0 || first.ReviewState != models.ReviewMachine {
+		t.Fatalf("Expected a review item awaiting review, got %+v", first)
+	}
+	second, err := ts.Translate(context.Background(), request())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if second.ReviewID != first.ReviewID || calls != 2 {
+		t.Errorf("Expected the open review item to be reused, got item %d after %d calls", second.ReviewID, calls)
+	}
+
+	// Edits are only possible once the review started
+	_, err = ts.ReviewTranslation(context.Background(), first.ReviewID, &models.ReviewAction{Action: "edit", Reviewer: "alice", Translation: "保存更改"})
+	if err == nil || !strings.Contains(err.Error(), "review conflict") {
+		t.Errorf("Expected review conflict, got %v", err)
+	}
+
+	// Invalid actions are rejected
+	invalid := []*models.ReviewAction{
+		{Action: "publish", Reviewer: "alice"},
+		{Action: "start"},
+		{Action: "reject", Reviewer: "alice", Translation: "保存更改"},
+		{Action: "edit", Reviewer: "alice"},
+	}
+	for _, action := range invalid {
+		if _, err := ts.ReviewTranslation(context.Background(), first.ReviewID, action); err == nil || !strings.Contains(err.Error(), "validation error") {
+			t.Errorf("Expected validation error for %+v, got %v", action, err)
+		}
+	}
+	if _, err := ts.ReviewTranslation(context.Background(), 999, &models.ReviewAction{Action: "start", Reviewer: "alice"}); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Expected not found error, got %v", err)
+	}
+
+	// Start, edit and approve the translation
+	actions := []*models.ReviewAction{
+		{Action: "start", Reviewer: "alice"},
+		{Action: "edit", Reviewer: "alice", Translation: "保存更改"},
+		{Action: "approve", Reviewer: "bob", Comment: "Matches the style guide"},
+	}
+	var item *models.ReviewItem
+	for _, action := range actions {
+		if item, err = ts.ReviewTranslation(context.Background(), first.ReviewID, action); err != nil {
+			t.Fatalf("Unexpected error for %s: %v", action.Action, err)
+		}
+	}
+	if item.State != models.ReviewApproved || item.Translation != "保存更改" || item.MachineTranslation != "保存" || item.Reviewer != "bob" {
+		t.Errorf("Unexpected review item: %+v", item)
+	}
+	if len(item.Events) != 4 || item.Events[1].Reviewer != "alice" || item.Events[3].Comment != "Matches the style guide" {
+		t.Errorf("Unexpected audit trail: %+v", item.Events)
+	}
+
+	// Identical requests now receive the approved translation without calling the model
+	approved, err := ts.Translate(context.Background(), request())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if approved.Translation != "保存更改" || approved.ReviewState != models.ReviewApproved || calls != 2 {
+		t.Errorf("Expected the approved translation without a model call, got %+v after %d calls", approved, calls)
+	}
+
+	// Other requests still go to the model
+	other := request()
+	other.Domain = "legal"
+	if response, err := ts.Translate(context.Background(), other); err != nil || response.ReviewID == first.ReviewID || calls != 3 {
+		t.Errorf("Expected a new review item from the model, got %+v (%v) after %d calls", response, err, calls)
+	}
+}
@@ -0,0 +1,191 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+	"unicode/utf8"
+
+	"translator-service/internal/config"
+	"translator-service/internal/models"
+)
+
+// RoutingPolicy chooses the model of a request made with model auto
+type RoutingPolicy interface {
+	// Choose returns the model of one of the candidates, which are the models
+	// the request may use. There is always at least one candidate.
+	Choose(req *models.TranslationRequest, candidates []RouteCandidate) (string, error)
+}
+
+// RouteCandidate describes a model a request with model auto may be routed to
+type RouteCandidate struct {
+	Model string
+	// Cost is the configured price of translating 1,000 characters; zero when unset
+	Cost float64
+	// Mock is true for models that answer locally instead of calling a provider
+	Mock bool
+	// P95Latency is taken from the model's latest successful provider calls; zero before the first one
+	P95Latency time.Duration
+	// Health is the status of the model's provider, one of the models.Provider* states
+	Health string
+}
+
+// SetRoutingPolicy replaces the policy choosing the model of requests made
+// with model auto. A nil policy restores CostLatencyPolicy.
+func (ts *TranslatorService) SetRoutingPolicy(policy RoutingPolicy) {
+	ts.mu.Lock()
+	ts.routing = policy
+	ts.mu.Unlock()
+}
+
+// routeAuto replaces model auto in the request with the model chosen by the
+// routing policy among those the request may use
+func (ts *TranslatorService) routeAuto(ctx context.Context, req *models.TranslationRequest) error {
+	ts.mu.RLock()
+	var policy RoutingPolicy = CostLatencyPolicy{}
+	if ts.routing != nil {
+		policy = ts.routing
+	}
+	routing := ts.config.RoutingModels
+	names := make([]string, 0, len(ts.translators))
+	for name := range ts.translators {
+		if _, ok := routing[name]; ok || len(routing) == 0 {
+			names = append(names, name)
+		}
+	}
+	ts.mu.RUnlock()
+	sort.Strings(names)
+
+	language := req.TargetLanguage
+	if language == "" {
+		language = models.LanguageSimplifiedChinese
+	}
+
+	// Leave out models that are disabled, not allowed for the tenant, not used
+	// for the target language or that reject the request's options
+	var candidates []RouteCandidate
+	for _, name := range names {
+		translator, exists := ts.translator(ctx, name)
+		if !exists || ts.authorizeProvider(ctx, name, translator) != nil {
+			continue
+		}
+		if languages := routing[name].Languages; len(languages) > 0 && !containsString(languages, language) {
+			continue
+		}
+		options := *req
+		options.Model = name
+		if ts.validationService.ValidateGenerationOptions(&options) != nil {
+			continue
+		}
+
+		candidate := RouteCandidate{
+			Model:      name,
+			Cost:       routing[name].Cost,
+			Mock:       true,
+			P95Latency: ts.monitor.CallLatencyP95(name),
+			Health:     models.ProviderUnknown,
+		}
+		if pt, ok := translator.(models.ProviderTranslator); ok {
+			candidate.Mock = false
+			candidate.Health = ts.monitor.ProviderStatus(pt.Provider())
+		}
+		candidates = append(candidates, candidate)
+	}
+	if len(candidates) == 0 {
+		return fmt.Errorf("no model available for %s: no model accepts the request", config.AutoModel)
+	}
+
+	model, err := policy.Choose(req, candidates)
+	if err != nil {
+		return fmt.Errorf("no model available for %s: %w", config.AutoModel, err)
+	}
+	chosen := false
+	for _, candidate := range candidates {
+		chosen = chosen || candidate.Model == model
+	}
+	if !chosen {
+		return fmt.Errorf("no model available for %s: routing policy chose %s, which the request may not use", config.AutoModel, model)
+	}
+
+	req.Model = model
+	return nil
+}
+
+// CostLatencyPolicy is the default routing policy. It prefers real providers
+// over mocks and avoids providers that are down, then picks the model with the
+// lowest score combining the estimated cost of the request and the observed
+// p95 latency, each relative to the most expensive and slowest candidate.
+// Latency weighs most for short texts and cost for long ones; degraded
+// providers get a penalty.
+type CostLatencyPolicy struct{}
+
+const (
+	// costBalanceChars is the input length at which cost and latency weigh the same
+	costBalanceChars = 1000
+
+	// degradedPenalty is added to the score of models whose provider is degraded
+	degradedPenalty = 0.5
+)
+
+// Choose picks the candidate with the lowest score, breaking ties by name
+func (CostLatencyPolicy) Choose(req *models.TranslationRequest, candidates []RouteCandidate) (string, error) {
+	candidates = preferCandidates(candidates, func(c RouteCandidate) bool { return !c.Mock })
+	candidates = preferCandidates(candidates, func(c RouteCandidate) bool { return c.Health != models.ProviderDown })
+
+	// Models without requests yet count as the average of those with some
+	var maxCost float64
+	var maxLatency, totalLatency time.Duration
+	observed := 0
+	for _, candidate := range candidates {
+		maxCost = max(maxCost, candidate.Cost)
+		if candidate.P95Latency > 0 {
+			maxLatency = max(maxLatency, candidate.P95Latency)
+			totalLatency += candidate.P95Latency
+			observed++
+		}
+	}
+	var defaultLatency time.Duration
+	if observed > 0 {
+		defaultLatency = totalLatency / time.Duration(observed)
+	}
+
+	chars := float64(utf8.RuneCountInString(req.Text))
+	costWeight := chars / (chars + costBalanceChars)
+
+	best, bestScore := "", 0.0
+	for _, candidate := range candidates {
+		var score float64
+		if maxCost > 0 {
+			score += costWeight * candidate.Cost / maxCost
+		}
+		if maxLatency > 0 {
+			latency := candidate.P95Latency
+			if latency == 0 {
+				latency = defaultLatency
+			}
+			score += (1 - costWeight) * float64(latency) / float64(maxLatency)
+		}
+		if candidate.Health == models.ProviderDegraded {
+			score += degradedPenalty
+		}
+		if best == "" || score < bestScore || score == bestScore && candidate.Model < best {
+			best, bestScore = candidate.Model, score
+		}
+	}
+	return best, nil
+}
+
+// preferCandidates keeps the candidates matching a preference, or all of them when none does
+func preferCandidates(candidates []RouteCandidate, preferred func(RouteCandidate) bool) []RouteCandidate {
+	var kept []RouteCandidate
+	for _, candidate := range candidates {
+		if preferred(candidate) {
+			kept = append(kept, candidate)
+		}
+	}
+	if len(kept) == 0 {
+		return candidates
+	}
+	return kept
+}
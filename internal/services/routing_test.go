@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"translator-service/internal/config"
+	"translator-service/internal/models"
+)
+
+func TestCostLatencyPolicy_Choose(t *testing.T) {
+	short := &models.TranslationRequest{Text: "Save the file"}
+	long := &models.TranslationRequest{Text: strings.Repeat("Save the file before closing the window. ", 100)}
+
+	fast := RouteCandidate{Model: "fast", Cost: 10, P95Latency: time.Second, Health: models.ProviderHealthy}
+	cheap := RouteCandidate{Model: "cheap", Cost: 1, P95Latency: 3 * time.Second, Health: models.ProviderHealthy}
+
+	tests := []struct {
+		name       string
+		req        *models.TranslationRequest
+		candidates []RouteCandidate
+		expected   string
+	}{
+		{"Short text prefers latency", short, []RouteCandidate{fast, cheap}, "fast"},
+		{"Long text prefers cost", long, []RouteCandidate{fast, cheap}, "cheap"},
+		{"Down provider is avoided", short, []RouteCandidate{{Model: "fast", Cost: 10, P95Latency: time.Second, Health: models.ProviderDown}, cheap}, "cheap"},
+		{"Degraded provider is penalised", short, []RouteCandidate{{Model: "fast", P95Latency: time.Second, Health: models.ProviderDegraded}, {Model: "steady", P95Latency: 1200 * time.Millisecond}}, "steady"},
+		{"All down still routes", short, []RouteCandidate{{Model: "b", Health: models.ProviderDown}, {Model: "a", Health: models.ProviderDown}}, "a"},
+		{"Mocks are left out", long, []RouteCandidate{{Model: "mock", Mock: true}, fast}, "fast"},
+		{"Unobserved latency counts as the average", short, []RouteCandidate{fast, {Model: "new", Cost: 10}, cheap}, "fast"},
+		{"Equal scores break ties by name", short, []RouteCandidate{{Model: "b"}, {Model: "a"}}, "a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model, err := CostLatencyPolicy{}.Choose(tt.req, tt.candidates)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if model != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, model)
+			}
+		})
+	}
+}
+
+// routingPolicyFunc adapts a function to RoutingPolicy
+type routingPolicyFunc func(req *models.TranslationRequest, candidates []RouteCandidate) (string, error)
+
+func (f routingPolicyFunc) Choose(req *models.TranslationRequest, candidates []RouteCandidate) (string, error) {
+	return f(req, candidates)
+}
+
+func TestTranslatorService_RouteAuto(t *testing.T) {
+	ts := NewTranslatorService(&config.Config{
+		ServerPort: "8080",
+		Timeout:    30,
+		RoutingModels: map[string]config.RoutingModelConfig{
+			"gpt-4":  {Cost: 30},
+			"claude": {Cost: 3, Languages: []string{models.LanguageSimplifiedChinese}},
+			"deepl":  {Cost: 20},
+		},
+	})
+	ts.translators = make(map[string]models.Translator)
+	for model, provider := range map[string]string{"gpt-4": "openai", "claude": "anthropic", "deepl": "deepl", "gpt-3.5": "openai"} {
+		ts.translators[model] = &ProviderTranslatorForTesting{
+			MockTranslatorForTesting: MockTranslatorForTesting{name: model},
+			provider:                 models.ProviderInfo{Name: provider, Endpoint: "https://api." + provider + ".com"},
+		}
+	}
+	ctx := context.Background()
+
+	// Fake latencies: claude is fast and cheap, gpt-4 slow and expensive
+	for i := 0; i < 20; i++ {
+		ts.monitor.RecordCallLatency("gpt-4", 4*time.Second)
+		ts.monitor.RecordCallLatency("claude", time.Second)
+		ts.monitor.RecordCallLatency("deepl", 2*time.Second)
+	}
+
+	response, err := ts.Translate(ctx, &models.TranslationRequest{Text: "Save the file", Model: "auto"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response.Model != "claude" || response.Alias != "auto" {
+		t.Errorf("Expected auto to route to claude, got %s via %q", response.Model, response.Alias)
+	}
+
+	// claude is not used for Traditional Chinese, and deepl does not take max_tokens
+	var candidates []RouteCandidate
+	ts.SetRoutingPolicy(routingPolicyFunc(func(req *models.TranslationRequest, c []RouteCandidate) (string, error) {
+		candidates = c
+		return CostLatencyPolicy{}.Choose(req, c)
+	}))
+	response, err = ts.Translate(ctx, &models.TranslationRequest{Text: "Save the file", Model: "auto", TargetLanguage: models.LanguageTraditionalChinese, MaxTokens: 500})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(candidates) != 1 || candidates[0].Model != "gpt-4" || candidates[0].Cost != 30 || candidates[0].P95Latency != 4*time.Second || response.Model != "gpt-4" {
+		t.Errorf("Expected gpt-4 as the only candidate, got %+v and %s", candidates, response.Model)
+	}
+
+	// Failing providers are avoided once they are down
+	for i := 0; i < providerDownAfter; i++ {
+		ts.monitor.RecordProviderCall(models.ProviderInfo{Name: "anthropic", Endpoint: "https://api.anthropic.com"}, errors.New("overloaded"))
+	}
+	response, err = ts.Translate(ctx, &models.TranslationRequest{Text: "Save the file", Model: "auto"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response.Model != "deepl" || candidates[0].Health != models.ProviderDown {
+		t.Errorf("Expected auto to avoid the down provider, got %s with %+v", response.Model, candidates)
+	}
+
+	// Policies may only choose among the candidates
+	ts.SetRoutingPolicy(routingPolicyFunc(func(req *models.TranslationRequest, c []RouteCandidate) (string, error) {
+		return "gpt-3.5", nil
+	}))
+	if _, err := ts.Translate(ctx, &models.TranslationRequest{Text: "Save the file", Model: "auto"}); err == nil || !strings.Contains(err.Error(), "no model available for auto") {
+		t.Errorf("Expected error for a model outside the candidates, got %v", err)
+	}
+	ts.SetRoutingPolicy(nil)
+
+	for _, model := range []string{"gpt-4", "claude", "deepl"} {
+		if _, err := ts.SetModelEnabled(model, false); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if _, err := ts.Translate(ctx, &models.TranslationRequest{Text: "Save the file", Model: "auto"}); err == nil || !strings.Contains(err.Error(), "no model available for auto") {
+		t.Errorf("Expected error when every routing model is disabled, got %v", err)
+	}
+}
+
+func TestTranslatorService_AutoSupported(t *testing.T) {
+	ts := NewTranslatorService(&config.Config{ServerPort: "8080", Timeout: 30})
+
+	if !ts.IsModelSupported("auto") || !strings.Contains(strings.Join(ts.GetSupportedModels(), ","), "auto") {
+		t.Errorf("Expected auto to be supported")
+	}
+
+	// Without real providers every model is a mock, and auto still routes
+	response, err := ts.Translate(context.Background(), &models.TranslationRequest{Text: "Save the file", Model: "auto"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response.Model == "auto" || !ts.IsModelSupported(response.Model) {
+		t.Errorf("Expected auto to route to a registered model, got %s", response.Model)
+	}
+}
@@ -0,0 +1,286 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"translator-service/internal/models"
+)
+
+// Session limits applied when the configuration leaves them unset
+const (
+	DefaultMaxSessions     = 1000
+	DefaultSessionMaxTurns = 10
+	DefaultSessionTTL      = time.Hour
+)
+
+// Per-session memory limits
+const (
+	// sessionMaxBytes bounds the text of the turns kept in a session; the
+	// oldest turns are dropped first
+	sessionMaxBytes = 32 * 1024
+	// sessionMaxTerms bounds the number of term pairs kept in a session
+	sessionMaxTerms = 200
+	// termMaxWords is the longest segment recorded as a term pair
+	termMaxWords = 4
+)
+
+// SessionManager keeps translation sessions in memory. Sessions expire after
+// the TTL without use, and the least recently used session is evicted when
+// the session limit is reached.
+type SessionManager struct {
+	mu          sync.Mutex
+	sessions    map[string]*session
+	maxSessions int
+	maxTurns    int
+	ttl         time.Duration
+	now         func() time.Time
+}
+
+// session is the mutable state behind a models.Session
+type session struct {
+	id        string
+	tenant    string
+	model     string
+	turns     []models.SessionTurn
+	terms     map[string]string
+	createdAt time.Time
+	lastUsed  time.Time
+}
+
+// NewSessionManager creates a session manager, applying the defaults to zero limits
+func NewSessionManager(maxSessions, maxTurns int, ttl time.Duration) *SessionManager {
+	if maxSessions <= 0 {
+		maxSessions = DefaultMaxSessions
+	}
+	if maxTurns <= 0 {
+		maxTurns = DefaultSessionMaxTurns
+	}
+	if ttl <= 0 {
+		ttl = DefaultSessionTTL
+	}
+
+	return &SessionManager{
+		sessions:    make(map[string]*session),
+		maxSessions: maxSessions,
+		maxTurns:    maxTurns,
+		ttl:         ttl,
+		now:         time.Now,
+	}
+}
+
+// Create starts a new session for a tenant with optional initial terms
+func (sm *SessionManager) Create(tenant, model string, terms map[string]string) (*models.Session, error) {
+	if len(terms) > sessionMaxTerms {
+		return nil, &ValidationError{fmt.Sprintf("A session may have at most %d terms", sessionMaxTerms)}
+	}
+	for source, target := range terms {
+		if strings.TrimSpace(source) == "" || strings.TrimSpace(target) == "" || len(source) > 200 || len(target) > 200 {
+			return nil, &ValidationError{"Terms must be non-empty and at most 200 characters"}
+		}
+	}
+
+	id := newID()
+
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	now := sm.now()
+	sm.pruneLocked(now)
+	if len(sm.sessions) >= sm.maxSessions {
+		sm.evictLeastRecentlyUsedLocked()
+	}
+
+	s := &session{
+		id:        id,
+		tenant:    tenant,
+		model:     model,
+		terms:     make(map[string]string, len(terms)),
+		createdAt: now,
+		lastUsed:  now,
+	}
+	for source, target := range terms {
+		s.terms[strings.TrimSpace(source)] = strings.TrimSpace(target)
+	}
+	sm.sessions[id] = s
+
+	return sm.snapshotLocked(s), nil
+}
+
+// Get returns a copy of a tenant's session, or false if it does not exist or has expired
+func (sm *SessionManager) Get(tenant, id string) (*models.Session, bool) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	s, ok := sm.liveLocked(tenant, id)
+	if !ok {
+		return nil, false
+	}
+	return sm.snapshotLocked(s), true
+}
+
+// Delete ends a tenant's session and returns false if it did not exist
+func (sm *SessionManager) Delete(tenant, id string) bool {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	if _, ok := sm.liveLocked(tenant, id); !ok {
+		return false
+	}
+	delete(sm.sessions, id)
+	return true
+}
+
+// History returns the turns and terms to send with a translation in a session,
+// marking the session as used
+func (sm *SessionManager) History(tenant, id string) (*models.SessionHistory, string, bool) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	s, ok := sm.liveLocked(tenant, id)
+	if !ok {
+		return nil, "", false
+	}
+	s.lastUsed = sm.now()
+
+	history := &models.SessionHistory{
+		Turns: append([]models.SessionTurn(nil), s.turns...),
+		Terms: make(map[string]string, len(s.terms)),
+	}
+	for source, target := range s.terms {
+		history.Terms[source] = target
+	}
+	return history, s.model, true
+}
+
+// Record appends a completed translation to a session and extracts term pairs
+// from it. Translations for sessions that have since ended are dropped.
+func (sm *SessionManager) Record(tenant, id string, turn models.SessionTurn) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	s, ok := sm.liveLocked(tenant, id)
+	if !ok {
+		return
+	}
+	s.lastUsed = sm.now()
+
+	s.turns = append(s.turns, turn)
+	if len(s.turns) > sm.maxTurns {
+		s.turns = s.turns[len(s.turns)-sm.maxTurns:]
+	}
+	for len(s.turns) > 1 && turnBytes(s.turns) > sessionMaxBytes {
+		s.turns = s.turns[1:]
+	}
+
+	if source, target, ok := extractTerm(turn); ok && len(s.terms) < sessionMaxTerms {
+		if _, exists := s.terms[source]; !exists {
+			s.terms[source] = target
+		}
+	}
+}
+
+// liveLocked returns a tenant's session that has not expired, removing it if it has.
+// Sessions of other tenants are reported as missing.
+func (sm *SessionManager) liveLocked(tenant, id string) (*session, bool) {
+	s, ok := sm.sessions[id]
+	if !ok || s.tenant != tenant {
+		return nil, false
+	}
+	if sm.now().Sub(s.lastUsed) > sm.ttl {
+		delete(sm.sessions, id)
+		return nil, false
+	}
+	return s, true
+}
+
+// pruneLocked removes every expired session
+func (sm *SessionManager) pruneLocked(now time.Time) {
+	for id, s := range sm.sessions {
+		if now.Sub(s.lastUsed) > sm.ttl {
+			delete(sm.sessions, id)
+		}
+	}
+}
+
+// evictLeastRecentlyUsedLocked removes the session that was used longest ago
+func (sm *SessionManager) evictLeastRecentlyUsedLocked() {
+	var oldest *session
+	for _, s := range sm.sessions {
+		if oldest == nil || s.lastUsed.Before(oldest.lastUsed) {
+			oldest = s
+		}
+	}
+	if oldest != nil {
+		delete(sm.sessions, oldest.id)
+	}
+}
+
+// snapshotLocked copies a session so it can be used without holding the lock
+func (sm *SessionManager) snapshotLocked(s *session) *models.Session {
+	snapshot := &models.Session{
+		ID:        s.id,
+		Tenant:    s.tenant,
+		Model:     s.model,
+		Turns:     append([]models.SessionTurn{}, s.turns...),
+		Terms:     make(map[string]string, len(s.terms)),
+		CreatedAt: s.createdAt,
+		ExpiresAt: s.lastUsed.Add(sm.ttl),
+	}
+	for source, target := range s.terms {
+		snapshot.Terms[source] = target
+	}
+	return snapshot
+}
+
+// turnBytes returns the size of the text held in a list of turns
+func turnBytes(turns []models.SessionTurn) int {
+	total := 0
+	for _, turn := range turns {
+		total += len(turn.Original) + len(turn.Translation)
+	}
+	return total
+}
+
+// extractTerm returns a term pair for short, label-like segments such as
+// headings and table captions, which tend to recur throughout a document.
+// Full sentences are not split into terms.
+func extractTerm(turn models.SessionTurn) (string, string, bool) {
+	source := strings.TrimSpace(turn.Original)
+	target := strings.TrimSpace(turn.Translation)
+	if source == "" || target == "" {
+		return "", "", false
+	}
+	if len(strings.Fields(source)) > termMaxWords || strings.ContainsAny(source, ".!?;\n") {
+		return "", "", false
+	}
+	return source, target, true
+}
+
+// CreateSession starts a translation session for the request's tenant
+func (ts *TranslatorService) CreateSession(ctx context.Context, req *models.SessionRequest) (*models.Session, error) {
+	if req.Model != "" && !ts.IsModelSupported(req.Model) {
+		return nil, fmt.Errorf("unsupported model: %s", req.Model)
+	}
+
+	session, err := ts.sessions.Create(TenantFromContext(ctx), req.Model, req.Terms)
+	if err != nil {
+		return nil, fmt.Errorf("validation error: %w", err)
+	}
+	return session, nil
+}
+
+// GetSession returns a translation session of the request's tenant, or false
+// if it does not exist or has expired
+func (ts *TranslatorService) GetSession(ctx context.Context, id string) (*models.Session, bool) {
+	return ts.sessions.Get(TenantFromContext(ctx), id)
+}
+
+// DeleteSession ends a translation session of the request's tenant and returns
+// false if it did not exist
+func (ts *TranslatorService) DeleteSession(ctx context.Context, id string) bool {
+	return ts.sessions.Delete(TenantFromContext(ctx), id)
+}
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"translator-service/internal/config"
+	"translator-service/internal/models"
+)
+
+func TestSessionManager_Expiry(t *testing.T) {
+	// Create a manager with a controllable clock
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	sm := NewSessionManager(10, 5, time.Minute)
+	sm.now = func() time.Time { return now }
+
+	session, err := sm.Create("", "gpt-4", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Using the session extends its lifetime
+	now = now.Add(50 * time.Second)
+	if _, _, ok := sm.History("", session.ID); !ok {
+		t.Fatal("Expected session to be live")
+	}
+	now = now.Add(50 * time.Second)
+	if _, ok := sm.Get("", session.ID); !ok {
+		t.Fatal("Expected session to be live after use")
+	}
+
+	// An unused session expires
+	now = now.Add(2 * time.Minute)
+	if _, ok := sm.Get("", session.ID); ok {
+		t.Error("Expected session to have expired")
+	}
+}
+
+func TestSessionManager_EvictsLeastRecentlyUsed(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	sm := NewSessionManager(2, 5, time.Hour)
+	sm.now = func() time.Time { return now }
+
+	first, _ := sm.Create("", "", nil)
+	now = now.Add(time.Second)
+	second, _ := sm.Create("", "", nil)
+	now = now.Add(time.Second)
+
+	// Using the first session makes the second the least recently used
+	sm.History("", first.ID)
+	now = now.Add(time.Second)
+	third, _ := sm.Create("", "", nil)
+
+	if _, ok := sm.Get("", second.ID); ok {
+		t.Error("Expected the least recently used session to be evicted")
+	}
+	for _, id := range []string{first.ID, third.ID} {
+		if _, ok := sm.Get("", id); !ok {
+			t.Errorf("Expected session %s to be kept", id)
+		}
+	}
+}
+
+func TestSessionManager_Record(t *testing.T) {
+	sm := NewSessionManager(10, 2, time.Hour)
+	session, _ := sm.Create("", "", map[string]string{"Invoice": "发票"})
+
+	sm.Record("", session.ID, models.SessionTurn{Original: "Billing Address", Translation: "账单地址"})
+	sm.Record("", session.ID, models.SessionTurn{Original: "Please check the billing address.", Translation: "请检查账单地址。"})
+	sm.Record("", session.ID, models.SessionTurn{Original: "Total", Translation: "总计"})
+
+	// Only the most recent turns are kept
+	history, _, _ := sm.History("", session.ID)
+	if len(history.Turns) != 2 || history.Turns[1].Original != "Total" {
+		t.Errorf("Expected the last two turns, got %+v", history.Turns)
+	}
+
+	// Short segments become terms, full sentences do not
+	expected := map[string]string{"Invoice": "发票", "Billing Address": "账单地址", "Total": "总计"}
+	if len(history.Terms) != len(expected) {
+		t.Errorf("Expected terms %v, got %v", expected, history.Terms)
+	}
+	for source, target := range expected {
+		if history.Terms[source] != target {
+			t.Errorf("Expected term %s to be %s, got %q", source, target, history.Terms[source])
+		}
+	}
+
+	// Long turns are dropped to stay within the byte budget
+	long := strings.Repeat("a", sessionMaxBytes)
+	sm.Record("", session.ID, models.SessionTurn{Original: long, Translation: "长"})
+	history, _, _ = sm.History("", session.ID)
+	if len(history.Turns) != 1 || history.Turns[0].Translation != "长" {
+		t.Errorf("Expected only the latest turn within the byte budget, got %d turns", len(history.Turns))
+	}
+}
+
+func TestTranslatorService_Sessions(t *testing.T) {
+	cfg := &config.Config{ServerPort: "8080", Timeout: 30}
+	ts := NewTranslatorService(cfg)
+
+	// Create a translator that records the request it was given
+	var received *models.TranslationRequest
+	ts.translators["gpt-4"] = &MockTranslatorForTesting{
+		name: "Session Recorder",
+		translateFunc: func(ctx context.Context, req *models.TranslationRequest) (*models.TranslationResponse, error) {
+			received = req
+			return &models.TranslationResponse{Original: req.Text, Translation: "翻译:" + req.Text, Model: req.Model}, nil
+		},
+	}
+
+	// Sessions reject unsupported models
+	if _, err := ts.CreateSession(context.Background(), &models.SessionRequest{Model: "unknown-model"}); err == nil || !strings.Contains(err.Error(), "unsupported model") {
+		t.Errorf("Expected unsupported model error, got %v", err)
+	}
+
+	session, err := ts.CreateSession(context.Background(), &models.SessionRequest{Model: "gpt-4"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// The first translation uses the session's model and has no history
+	if _, err := ts.Translate(context.Background(), &models.TranslationRequest{Text: "Settings", SessionID: session.ID}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if received.Model != "gpt-4" || len(received.SessionHistory.Turns) != 0 {
+		t.Errorf("Unexpected first request: %+v", received)
+	}
+
+	// The second translation carries the first as history and as a term
+	if _, err := ts.Translate(context.Background(), &models.TranslationRequest{Text: "Open the settings.", SessionID: session.ID}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	history := received.SessionHistory
+	if len(history.Turns) != 1 || history.Turns[0].Translation != "翻译:Settings" {
+		t.Errorf("Expected the first turn in the history, got %+v", history.Turns)
+	}
+	if history.Terms["Settings"] != "翻译:Settings" {
+		t.Errorf("Expected an extracted term, got %v", history.Terms)
+	}
+
+	// Unknown sessions are rejected
+	_, err = ts.Translate(context.Background(), &models.TranslationRequest{Text: "Hello", SessionID: "missing"})
+	if err == nil || !strings.Contains(err.Error(), "validation error") {
+		t.Errorf("Expected validation error, got %v", err)
+	}
+}
+
+func TestProviders_SendSessionHistory(t *testing.T) {
+	req := &models.TranslationRequest{
+		Text: "Open the settings.",
+		SessionHistory: &models.SessionHistory{
+			Turns: []models.SessionTurn{{Original: "Settings", Translation: "设置"}},
+			Terms: map[string]string{"Settings": "设置"},
+		},
+	}
+	prompt := promptFor(req)
+
+	// Chat providers receive the earlier turn as a user and assistant exchange
+	messages := chatMessages(prompt, req)
+	if len(messages) != 4 || messages[1].Content != "Settings" || messages[2].Role != "assistant" || messages[3].Content != req.Text {
+		t.Errorf("Unexpected chat messages: %+v", messages)
+	}
+	if !strings.Contains(messages[0].Content, "- Settings: 设置") {
+		t.Errorf("Expected session terms in the system prompt, got %q", messages[0].Content)
+	}
+
+	anthropic := anthropicMessages(req)
+	if len(anthropic) != 3 || anthropic[1].Role != "assistant" || anthropic[1].Content != "设置" {
+		t.Errorf("Unexpected Anthropic messages: %+v", anthropic)
+	}
+
+	gemini := geminiContents(req)
+	if len(gemini) != 3 || gemini[1].Role != "model" {
+		t.Errorf("Unexpected Gemini contents: %+v", gemini)
+	}
+
+	completion := completionPrompt(prompt, req)
+	if !strings.HasSuffix(completion, "English: Settings\n\nChinese: 设置\n\nEnglish: Open the settings.\n\nChinese:") {
+		t.Errorf("Unexpected completion prompt: %q", completion)
+	}
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"sync"
+
+	"translator-service/internal/models"
+)
+
+// translateShared calls the provider for a request, asking for a shorter
+// translation while the output exceeds the context's max_length. Concurrent
+// identical requests share one call; requests in a session never do, since
+// their history differs.
+func (ts *TranslatorService) translateShared(ctx context.Context, translator models.Translator, req *models.TranslationRequest) (*models.TranslationResponse, error) {
+	// The call may outlive this request, so it works on a copy
+	callReq := *req
+	call := func(ctx context.Context) (*models.TranslationResponse, error) {
+		response, err := ts.translateWithRetry(ctx, translator, &callReq)
+		if err != nil {
+			return nil, err
+		}
+		return ts.enforceMaxLength(ctx, translator, &callReq, response)
+	}
+
+	if req.SessionID != "" {
+		return call(ctx)
+	}
+	response, shared, err := ts.flights.do(ctx, flightKey(TenantFromContext(ctx), req), call)
+	if shared {
+		ts.monitor.RecordCoalesced()
+	}
+	return response, err
+}
+
+// flight is a provider call shared by concurrent identical requests
+type flight struct {
+	done     chan struct{}
+	response *models.TranslationResponse
+	err      error
+
+	// waiters counts the requests waiting for the call; cancel stops it once none is left
+	waiters int
+	cancel  context.CancelFunc
+}
+
+// flightGroup coalesces concurrent identical requests so that only one of
+// them calls the provider and all receive its result
+type flightGroup struct {
+	mu      sync.Mutex
+	flights map[string]*flight
+}
+
+// newFlightGroup creates an empty flight group
+func newFlightGroup() *flightGroup {
+	return &flightGroup{flights: make(map[string]*flight)}
+}
+
+// do runs call for the first request with a key and makes later requests with
+// the same key wait for its result; shared reports whether the request joined
+// a call already in flight. The call runs on a context of its own, carrying
+// the first request's values, so it is not cut short when that request is
+// cancelled; each request stops waiting when its own context is done, and the
+// call is cancelled when no request is left waiting for it. The last request
+// to leave returns only once the cancelled call has.
+func (g *flightGroup) do(ctx context.Context, key string, call func(context.Context) (*models.TranslationResponse, error)) (response *models.TranslationResponse, shared bool, err error) {
+	g.mu.Lock()
+	f, shared := g.flights[key]
+	if !shared {
+		callCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		f = &flight{done: make(chan struct{}), cancel: cancel}
+		g.flights[key] = f
+
+		go func() {
+			f.response, f.err = call(callCtx)
+			g.mu.Lock()
+			g.forget(key, f)
+			g.mu.Unlock()
+			cancel()
+			close(f.done)
+		}()
+	}
+	f.waiters++
+	g.mu.Unlock()
+
+	select {
+	case <-f.done:
+		if f.err != nil {
+			return nil, shared, f.err
+		}
+		// Every request gets its own copy to fill in
+		copied := *f.response
+		return &copied, shared, nil
+	case <-ctx.Done():
+		g.mu.Lock()
+		f.waiters--
+		last := f.waiters == 0
+		if last {
+			f.cancel()
+			g.forget(key, f)
+		}
+		g.mu.Unlock()
+
+		// The last request waits for the cancelled call to return, so that no
+		// call outlives every request it was made for
+		if last {
+			<-f.done
+		}
+		return nil, shared, ctx.Err()
+	}
+}
+
+// forget removes a flight so later requests start a new call. g.mu must be held.
+func (g *flightGroup) forget(key string, f *flight) {
+	if g.flights[key] == f {
+		delete(g.flights, key)
+	}
+}
+
+// flightKey identifies requests of a tenant that call the provider in exactly
+// the same way: the model, the rendered prompt and every option of the request
+func flightKey(tenant string, req *models.TranslationRequest) string {
+	temperature, version, prompt := "", "", ""
+	if req.Temperature != nil {
+		temperature = strconv.FormatFloat(*req.Temperature, 'g', -1, 64)
+	}
+	if req.RenderedPrompt != nil {
+		version, prompt = req.RenderedPrompt.Version, req.RenderedPrompt.Text
+	}
+	parts := []string{reviewKey(tenant, req), req.Model, strconv.Itoa(req.MaxTokens), temperature, version, prompt}
+
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,195 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"translator-service/internal/config"
+	"translator-service/internal/models"
+)
+
+// blockingTranslator answers once released, counting its calls and the calls
+// whose context was cancelled
+type blockingTranslator struct {
+	release   chan struct{}
+	calls     atomic.Int32
+	cancelled chan struct{}
+}
+
+func newBlockingTranslator() *blockingTranslator {
+	return &blockingTranslator{release: make(chan struct{}), cancelled: make(chan struct{}, 10)}
+}
+
+func (b *blockingTranslator) Translate(ctx context.Context, req *models.TranslationRequest) (*models.TranslationResponse, error) {
+	b.calls.Add(1)
+	select {
+	case <-b.release:
+		return &models.TranslationResponse{Original: req.Text, Translation: "保存文件", Model: req.Model}, nil
+	case <-ctx.Done():
+		b.cancelled <- struct{}{}
+		return nil, ctx.Err()
+	}
+}
+
+func (b *blockingTranslator) Name() string {
+	return "blocking"
+}
+
+func (b *blockingTranslator) SupportsModel(model string) bool {
+	return true
+}
+
+// waitForWaiters waits until n requests wait for flights in the group
+func waitForWaiters(t *testing.T, g *flightGroup, n int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		g.mu.Lock()
+		waiters := 0
+		for _, f := range g.flights {
+			waiters += f.waiters
+		}
+		g.mu.Unlock()
+		if waiters == n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("Expected %d waiting requests", n)
+}
+
+func TestTranslatorService_CoalescesIdenticalRequests(t *testing.T) {
+	ts := NewTranslatorService(&config.Config{ServerPort: "8080", Timeout: 30})
+	translator := newBlockingTranslator()
+	ts.translators["gpt-4"] = translator
+
+	const requests = 10
+	var wg sync.WaitGroup
+	responses := make([]*models.TranslationResponse, requests)
+	errs := make([]error, requests)
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			responses[i], errs[i] = ts.Translate(context.Background(), &models.TranslationRequest{Text: "Save the file", Model: "gpt-4"})
+		}(i)
+	}
+	waitForWaiters(t, ts.flights, requests)
+	close(translator.release)
+	wg.Wait()
+
+	if calls := translator.calls.Load(); calls != 1 {
+		t.Errorf("Expected one provider call, got %d", calls)
+	}
+	for i := 0; i < requests; i++ {
+		if errs[i] != nil || responses[i].Translation != "保存文件" {
+			t.Errorf("Request %d: unexpected result %+v %v", i, responses[i], errs[i])
+		}
+	}
+	// Each request gets its own response
+	if responses[0] == responses[1] {
+		t.Errorf("Expected separate responses")
+	}
+	if coalesced := ts.AdminStatus().Volume.Coalesced; coalesced != requests-1 {
+		t.Errorf("Expected %d coalesced requests, got %d", requests-1, coalesced)
+	}
+
+	// Nothing is left in flight, so the next request calls the provider again
+	if _, err := ts.Translate(context.Background(), &models.TranslationRequest{Text: "Save the file", Model: "gpt-4"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if calls := translator.calls.Load(); calls != 2 {
+		t.Errorf("Expected a new provider call, got %d calls", calls)
+	}
+}
+
+func TestTranslatorService_DoesNotCoalesceDifferentRequests(t *testing.T) {
+	ts := NewTranslatorService(&config.Config{ServerPort: "8080", Timeout: 30})
+	translator := newBlockingTranslator()
+	ts.translators["gpt-4"] = translator
+
+	requests := []*models.TranslationRequest{
+		{Text: "Save the file", Model: "gpt-4"},
+		{Text: "Save the file", Model: "gpt-4", Formality: "formal"},
+		{Text: "Save the file", Model: "gpt-4", TargetLanguage: models.LanguageTraditionalChinese},
+		{Text: "Open the file", Model: "gpt-4"},
+	}
+	var wg sync.WaitGroup
+	for _, req := range requests {
+		wg.Add(1)
+		go func(req *models.TranslationRequest) {
+			defer wg.Done()
+			if _, err := ts.Translate(context.Background(), req); err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		}(req)
+	}
+	waitForWaiters(t, ts.flights, len(requests))
+	close(translator.release)
+	wg.Wait()
+
+	if calls := translator.calls.Load(); calls != int32(len(requests)) {
+		t.Errorf("Expected %d provider calls, got %d", len(requests), calls)
+	}
+}
+
+func TestTranslatorService_CoalescedCancellation(t *testing.T) {
+	ts := NewTranslatorService(&config.Config{ServerPort: "8080", Timeout: 30})
+	translator := newBlockingTranslator()
+	ts.translators["gpt-4"] = translator
+	req := &models.TranslationRequest{Text: "Save the file", Model: "gpt-4"}
+
+	// The first request is cancelled; the second still receives the result
+	firstCtx, cancelFirst := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := ts.Translate(firstCtx, req)
+		firstErr <- err
+	}()
+	waitForWaiters(t, ts.flights, 1)
+
+	secondResult := make(chan error, 1)
+	go func() {
+		_, err := ts.Translate(context.Background(), req)
+		secondResult <- err
+	}()
+	waitForWaiters(t, ts.flights, 2)
+
+	cancelFirst()
+	if err := <-firstErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the cancelled request to stop waiting, got %v", err)
+	}
+	close(translator.release)
+	if err := <-secondResult; err != nil {
+		t.Errorf("Expected the other request to succeed, got %v", err)
+	}
+	if calls := translator.calls.Load(); calls != 1 || len(translator.cancelled) != 0 {
+		t.Errorf("Expected one uncancelled provider call, got %d calls and %d cancelled", calls, len(translator.cancelled))
+	}
+}
+
+func TestTranslatorService_CoalescedCallCancelledWithoutWaiters(t *testing.T) {
+	ts := NewTranslatorService(&config.Config{ServerPort: "8080", Timeout: 30})
+	translator := newBlockingTranslator()
+	ts.translators["gpt-4"] = translator
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := ts.Translate(ctx, &models.TranslationRequest{Text: "Save the file", Model: "gpt-4"})
+		done <- err
+	}()
+	waitForWaiters(t, ts.flights, 1)
+
+	cancel()
+	<-done
+	select {
+	case <-translator.cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the provider call to be cancelled once no request waits for it")
+	}
+}
@@ -0,0 +1,448 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"translator-service/internal/config"
+	"translator-service/internal/models"
+	"translator-service/internal/storage"
+)
+
+var (
+	// ErrInvalidAPIKey is returned when an API key does not belong to any tenant
+	ErrInvalidAPIKey = errors.New("invalid API key")
+
+	// ErrAPIKeyRequired is returned when a tenant is named without an API key and
+	// does not allow the X-Tenant-ID header alone
+	ErrAPIKeyRequired = errors.New("tenant requires an API key")
+)
+
+// tenantIDPattern matches the IDs accepted for tenants created through the admin API
+var tenantIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`)
+
+// TenantAccessError reports that a tenant's text may not be sent to a model's provider
+type TenantAccessError struct {
+	Tenant string
+	Model  string
+	// Provider is empty when the tenant is not configured or is disabled
+	Provider models.ProviderInfo
+	Disabled bool
+}
+
+func (e *TenantAccessError) Error() string {
+	if e.Disabled {
+		return fmt.Sprintf("tenant access denied: tenant %s is disabled", e.Tenant)
+	}
+	if e.Provider.Name == "" {
+		return fmt.Sprintf("tenant access denied: unknown tenant %s", e.Tenant)
+	}
+	return fmt.Sprintf("tenant access denied: tenant %s may not send text to %s at %s (model %s)",
+		e.Tenant, e.Provider.Name, e.Provider.Endpoint, e.Model)
+}
+
+// tenantTranslators are the translators built with a tenant's own provider keys
+type tenantTranslators struct {
+	openAIKey    string
+	anthropicKey string
+	translators  map[string]models.Translator
+}
+
+// SetTenantStore replaces the store holding the tenants created through the admin API
+func (ts *TranslatorService) SetTenantStore(store storage.TenantStore) {
+	ts.tenants = store
+}
+
+// Tenant returns a tenant defined in the config file or created through the
+// admin API. Tenants in the config file take precedence.
+func (ts *TranslatorService) Tenant(ctx context.Context, id string) (*models.Tenant, error) {
+	if settings, ok := ts.Config().Tenants[id]; ok {
+		return configTenant(id, settings), nil
+	}
+	tenant, err := ts.tenants.GetTenant(ctx, id)
+	if errors.Is(err, storage.ErrTenantNotFound) {
+		return nil, fmt.Errorf("tenant %s: %w", id, err)
+	}
+	return tenant, err
+}
+
+// ListTenants returns the tenants defined in the config file and those created
+// through the admin API, ordered by ID
+func (ts *TranslatorService) ListTenants(ctx context.Context) ([]models.Tenant, error) {
+	stored, err := ts.tenants.ListTenants(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	configured := ts.Config().Tenants
+	tenants := make([]models.Tenant, 0, len(configured)+len(stored))
+	for id, settings := range configured {
+		tenants = append(tenants, *configTenant(id, settings))
+	}
+	for _, tenant := range stored {
+		if _, ok := configured[tenant.ID]; !ok {
+			tenants = append(tenants, tenant)
+		}
+	}
+
+	sort.Slice(tenants, func(i, j int) bool {
+		return tenants[i].ID < tenants[j].ID
+	})
+	return tenants, nil
+}
+
+// CreateTenant creates a tenant with a new API key, which is returned only once
+func (ts *TranslatorService) CreateTenant(ctx context.Context, req *models.TenantRequest) (*models.TenantCreated, error) {
+	if err := ts.validationService.validateTenantRequest(req, ts.GetSupportedModels()); err != nil {
+		return nil, fmt.Errorf("validation error: %w", err)
+	}
+	if _, ok := ts.Config().Tenants[req.ID]; ok {
+		return nil, fmt.Errorf("tenant conflict: tenant %s is defined in the config file", req.ID)
+	}
+
+	apiKey, err := newAPIKey()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	tenant := &models.Tenant{
+		ID:               req.ID,
+		Source:           models.TenantSourceAPI,
+		APIKeyHashes:     []string{apiKeyHash(apiKey)},
+		OpenAIKey:        req.OpenAIKey,
+		AnthropicKey:     req.AnthropicKey,
+		DefaultModel:     req.DefaultModel,
+		TargetLanguage:   req.TargetLanguage,
+		AllowedProviders: req.AllowedProviders,
+		AllowedEndpoints: req.AllowedEndpoints,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+	if err := ts.tenants.CreateTenant(ctx, tenant); err != nil {
+		if errors.Is(err, storage.ErrTenantExists) {
+			return nil, fmt.Errorf("tenant conflict: %w", err)
+		}
+		return nil, err
+	}
+
+	log.Printf("Tenant %s created", tenant.ID)
+	return &models.TenantCreated{Tenant: *tenant, APIKey: apiKey}, nil
+}
+
+// UpdateTenant disables or re-enables a tenant created through the admin API,
+// or replaces its provider keys
+func (ts *TranslatorService) UpdateTenant(ctx context.Context, id string, update *models.TenantUpdate) (*models.Tenant, error) {
+	if err := ts.validationService.validateTenantUpdate(update); err != nil {
+		return nil, fmt.Errorf("validation error: %w", err)
+	}
+	if _, ok := ts.Config().Tenants[id]; ok {
+		return nil, fmt.Errorf("tenant conflict: tenant %s is defined in the config file; change it there", id)
+	}
+
+	tenant, err := ts.Tenant(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if update.Disabled != nil {
+		tenant.Disabled = *update.Disabled
+	}
+	if update.OpenAIKey != nil {
+		tenant.OpenAIKey = *update.OpenAIKey
+	}
+	if update.AnthropicKey != nil {
+		tenant.AnthropicKey = *update.AnthropicKey
+	}
+	tenant.UpdatedAt = time.Now().UTC()
+	if err := ts.tenants.UpdateTenant(ctx, tenant); err != nil {
+		return nil, err
+	}
+
+	log.Printf("Tenant %s updated (disabled: %v)", id, tenant.Disabled)
+	return tenant, nil
+}
+
+// ResolveTenant identifies the tenant a request is made for, from its API key
+// or from the tenant ID it names. The ID alone is only accepted for tenants
+// that allow it; requests with neither have no tenant. While no tenants are
+// defined, IDs are passed through unchecked as tenants without any settings.
+// The tenant is resolved once per request and carried in its context with
+// WithTenant.
+func (ts *TranslatorService) ResolveTenant(ctx context.Context, apiKey, id string) (*models.Tenant, error) {
+	if apiKey != "" {
+		tenant, err := ts.tenantForAPIKey(ctx, apiKey)
+		if err != nil {
+			return nil, err
+		}
+		if tenant == nil || id != "" && id != tenant.ID {
+			return nil, ErrInvalidAPIKey
+		}
+		if tenant.Disabled {
+			return nil, &TenantAccessError{Tenant: tenant.ID, Disabled: true}
+		}
+		return resolveTenantKeys(tenant)
+	}
+
+	if id == "" {
+		return nil, nil
+	}
+	tenant, err := ts.Tenant(ctx, id)
+	if errors.Is(err, storage.ErrTenantNotFound) {
+		defined, err := ts.hasTenants(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if defined {
+			return nil, &TenantAccessError{Tenant: id}
+		}
+		return &models.Tenant{ID: id}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !tenant.AllowTenantHeader {
+		return nil, ErrAPIKeyRequired
+	}
+	if tenant.Disabled {
+		return nil, &TenantAccessError{Tenant: id, Disabled: true}
+	}
+	return resolveTenantKeys(tenant)
+}
+
+// resolveTenantKeys replaces the provider key references of a tenant created
+// through the admin API with the keys. Tenants in the config file have theirs
+// resolved when it is loaded.
+func resolveTenantKeys(tenant *models.Tenant) (*models.Tenant, error) {
+	if tenant.Source != models.TenantSourceAPI {
+		return tenant, nil
+	}
+
+	var err error
+	if tenant.OpenAIKey != "" {
+		if tenant.OpenAIKey, err = config.ResolveStoredSecret(tenant.OpenAIKey); err != nil {
+			return nil, fmt.Errorf("tenant %s openai key: %w", tenant.ID, err)
+		}
+	}
+	if tenant.AnthropicKey != "" {
+		if tenant.AnthropicKey, err = config.ResolveStoredSecret(tenant.AnthropicKey); err != nil {
+			return nil, fmt.Errorf("tenant %s anthropic key: %w", tenant.ID, err)
+		}
+	}
+	return tenant, nil
+}
+
+// tenantForAPIKey returns the tenant an API key belongs to, or nil if there is none
+func (ts *TranslatorService) tenantForAPIKey(ctx context.Context, apiKey string) (*models.Tenant, error) {
+	hash := apiKeyHash(apiKey)
+	for id, settings := range ts.Config().Tenants {
+		tenant := configTenant(id, settings)
+		for _, tenantHash := range tenant.APIKeyHashes {
+			if subtle.ConstantTimeCompare([]byte(hash), []byte(tenantHash)) == 1 {
+				return tenant, nil
+			}
+		}
+	}
+	return ts.tenants.FindTenantByAPIKey(ctx, hash)
+}
+
+// hasTenants reports whether any tenant is defined
+func (ts *TranslatorService) hasTenants(ctx context.Context) (bool, error) {
+	if len(ts.Config().Tenants) > 0 {
+		return true, nil
+	}
+	stored, err := ts.tenants.ListTenants(ctx)
+	if err != nil {
+		return false, err
+	}
+	return len(stored) > 0, nil
+}
+
+// DefaultModel returns the model used for the request's tenant when a request
+// names none, or an empty string if it has no default
+func (ts *TranslatorService) DefaultModel(ctx context.Context) string {
+	if tenant := requestTenant(ctx); tenant != nil {
+		return tenant.DefaultModel
+	}
+	return ""
+}
+
+// authorizeProvider checks that the model has not been disabled and that the
+// request's tenant may send text to the translator's provider. It must be
+// called before every call to a provider. Requests without a tenant, and
+// translators that send nothing outside the service, are always allowed.
+func (ts *TranslatorService) authorizeProvider(ctx context.Context, model string, translator models.Translator) error {
+	if ts.modelDisabled(model) {
+		return fmt.Errorf("model disabled: %s", model)
+	}
+
+	tenant := requestTenant(ctx)
+	if tenant == nil {
+		return nil
+	}
+	if tenant.Disabled {
+		return &TenantAccessError{Tenant: tenant.ID, Model: model, Disabled: true}
+	}
+
+	pt, ok := translator.(models.ProviderTranslator)
+	if !ok {
+		return nil
+	}
+	provider := pt.Provider()
+	if !tenantAllows(tenant, provider) {
+		return &TenantAccessError{Tenant: tenant.ID, Model: model, Provider: provider}
+	}
+	return nil
+}
+
+// tenantAllows reports whether a tenant's settings allow a provider and its endpoint
+func tenantAllows(tenant *models.Tenant, provider models.ProviderInfo) bool {
+	if len(tenant.AllowedProviders) > 0 && !containsString(tenant.AllowedProviders, provider.Name) {
+		return false
+	}
+	if len(tenant.AllowedEndpoints) == 0 {
+		return true
+	}
+
+	endpoint := strings.TrimSuffix(provider.Endpoint, "/")
+	for _, allowed := range tenant.AllowedEndpoints {
+		allowed = strings.TrimSuffix(allowed, "/")
+		if endpoint == allowed || strings.HasPrefix(endpoint, allowed+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// translatorsFor returns the translators for the request's tenant: those built
+// with its own provider keys when it has any, otherwise the shared ones
+func (ts *TranslatorService) translatorsFor(ctx context.Context) map[string]models.Translator {
+	tenant := requestTenant(ctx)
+
+	ts.mu.RLock()
+	if tenant == nil || !tenant.HasOwnKeys() {
+		defer ts.mu.RUnlock()
+		return ts.translators
+	}
+	cached, ok := ts.tenantTranslators[tenant.ID]
+	ts.mu.RUnlock()
+
+	if ok && cached.openAIKey == tenant.OpenAIKey && cached.anthropicKey == tenant.AnthropicKey {
+		return cached.translators
+	}
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	// Build the tenant's translators from the current configuration with its keys swapped in
+	cfg := *ts.config
+	if tenant.OpenAIKey != "" {
+		cfg.OpenAIKey = tenant.OpenAIKey
+	}
+	if tenant.AnthropicKey != "" {
+		cfg.AnthropicKey = tenant.AnthropicKey
+	}
+	cached = tenantTranslators{
+		openAIKey:    tenant.OpenAIKey,
+		anthropicKey: tenant.AnthropicKey,
+		translators:  buildTranslators(&cfg, ts.httpClient),
+	}
+	ts.tenantTranslators[tenant.ID] = cached
+	return cached.translators
+}
+
+// configTenant converts a tenant defined in the config file
+func configTenant(id string, settings config.TenantConfig) *models.Tenant {
+	tenant := &models.Tenant{
+		ID:                id,
+		Source:            models.TenantSourceConfig,
+		AllowTenantHeader: settings.AllowTenantHeader,
+		OpenAIKey:         settings.OpenAIKey,
+		AnthropicKey:      settings.AnthropicKey,
+		DefaultModel:      settings.DefaultModel,
+		TargetLanguage:    settings.TargetLanguage,
+		AllowedProviders:  settings.AllowedProviders,
+		AllowedEndpoints:  settings.AllowedEndpoints,
+		Disabled:          settings.Disabled,
+	}
+	for _, key := range settings.APIKeys {
+		tenant.APIKeyHashes = append(tenant.APIKeyHashes, apiKeyHash(key))
+	}
+	return tenant
+}
+
+// newAPIKey generates a random tenant API key
+func newAPIKey() (string, error) {
+	key := make([]byte, 24)
+	if _, err := rand.Read(key); err != nil {
+		return "", fmt.Errorf("failed to generate API key: %w", err)
+	}
+	return "tsk_" + hex.EncodeToString(key), nil
+}
+
+// apiKeyHash returns the hash under which an API key is stored
+func apiKeyHash(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:])
+}
+
+// validateTenantRequest validates an admin request to create a tenant
+func (vs *ValidationService) validateTenantRequest(req *models.TenantRequest, supportedModels []string) error {
+	req.ID = strings.TrimSpace(req.ID)
+	req.DefaultModel = strings.TrimSpace(req.DefaultModel)
+
+	if !tenantIDPattern.MatchString(req.ID) {
+		return &ValidationError{"ID must be 1 to 64 letters, digits, dots, dashes or underscores"}
+	}
+	if req.DefaultModel != "" && !containsString(supportedModels, req.DefaultModel) {
+		return &ValidationError{"Unsupported default model: " + req.DefaultModel}
+	}
+	if err := vs.ValidateTargetLanguage(req.TargetLanguage); err != nil {
+		return err
+	}
+	if err := config.ValidateTenantRestrictions(req.AllowedProviders, req.AllowedEndpoints); err != nil {
+		return &ValidationError{err.Error()}
+	}
+	return validateTenantKeys(req.OpenAIKey, req.AnthropicKey)
+}
+
+// validateTenantUpdate validates an admin request to change a tenant
+func (vs *ValidationService) validateTenantUpdate(update *models.TenantUpdate) error {
+	if update.Disabled == nil && update.OpenAIKey == nil && update.AnthropicKey == nil {
+		return &ValidationError{"Nothing to update: set disabled, openai_key or anthropic_key"}
+	}
+
+	var openAIKey, anthropicKey string
+	if update.OpenAIKey != nil {
+		openAIKey = *update.OpenAIKey
+	}
+	if update.AnthropicKey != nil {
+		anthropicKey = *update.AnthropicKey
+	}
+	return validateTenantKeys(openAIKey, anthropicKey)
+}
+
+// validateTenantKeys checks that the provider keys of a tenant created through
+// the admin API are references, so that no key is stored in plain text
+func validateTenantKeys(openAIKey, anthropicKey string) error {
+	if openAIKey != "" {
+		if err := config.ValidateStoredSecret(openAIKey); err != nil {
+			return &ValidationError{"openai_key " + err.Error()}
+		}
+	}
+	if anthropicKey != "" {
+		if err := config.ValidateStoredSecret(anthropicKey); err != nil {
+			return &ValidationError{"anthropic_key " + err.Error()}
+		}
+	}
+	return nil
+}
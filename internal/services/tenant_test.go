@@ -0,0 +1,394 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"translator-service/internal/config"
+	"translator-service/internal/models"
+	"translator-service/internal/storage"
+)
+
+// ProviderTranslatorForTesting is a mock translator that reports an external provider
+type ProviderTranslatorForTesting struct {
+	MockTranslatorForTesting
+	provider models.ProviderInfo
+}
+
+func (p *ProviderTranslatorForTesting) Provider() models.ProviderInfo {
+	return p.provider
+}
+
+// tenantContext returns a context carrying a tenant the way the tenant
+// middleware resolves it
+func tenantContext(t *testing.T, ts *TranslatorService, id string) context.Context {
+	t.Helper()
+	if id == "" {
+		return context.Background()
+	}
+	tenant, err := ts.Tenant(context.Background(), id)
+	if errors.Is(err, storage.ErrTenantNotFound) {
+		tenant = &models.Tenant{ID: id}
+	} else if err != nil {
+		t.Fatalf("Failed to look up tenant %s: %v", id, err)
+	}
+	return WithTenant(context.Background(), tenant)
+}
+
+// tenantID returns the ID of a resolved tenant, or an empty string for none
+func tenantID(tenant *models.Tenant) string {
+	if tenant == nil {
+		return ""
+	}
+	return tenant.ID
+}
+
+func TestTenantAllows(t *testing.T) {
+	provider := models.ProviderInfo{Name: "azure", Endpoint: "https://eu-resource.openai.azure.com"}
+
+	tests := []struct {
+		name     string
+		settings models.Tenant
+		expected bool
+	}{
+		{"No restrictions", models.Tenant{}, true},
+		{"Allowed provider", models.Tenant{AllowedProviders: []string{"azure", "deepl"}}, true},
+		{"Other provider", models.Tenant{AllowedProviders: []string{"anthropic"}}, false},
+		{"Allowed endpoint", models.Tenant{AllowedEndpoints: []string{"https://eu-resource.openai.azure.com/"}}, true},
+		{"Endpoint sharing a prefix", models.Tenant{AllowedEndpoints: []string{"https://eu-resource.openai.azure"}}, false},
+		{"Allowed provider at another endpoint", models.Tenant{AllowedProviders: []string{"azure"}, AllowedEndpoints: []string{"https://us-resource.openai.azure.com"}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if allowed := tenantAllows(&tt.settings, provider); allowed != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, allowed)
+			}
+		})
+	}
+}
+
+func TestTranslatorService_TenantRestrictions(t *testing.T) {
+	cfg := &config.Config{ServerPort: "8080", Timeout: 30, Tenants: map[string]config.TenantConfig{
+		"acme":    {AllowedProviders: []string{"anthropic"}},
+		"initech": {},
+		"globex":  {Disabled: true},
+	}}
+	ts := NewTranslatorService(cfg)
+
+	// Create an OpenAI and an Anthropic translator that count their calls
+	calls := make(map[string]int)
+	translatorFor := func(provider string) *ProviderTranslatorForTesting {
+		return &ProviderTranslatorForTesting{
+			MockTranslatorForTesting: MockTranslatorForTesting{
+				name: provider,
+				translateFunc: func(ctx context.Context, req *models.TranslationRequest) (*models.TranslationResponse, error) {
+					calls[provider]++
+					return &models.TranslationResponse{Original: req.Text, Translation: "保存", Model: req.Model}, nil
+				},
+			},
+			provider: models.ProviderInfo{Name: provider, Endpoint: "https://api." + provider + ".com/v1"},
+		}
+	}
+	ts.translators["gpt-4"] = translatorFor("openai")
+	ts.translators["claude"] = translatorFor("anthropic")
+
+	tests := []struct {
+		name    string
+		tenant  string
+		model   string
+		allowed bool
+	}{
+		{"No tenant", "", "gpt-4", true},
+		{"Allowed provider", "acme", "claude", true},
+		{"Forbidden provider", "acme", "gpt-4", false},
+		{"Unrestricted tenant", "initech", "gpt-4", true},
+		{"Disabled tenant", "globex", "claude", false},
+		{"Mock model", "acme", "llama", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := tenantContext(t, ts, tt.tenant)
+			before := calls["openai"] + calls["anthropic"]
+			_, err := ts.Translate(ctx, &models.TranslationRequest{Text: "Save", Model: tt.model})
+
+			var accessErr *TenantAccessError
+			if tt.allowed && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if !tt.allowed && !errors.As(err, &accessErr) {
+				t.Errorf("Expected TenantAccessError, got %v", err)
+			}
+			if !tt.allowed && calls["openai"]+calls["anthropic"] != before {
+				t.Errorf("Expected no provider call for a forbidden model")
+			}
+		})
+	}
+
+	// Models used on the tenant's behalf are checked as well
+	ctx := tenantContext(t, ts, "acme")
+	response, err := ts.Translate(ctx, &models.TranslationRequest{Text: "Save", Model: "claude", Verify: VerifyBackTranslation})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response.Verification.Error != "" {
+		t.Errorf("Unexpected verification error: %s", response.Verification.Error)
+	}
+
+	reloaded := *cfg
+	reloaded.BackTranslationModel = "gpt-4"
+	ts.Reload(&reloaded, nil)
+	ts.translators["gpt-4"] = translatorFor("openai")
+	ts.translators["claude"] = translatorFor("anthropic")
+	before := calls["openai"]
+	response, err = ts.Translate(ctx, &models.TranslationRequest{Text: "Save", Model: "claude", Verify: VerifyBackTranslation})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response.Verification.Error == "" || calls["openai"] != before {
+		t.Errorf("Expected the back-translation with a forbidden model to fail without a call, got %+v", response.Verification)
+	}
+}
+
+func TestTranslatorService_ResolveTenant(t *testing.T) {
+	cfg := &config.Config{ServerPort: "8080", Timeout: 30, Tenants: map[string]config.TenantConfig{
+		"acme":    {APIKeys: []string{"acme-key"}},
+		"initech": {AllowTenantHeader: true},
+		"globex":  {APIKeys: []string{"globex-key"}, Disabled: true},
+	}}
+	ts := NewTranslatorService(cfg)
+
+	tests := []struct {
+		name     string
+		apiKey   string
+		id       string
+		expected string
+		err      error
+	}{
+		{"No tenant", "", "", "", nil},
+		{"API key", "acme-key", "", "acme", nil},
+		{"API key and matching ID", "acme-key", "acme", "acme", nil},
+		{"API key and other ID", "acme-key", "initech", "", ErrInvalidAPIKey},
+		{"Unknown API key", "other-key", "", "", ErrInvalidAPIKey},
+		{"ID of a tenant allowing it", "", "initech", "initech", nil},
+		{"ID of a tenant with keys", "", "acme", "", ErrAPIKeyRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tenant, err := ts.ResolveTenant(context.Background(), tt.apiKey, tt.id)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Expected error %v, got %v", tt.err, err)
+			}
+			if id := tenantID(tenant); id != tt.expected {
+				t.Errorf("Expected tenant %q, got %q", tt.expected, id)
+			}
+		})
+	}
+
+	// Unknown and disabled tenants are refused
+	var accessErr *TenantAccessError
+	if _, err := ts.ResolveTenant(context.Background(), "", "umbrella"); !errors.As(err, &accessErr) || accessErr.Disabled {
+		t.Errorf("Expected unknown tenant error, got %v", err)
+	}
+	if _, err := ts.ResolveTenant(context.Background(), "globex-key", ""); !errors.As(err, &accessErr) || !accessErr.Disabled {
+		t.Errorf("Expected disabled tenant error, got %v", err)
+	}
+
+	// Without any tenants, tenant IDs only label the request
+	ts = NewTranslatorService(&config.Config{ServerPort: "8080", Timeout: 30})
+	if tenant, err := ts.ResolveTenant(context.Background(), "", "umbrella"); err != nil || tenant == nil || tenant.ID != "umbrella" {
+		t.Errorf("Expected the tenant ID to be passed through, got %+v (%v)", tenant, err)
+	}
+}
+
+func TestTranslatorService_TenantDefaults(t *testing.T) {
+	cfg := &config.Config{ServerPort: "8080", Timeout: 30, Tenants: map[string]config.TenantConfig{
+		"acme": {DefaultModel: "claude", TargetLanguage: models.LanguageTraditionalChinese},
+	}}
+	ts := NewTranslatorService(cfg)
+
+	var received models.TranslationRequest
+	ts.translators["claude"] = &MockTranslatorForTesting{
+		name: "claude",
+		translateFunc: func(ctx context.Context, req *models.TranslationRequest) (*models.TranslationResponse, error) {
+			received = *req
+			return &models.TranslationResponse{Original: req.Text, Translation: "儲存", Model: req.Model}, nil
+		},
+	}
+
+	ctx := tenantContext(t, ts, "acme")
+	if _, err := ts.Translate(ctx, &models.TranslationRequest{Text: "Save"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if received.Model != "claude" || received.TargetLanguage != models.LanguageTraditionalChinese {
+		t.Errorf("Expected the tenant's defaults, got model %q and language %q", received.Model, received.TargetLanguage)
+	}
+
+	// The request's own settings win
+	ts.translators["gpt-4"] = ts.translators["claude"]
+	if _, err := ts.Translate(ctx, &models.TranslationRequest{Text: "Save", Model: "gpt-4", TargetLanguage: models.LanguageSimplifiedChinese}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if received.Model != "gpt-4" || received.TargetLanguage != models.LanguageSimplifiedChinese {
+		t.Errorf("Expected the request's settings, got model %q and language %q", received.Model, received.TargetLanguage)
+	}
+
+	// Unknown target languages are rejected
+	if _, err := ts.Translate(ctx, &models.TranslationRequest{Text: "Save", TargetLanguage: "fr"}); err == nil {
+		t.Errorf("Expected error for unsupported target language")
+	}
+}
+
+func TestTranslatorService_TenantProviderKeys(t *testing.T) {
+	cfg := &config.Config{ServerPort: "8080", Timeout: 30, Tenants: map[string]config.TenantConfig{
+		"acme":    {OpenAIKey: "sk-acme"},
+		"initech": {},
+	}}
+	ts := NewTranslatorService(cfg)
+
+	// Without a global key, OpenAI models are mocks except for the tenant with its own key
+	for _, tenant := range []string{"", "initech"} {
+		translator, _ := ts.translator(tenantContext(t, ts, tenant), "gpt-4")
+		if _, ok := translator.(*MockTranslator); !ok {
+			t.Errorf("Expected tenant %q to use the shared mock translator, got %T", tenant, translator)
+		}
+	}
+
+	acme := tenantContext(t, ts, "acme")
+	translator, _ := ts.translator(acme, "gpt-4")
+	if _, ok := translator.(*OpenAITranslator); !ok {
+		t.Fatalf("Expected an OpenAI translator with the tenant's key, got %T", translator)
+	}
+	if cached, _ := ts.translator(acme, "gpt-4"); cached != translator {
+		t.Errorf("Expected the tenant's translators to be reused")
+	}
+}
+
+func TestTranslatorService_AdminTenants(t *testing.T) {
+	cfg := &config.Config{ServerPort: "8080", Timeout: 30, Tenants: map[string]config.TenantConfig{
+		"acme": {},
+	}}
+	ts := NewTranslatorService(cfg)
+	ctx := context.Background()
+
+	created, err := ts.CreateTenant(ctx, &models.TenantRequest{ID: "globex", DefaultModel: "gpt-4", AllowedProviders: []string{"openai"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if created.APIKey == "" || created.Source != models.TenantSourceAPI {
+		t.Errorf("Unexpected created tenant: %+v", created)
+	}
+
+	// Invalid and conflicting tenants are refused
+	invalid := []*models.TenantRequest{
+		{ID: "has spaces"},
+		{ID: "initech", DefaultModel: "no-such-model"},
+		{ID: "initech", TargetLanguage: "fr"},
+		{ID: "initech", AllowedProviders: []string{"openai-eu"}},
+		{ID: "initech", OpenAIKey: "sk-literal"},
+		{ID: "initech", AnthropicKey: "exec:cat /run/secrets/anthropic"},
+	}
+	for _, req := range invalid {
+		if _, err := ts.CreateTenant(ctx, req); err == nil || !strings.Contains(err.Error(), "validation error") {
+			t.Errorf("Expected validation error for %+v, got %v", req, err)
+		}
+	}
+	for _, id := range []string{"acme", "globex"} {
+		if _, err := ts.CreateTenant(ctx, &models.TenantRequest{ID: id}); err == nil || !strings.Contains(err.Error(), "tenant conflict") {
+			t.Errorf("Expected conflict for %s, got %v", id, err)
+		}
+	}
+
+	// The returned key identifies the tenant
+	if tenant, err := ts.ResolveTenant(ctx, created.APIKey, ""); err != nil || tenant == nil || tenant.ID != "globex" {
+		t.Errorf("Expected the key to identify globex, got %+v (%v)", tenant, err)
+	}
+
+	tenants, err := ts.ListTenants(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(tenants) != 2 || tenants[0].Source != models.TenantSourceConfig || tenants[1].ID != "globex" {
+		t.Errorf("Unexpected tenants: %+v", tenants)
+	}
+
+	// Disabling a tenant refuses its key; config tenants are disabled in the config file
+	disabled := true
+	if _, err := ts.UpdateTenant(ctx, "globex", &models.TenantUpdate{Disabled: &disabled}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var accessErr *TenantAccessError
+	if _, err := ts.ResolveTenant(ctx, created.APIKey, ""); !errors.As(err, &accessErr) || !accessErr.Disabled {
+		t.Errorf("Expected disabled tenant error, got %v", err)
+	}
+	if _, err := ts.UpdateTenant(ctx, "acme", &models.TenantUpdate{Disabled: &disabled}); err == nil || !strings.Contains(err.Error(), "tenant conflict") {
+		t.Errorf("Expected conflict for a config tenant, got %v", err)
+	}
+	if _, err := ts.UpdateTenant(ctx, "umbrella", &models.TenantUpdate{Disabled: &disabled}); err == nil || !strings.Contains(err.Error(), "tenant not found") {
+		t.Errorf("Expected not found for an unknown tenant, got %v", err)
+	}
+}
+
+func TestTranslatorService_AdminTenantProviderKeys(t *testing.T) {
+	t.Setenv("TEST_GLOBEX_OPENAI_KEY", "sk-globex")
+	ts := NewTranslatorService(&config.Config{ServerPort: "8080", Timeout: 30})
+	ctx := context.Background()
+
+	created, err := ts.CreateTenant(ctx, &models.TenantRequest{ID: "globex", OpenAIKey: "env:TEST_GLOBEX_OPENAI_KEY"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// The reference is stored and the key is resolved with the request's tenant
+	if stored, err := ts.Tenant(ctx, "globex"); err != nil || stored.OpenAIKey != "env:TEST_GLOBEX_OPENAI_KEY" {
+		t.Errorf("Expected the key reference to be stored, got %+v (%v)", stored, err)
+	}
+	tenant, err := ts.ResolveTenant(ctx, created.APIKey, "")
+	if err != nil || tenant.OpenAIKey != "sk-globex" {
+		t.Fatalf("Expected the key to be resolved, got %+v (%v)", tenant, err)
+	}
+	if translator, _ := ts.translator(WithTenant(ctx, tenant), "gpt-4"); translator.Name() != "OpenAI" {
+		t.Errorf("Expected an OpenAI translator with the tenant's key, got %s", translator.Name())
+	}
+
+	// Keys are replaced with references only
+	literal := "sk-literal"
+	if _, err := ts.UpdateTenant(ctx, "globex", &models.TenantUpdate{OpenAIKey: &literal}); err == nil || !strings.Contains(err.Error(), "validation error") {
+		t.Errorf("Expected validation error for a literal key, got %v", err)
+	}
+	if _, err := ts.UpdateTenant(ctx, "globex", &models.TenantUpdate{}); err == nil || !strings.Contains(err.Error(), "validation error") {
+		t.Errorf("Expected validation error for an empty update, got %v", err)
+	}
+	unset := "env:TEST_GLOBEX_UNSET"
+	if _, err := ts.UpdateTenant(ctx, "globex", &models.TenantUpdate{OpenAIKey: &unset}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := ts.ResolveTenant(ctx, created.APIKey, ""); err == nil {
+		t.Errorf("Expected error for a key reference that does not resolve")
+	}
+}
+
+func TestTranslatorService_TenantHistory(t *testing.T) {
+	ts := NewTranslatorService(&config.Config{ServerPort: "8080", Timeout: 30})
+	ts.SetHistoryStore(storage.NewMemoryHistoryStore(0))
+
+	for _, tenant := range []string{"", "acme", "initech"} {
+		if _, err := ts.Translate(tenantContext(t, ts, tenant), &models.TranslationRequest{Text: "Save", Model: "gpt-4"}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	for _, tenant := range []string{"", "acme", "initech"} {
+		page, err := ts.HistoryStore().Search(context.Background(), models.HistoryQuery{Tenant: tenant})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if page.Total != 1 || page.Entries[0].Tenant != tenant {
+			t.Errorf("Expected one entry for tenant %q, got %+v", tenant, page)
+		}
+	}
+}
@@ -4,117 +4,390 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"math/rand/v2"
+	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"translator-service/internal/config"
 	"translator-service/internal/models"
+	"translator-service/internal/prompts"
+	"translator-service/internal/storage"
 )
 
 // TranslatorService manages multiple translation providers
 type TranslatorService struct {
+	// mu guards translators, prompts and config, which are swapped together on
+	// reload, the translators built for tenants with their own provider keys,
+	// and the disabled models and alias weights set by an administrator
+	mu                sync.RWMutex
 	translators       map[string]models.Translator
+	tenantTranslators map[string]tenantTranslators
+	disabledModels    map[string]bool
+	aliasWeights      map[string]map[string]int
+	prompts           *prompts.Store
+	sessions          *SessionManager
 	validationService *ValidationService
-	config            *config.Config
+	history           storage.HistoryStore
+	reviews           storage.ReviewStore
+	reviewMu          sync.Mutex
+	votes             storage.VoteStore
+	tenants           storage.TenantStore
+	filter            ContentFilter
+	routing           RoutingPolicy
+	monitor           *Monitor
+	flights           *flightGroup
+	// httpClient is shared by all providers, including those built for tenants
+	httpClient *http.Client
+	// randIntN picks the model an alias routes a request to
+	randIntN func(n int) int
+	config   *config.Config
 }
 
 // NewTranslatorService creates a new translator service
 func NewTranslatorService(cfg *config.Config) *TranslatorService {
 	service := &TranslatorService{
-		translators:       make(map[string]models.Translator),
 		validationService: NewValidationService(),
+		history:           storage.NewMemoryHistoryStore(0),
+		votes:             storage.NewMemoryVoteStore(),
+		tenants:           storage.NewMemoryTenantStore(),
+		tenantTranslators: make(map[string]tenantTranslators),
+		disabledModels:    make(map[string]bool),
+		aliasWeights:      make(map[string]map[string]int),
+		randIntN:          rand.IntN,
+		monitor:           NewMonitor(),
+		flights:           newFlightGroup(),
+		prompts:           prompts.Builtin(),
+		sessions:          NewSessionManager(cfg.MaxSessions, cfg.SessionMaxTurns, cfg.GetSessionTTL()),
 		config:            cfg,
 	}
 
 	// Register supported translators
-	service.registerTranslators()
+	service.httpClient = newHTTPClient(cfg)
+	service.translators = buildTranslators(cfg, service.httpClient)
 
 	return service
 }
 
-// registerTranslators registers all supported translation providers
-func (ts *TranslatorService) registerTranslators() {
+// Reload replaces the configuration, the registered translation providers and,
+// when store is not nil, the prompt templates. They are swapped in atomically,
+// so requests already in flight finish on the providers they started with.
+// Models disabled and alias weights set by an administrator are kept.
+func (ts *TranslatorService) Reload(cfg *config.Config, store *prompts.Store) {
+	httpClient := newHTTPClient(cfg)
+	translators := buildTranslators(cfg, httpClient)
+
+	ts.mu.Lock()
+	previous := ts.httpClient
+	ts.config = cfg
+	ts.httpClient = httpClient
+	ts.translators = translators
+	ts.tenantTranslators = make(map[string]tenantTranslators)
+	if store != nil {
+		ts.prompts = store
+	}
+	ts.mu.Unlock()
+
+	// Requests in flight keep their connections; idle ones are no longer needed
+	previous.CloseIdleConnections()
+}
+
+// Config returns the configuration currently in use
+func (ts *TranslatorService) Config() *config.Config {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+	return ts.config
+}
+
+// buildTranslators creates the translation providers for all supported models,
+// all calling their APIs through client
+func buildTranslators(cfg *config.Config, client *http.Client) map[string]models.Translator {
+	translators := make(map[string]models.Translator)
+
 	// Register real translators if API keys are configured
-	if ts.config.HasOpenAIKey() {
-		openaiTranslator := NewOpenAITranslator(ts.config.GetOpenAIKey(), ts.config.GetOpenAIEndpoint())
+	if cfg.HasOpenAIKey() {
+		openaiTranslator := NewOpenAITranslator(cfg.GetOpenAIKey(), cfg.GetOpenAIEndpoint(), client)
 
 		// Support for Qwen models (Alibaba Cloud) - using OpenAI-compatible API
-		if strings.HasPrefix(ts.config.GetOpenAIEndpoint(), "https://idealab.alibaba-inc.com") {
-			ts.translators["Qwen3-Coder-Plus"] = openaiTranslator
-			ts.translators["qwen-max-latest"] = openaiTranslator
-			ts.translators["qwen-plus"] = openaiTranslator
-			ts.translators["qwen2.5-max"] = openaiTranslator
-			ts.translators["qwen2.5-plus"] = openaiTranslator
+		if strings.HasPrefix(cfg.GetOpenAIEndpoint(), "https://idealab.alibaba-inc.com") {
+			translators["Qwen3-Coder-Plus"] = openaiTranslator
+			translators["qwen-max-latest"] = openaiTranslator
+			translators["qwen-plus"] = openaiTranslator
+			translators["qwen2.5-max"] = openaiTranslator
+			translators["qwen2.5-plus"] = openaiTranslator
 		} else {
-			ts.translators["gpt-3.5-turbo"] = openaiTranslator
-			ts.translators["gpt-3.5"] = openaiTranslator
-			ts.translators["gpt-4"] = openaiTranslator
-			ts.translators["gpt-4-turbo"] = openaiTranslator
-			ts.translators["gpt-4o"] = openaiTranslator
+			translators["gpt-3.5-turbo"] = openaiTranslator
+			translators["gpt-3.5"] = openaiTranslator
+			translators["gpt-4"] = openaiTranslator
+			translators["gpt-4-turbo"] = openaiTranslator
+			translators["gpt-4o"] = openaiTranslator
 		}
 	} else {
 		// Register mock translators for OpenAI models when no API key is present
-		ts.translators["gpt-3.5-turbo"] = NewMockTranslator("GPT-3.5 Turbo")
-		ts.translators["gpt-3.5"] = NewMockTranslator("GPT-3.5")
-		ts.translators["gpt-4"] = NewMockTranslator("GPT-4")
-		ts.translators["gpt-4-turbo"] = NewMockTranslator("GPT-4 Turbo")
-		ts.translators["gpt-4o"] = NewMockTranslator("GPT-4O")
+		translators["gpt-3.5-turbo"] = NewMockTranslator("GPT-3.5 Turbo")
+		translators["gpt-3.5"] = NewMockTranslator("GPT-3.5")
+		translators["gpt-4"] = NewMockTranslator("GPT-4")
+		translators["gpt-4-turbo"] = NewMockTranslator("GPT-4 Turbo")
+		translators["gpt-4o"] = NewMockTranslator("GPT-4O")
 		// Also register mock translators for Qwen models
-		ts.translators["Qwen3-Coder-Plus"] = NewMockTranslator("Qwen3 Coder Plus")
-		ts.translators["qwen-max-latest"] = NewMockTranslator("Qwen Max Latest")
-		ts.translators["qwen-plus"] = NewMockTranslator("Qwen Plus")
-		ts.translators["qwen2.5-max"] = NewMockTranslator("Qwen 2.5 Max")
-		ts.translators["qwen2.5-plus"] = NewMockTranslator("Qwen 2.5 Plus")
-	}
-
-	if ts.config.HasAnthropicKey() {
-		anthropicTranslator := NewAnthropicTranslator(ts.config.GetAnthropicKey(), ts.config.GetAnthropicEndpoint())
-		ts.translators["claude-3-opus"] = anthropicTranslator
-		ts.translators["claude-3-sonnet"] = anthropicTranslator
-		ts.translators["claude-3-haiku"] = anthropicTranslator
-		ts.translators["claude-3-opus-20240229"] = anthropicTranslator
-		ts.translators["claude-3-sonnet-20240229"] = anthropicTranslator
-		ts.translators["claude-3-haiku-20240307"] = anthropicTranslator
-		ts.translators["claude"] = anthropicTranslator
+		translators["Qwen3-Coder-Plus"] = NewMockTranslator("Qwen3 Coder Plus")
+		translators["qwen-max-latest"] = NewMockTranslator("Qwen Max Latest")
+		translators["qwen-plus"] = NewMockTranslator("Qwen Plus")
+		translators["qwen2.5-max"] = NewMockTranslator("Qwen 2.5 Max")
+		translators["qwen2.5-plus"] = NewMockTranslator("Qwen 2.5 Plus")
+	}
+
+	if cfg.HasAnthropicKey() {
+		anthropicTranslator := NewAnthropicTranslator(cfg.GetAnthropicKey(), cfg.GetAnthropicEndpoint(), client)
+		translators["claude-3-opus"] = anthropicTranslator
+		translators["claude-3-sonnet"] = anthropicTranslator
+		translators["claude-3-haiku"] = anthropicTranslator
+		translators["claude-3-opus-20240229"] = anthropicTranslator
+		translators["claude-3-sonnet-20240229"] = anthropicTranslator
+		translators["claude-3-haiku-20240307"] = anthropicTranslator
+		translators["claude"] = anthropicTranslator
 	} else {
 		// Register mock translators for Anthropic models when no API key is present
-		ts.translators["claude-3-opus"] = NewMockTranslator("Claude 3 Opus")
-		ts.translators["claude-3-sonnet"] = NewMockTranslator("Claude 3 Sonnet")
-		ts.translators["claude-3-haiku"] = NewMockTranslator("Claude 3 Haiku")
-		ts.translators["claude-3-opus-20240229"] = NewMockTranslator("Claude 3 Opus (2024-02-29)")
-		ts.translators["claude-3-sonnet-20240229"] = NewMockTranslator("Claude 3 Sonnet (2024-02-29)")
-		ts.translators["claude-3-haiku-20240307"] = NewMockTranslator("Claude 3 Haiku (2024-03-07)")
-		ts.translators["claude"] = NewMockTranslator("Claude")
+		translators["claude-3-opus"] = NewMockTranslator("Claude 3 Opus")
+		translators["claude-3-sonnet"] = NewMockTranslator("Claude 3 Sonnet")
+		translators["claude-3-haiku"] = NewMockTranslator("Claude 3 Haiku")
+		translators["claude-3-opus-20240229"] = NewMockTranslator("Claude 3 Opus (2024-02-29)")
+		translators["claude-3-sonnet-20240229"] = NewMockTranslator("Claude 3 Sonnet (2024-02-29)")
+		translators["claude-3-haiku-20240307"] = NewMockTranslator("Claude 3 Haiku (2024-03-07)")
+		translators["claude"] = NewMockTranslator("Claude")
+	}
+
+	// Azure OpenAI deployments are registered after OpenAI so they take precedence
+	if cfg.HasAzureKey() {
+		azureTranslator := NewAzureOpenAITranslator(cfg.AzureKey, cfg.AzureEndpoint, cfg.AzureAPIVersion, cfg.AzureDeployments, client)
+		for model := range cfg.AzureDeployments {
+			translators[model] = azureTranslator
+		}
+	}
+
+	if cfg.HasGeminiKey() {
+		geminiTranslator := NewGeminiTranslator(cfg.GeminiKey, cfg.GeminiEndpoint, cfg.GeminiModels, client)
+		for _, model := range cfg.GeminiModels {
+			translators[model] = geminiTranslator
+		}
+	}
+
+	// Classical machine translation engines for short strings where an LLM is overkill
+	if cfg.HasDeepLKey() {
+		translators["deepl"] = NewDeepLTranslator(cfg.DeepLKey, cfg.DeepLEndpoint, client)
+	}
+	if cfg.HasLibreTranslateEndpoint() {
+		translators["libretranslate"] = NewLibreTranslateTranslator(cfg.LibreTranslateKey, cfg.LibreTranslateEndpoint, client)
+	}
+
+	// Llama uses a local Ollama or llama.cpp server when configured
+	if cfg.HasLocalEndpoint() {
+		if cfg.GetLocalProvider() == "llamacpp" {
+			translators["llama"] = NewLlamaCppTranslator(cfg.LocalEndpoint, client)
+		} else {
+			ollamaTranslator := NewOllamaTranslator(cfg.LocalEndpoint, cfg.LocalModel, client)
+			translators["llama"] = ollamaTranslator
+			if cfg.LocalModel != "" && cfg.LocalModel != "llama" {
+				translators[cfg.LocalModel] = ollamaTranslator
+			}
+		}
+	} else {
+		translators["llama"] = NewMockTranslator("Llama")
 	}
 
-	// Llama is always a mock translator (open source model)
-	ts.translators["llama"] = NewMockTranslator("Llama")
+	return translators
+}
+
+// SetPromptStore replaces the prompt templates available for translation
+func (ts *TranslatorService) SetPromptStore(store *prompts.Store) {
+	ts.mu.Lock()
+	ts.prompts = store
+	ts.mu.Unlock()
+}
+
+// PromptStore returns the prompt templates available for translation
+func (ts *TranslatorService) PromptStore() *prompts.Store {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+	return ts.prompts
+}
+
+// SetHistoryStore replaces the store used to persist successful translations
+func (ts *TranslatorService) SetHistoryStore(store storage.HistoryStore) {
+	ts.history = store
+}
+
+// HistoryStore returns the store used to persist successful translations
+func (ts *TranslatorService) HistoryStore() storage.HistoryStore {
+	return ts.history
+}
+
+// SetVoteStore replaces the store used to persist comparisons and their votes
+func (ts *TranslatorService) SetVoteStore(store storage.VoteStore) {
+	ts.votes = store
+}
+
+// VoteStore returns the store used to persist comparisons and their votes
+func (ts *TranslatorService) VoteStore() storage.VoteStore {
+	return ts.votes
 }
 
 // Translate translates text using the specified model with retry logic
 func (ts *TranslatorService) Translate(ctx context.Context, req *models.TranslationRequest) (*models.TranslationResponse, error) {
+	// The provider calls have no timeout of their own, so callers without a
+	// deadline get the configured one
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, ts.Config().GetTimeout())
+		defer cancel()
+	}
+
+	// Work on a copy so the caller's request is left untouched
+	prepared := *req
+
+	start := time.Now()
+	response, err := ts.translate(ctx, &prepared)
+	model := prepared.Model
+	if !ts.IsModelSupported(model) {
+		model = unknownModel
+	}
+	ts.monitor.RecordRequest(model, TenantFromContext(ctx), time.Since(start), err)
+	return response, err
+}
+
+// translate translates a request the caller no longer needs, filling in its
+// defaults along the way
+func (ts *TranslatorService) translate(ctx context.Context, req *models.TranslationRequest) (*models.TranslationResponse, error) {
+	// Translations in a session carry its earlier turns and terms, and default to its model
+	tenant := TenantFromContext(ctx)
+	if req.SessionID != "" {
+		history, sessionModel, ok := ts.sessions.History(tenant, req.SessionID)
+		if !ok {
+			return nil, fmt.Errorf("validation error: %w", &ValidationError{"Unknown or expired session: " + req.SessionID})
+		}
+		req.SessionHistory = history
+		if req.Model == "" {
+			req.Model = sessionModel
+		}
+	}
+
+	// Requests that leave the model or target language unset use the tenant's defaults
+	if settings := requestTenant(ctx); settings != nil {
+		if req.Model == "" {
+			req.Model = settings.DefaultModel
+		}
+		if req.TargetLanguage == "" {
+			req.TargetLanguage = settings.TargetLanguage
+		}
+	}
+
 	// Validate input
 	if err := ts.validationService.ValidateTextInput(req.Text); err != nil {
 		return nil, fmt.Errorf("validation error: %w", err)
 	}
 
+	// Aliases route each request to one of their models by weight, and auto
+	// to the model chosen by the routing policy
+	alias, err := ts.resolveAlias(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if ts.modelDisabled(req.Model) {
+		return nil, fmt.Errorf("model disabled: %s", req.Model)
+	}
+
 	if err := ts.validationService.ValidateModelInput(req.Model, ts.GetSupportedModels()); err != nil {
 		return nil, fmt.Errorf("validation error: %w", err)
 	}
 
+	if err := ts.validationService.ValidateGenerationOptions(req); err != nil {
+		return nil, fmt.Errorf("validation error: %w", err)
+	}
+
+	if err := ts.validationService.ValidateContext(req.Context); err != nil {
+		return nil, fmt.Errorf("validation error: %w", err)
+	}
+
+	if err := ts.validationService.ValidateTargetLanguage(req.TargetLanguage); err != nil {
+		return nil, fmt.Errorf("validation error: %w", err)
+	}
+
 	// Find the appropriate translator
-	translator, exists := ts.translators[req.Model]
+	translator, exists := ts.translator(ctx, req.Model)
 	if !exists {
 		return nil, fmt.Errorf("unsupported model: %s", req.Model)
 	}
 
-	// Perform translation with retry logic
+	if err := ts.validateVerification(ctx, req); err != nil {
+		return nil, err
+	}
+
+	// Filter the text before it can reach a provider. Everything stored below
+	// keeps the filtered text; only the response is restored.
+	original := req.Text
+	filtered, err := ts.filterContent(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Render the prompt template
+	rendered, err := ts.renderPrompt(req)
+	if err != nil {
+		return nil, err
+	}
+	req.RenderedPrompt = rendered
+
+	// Reuse a human-approved translation of an identical request instead of calling the model
+	start := time.Now()
+	response := ts.approvedTranslation(ctx, req)
+	if response == nil {
+		response, err = ts.translateShared(ctx, translator, req)
+		if err != nil {
+			return nil, err
+		}
+
+		ts.submitForReview(ctx, req, response)
+	}
+
+	if ts.qualityEnabled(req) {
+		response.Quality = ts.estimateQuality(ctx, req, response)
+	}
+	if req.Verify == VerifyBackTranslation {
+		response.Verification = ts.verifyBackTranslation(ctx, req, response)
+	}
+
+	ts.recordHistory(ctx, req, response, start)
+	if req.SessionID != "" {
+		ts.sessions.Record(tenant, req.SessionID, models.SessionTurn{Original: req.Text, Translation: response.Translation})
+	}
+
+	if alias != "" {
+		response.Alias = alias
+		// Mocks answer with a display name, so name the model the alias chose
+		if !ts.IsModelSupported(response.Model) {
+			response.Model = req.Model
+		}
+	}
+
+	restoreContent(response, original, filtered)
+	return response, nil
+}
+
+// translateWithRetry calls a translator, retrying transient errors
+func (ts *TranslatorService) translateWithRetry(ctx context.Context, translator models.Translator, req *models.TranslationRequest) (*models.TranslationResponse, error) {
+	// The tenant's restrictions apply to every call, whichever model it goes to
+	if err := ts.authorizeProvider(ctx, req.Model, translator); err != nil {
+		return nil, err
+	}
+
 	var response *models.TranslationResponse
 	var err error
 
 	// Retry up to 3 times for transient errors
 	for attempt := 0; attempt < 3; attempt++ {
-		response, err = translator.Translate(ctx, req)
+		response, err = ts.callProvider(ctx, translator, req)
 		if err == nil {
 			// Success
 			return response, nil
@@ -144,25 +417,94 @@ func (ts *TranslatorService) Translate(ctx context.Context, req *models.Translat
 		}
 	}
 
+	log.Printf("Translation failed after retries with %s: %v", req.Model, err)
+	return nil, fmt.Errorf("failed to translate with %s after retries: %w", req.Model, err)
+}
+
+// renderPrompt renders the prompt template selected by the request, falling back
+// to the template configured for the model and then to the default template
+func (ts *TranslatorService) renderPrompt(req *models.TranslationRequest) (*models.RenderedPrompt, error) {
+	ts.mu.RLock()
+	store, cfg := ts.prompts, ts.config
+	ts.mu.RUnlock()
+
+	if req.Prompt != "" {
+		rendered, err := renderPrompt(store, req.Prompt, req)
+		if err != nil {
+			return nil, fmt.Errorf("validation error: %w", err)
+		}
+		return rendered, nil
+	}
+
+	ref := cfg.GetPromptTemplate(req.Model)
+	rendered, err := renderPrompt(store, ref, req)
 	if err != nil {
-		log.Printf("Translation failed after retries with %s: %v", req.Model, err)
-		return nil, fmt.Errorf("failed to translate with %s after retries: %w", req.Model, err)
+		return nil, fmt.Errorf("failed to render prompt for %s: %w", req.Model, err)
 	}
+	return rendered, nil
+}
 
-	return response, nil
+// recordHistory persists a successful translation, logging any storage failure
+func (ts *TranslatorService) recordHistory(ctx context.Context, req *models.TranslationRequest, response *models.TranslationResponse, start time.Time) {
+	if ts.history == nil {
+		return
+	}
+
+	entry := &models.HistoryEntry{
+		Original:    response.Original,
+		Translation: response.Translation,
+		Model:       req.Model,
+		Client:      ClientFromContext(ctx),
+		Tenant:      TenantFromContext(ctx),
+		LatencyMs:   time.Since(start).Milliseconds(),
+		CreatedAt:   time.Now().UTC(),
+	}
+
+	// Use a fresh context so a cancelled request does not lose its history entry
+	if err := ts.history.Save(context.WithoutCancel(ctx), entry); err != nil {
+		log.Printf("Failed to record translation history: %v", err)
+	}
+}
+
+// translator returns the translator registered for a model, using the
+// tenant's own provider keys when it has any
+func (ts *TranslatorService) translator(ctx context.Context, model string) (models.Translator, bool) {
+	translator, exists := ts.translatorsFor(ctx)[model]
+	return translator, exists
 }
 
-// GetSupportedModels returns a list of supported models
+// GetSupportedModels returns a list of supported models and aliases, and auto
+// when any model is available, leaving out the models disabled by an administrator
 func (ts *TranslatorService) GetSupportedModels() []string {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+
 	models := make([]string, 0, len(ts.translators))
 	for model := range ts.translators {
-		models = append(models, model)
+		if !ts.disabledModels[model] {
+			models = append(models, model)
+		}
+	}
+	for alias := range ts.config.ModelAliases {
+		if _, isModel := ts.translators[alias]; !isModel {
+			models = append(models, alias)
+		}
+	}
+	for alias := range ts.aliasWeights {
+		if _, inConfig := ts.config.ModelAliases[alias]; !inConfig {
+			models = append(models, alias)
+		}
+	}
+	if len(ts.translators) > len(ts.disabledModels) {
+		models = append(models, config.AutoModel)
 	}
 	return models
 }
 
-// IsModelSupported checks if a model is supported
+// IsModelSupported checks if a model or alias, or auto, is supported
 func (ts *TranslatorService) IsModelSupported(model string) bool {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
 	_, exists := ts.translators[model]
-	return exists
+	return exists || ts.weightsLocked(model) != nil || model == config.AutoModel
 }
@@ -119,6 +119,28 @@ func TestTranslatorService_Translate(t *testing.T) {
 	}
 }
 
+func TestTranslatorService_TranslateDeadline(t *testing.T) {
+	ts := NewTranslatorService(&config.Config{ServerPort: "8080", Timeout: 1})
+
+	// A provider that never answers
+	ts.translators["test-model"] = &MockTranslatorForTesting{
+		name: "test-model",
+		translateFunc: func(ctx context.Context, req *models.TranslationRequest) (*models.TranslationResponse, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	}
+
+	// Requests without a deadline give up after the configured timeout
+	start := time.Now()
+	if _, err := ts.Translate(context.Background(), &models.TranslationRequest{Text: "Save", Model: "test-model"}); err == nil {
+		t.Fatalf("Expected the translation to time out")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected the configured timeout to apply, took %v", elapsed)
+	}
+}
+
 func TestTranslatorService_GetSupportedModels(t *testing.T) {
 	cfg := &config.Config{
 		ServerPort: "8080",
@@ -224,3 +246,59 @@ func TestTranslatorService_RetryLogic(t *testing.T) {
 		t.Errorf("Expected 3 calls, got %d", callCount)
 	}
 }
+
+func TestTranslatorService_Reload(t *testing.T) {
+	cfg := &config.Config{
+		ServerPort: "8080",
+		Timeout:    30,
+	}
+
+	ts := NewTranslatorService(cfg)
+
+	// Start a slow translation on the current provider
+	started := make(chan struct{})
+	release := make(chan struct{})
+	ts.translators["test-model"] = &MockTranslatorForTesting{
+		name: "test-model",
+		translateFunc: func(ctx context.Context, req *models.TranslationRequest) (*models.TranslationResponse, error) {
+			close(started)
+			<-release
+			return &models.TranslationResponse{Original: req.Text, Translation: "old provider", Model: "test-model"}, nil
+		},
+	}
+
+	result := make(chan *models.TranslationResponse, 1)
+	go func() {
+		response, err := ts.Translate(context.Background(), &models.TranslationRequest{Text: "Hello", Model: "test-model"})
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		result <- response
+	}()
+	<-started
+
+	// Reload with a configuration that registers real OpenAI providers
+	reloaded := &config.Config{
+		ServerPort:     "8080",
+		Timeout:        30,
+		OpenAIKey:      "new-key",
+		OpenAIEndpoint: "https://api.openai.com/v1",
+	}
+	ts.Reload(reloaded, nil)
+	close(release)
+
+	// The in-flight request finishes on the provider it started with
+	if response := <-result; response == nil || response.Translation != "old provider" {
+		t.Errorf("Expected in-flight request to finish on the old provider")
+	}
+
+	if ts.Config() != reloaded {
+		t.Errorf("Expected reloaded configuration to be in use")
+	}
+	if ts.IsModelSupported("test-model") {
+		t.Errorf("Expected test-model to be removed by reload")
+	}
+	if translator, _ := ts.translator(context.Background(), "gpt-4"); translator.Name() != "OpenAI" {
+		t.Errorf("Expected gpt-4 to use the OpenAI provider after reload, got %s", translator.Name())
+	}
+}
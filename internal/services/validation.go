@@ -1,9 +1,12 @@
 package services
 
 import (
+	"fmt"
 	"regexp"
 	"strings"
 	"unicode"
+
+	"translator-service/internal/models"
 )
 
 // ValidationService provides input validation for the translation service
@@ -66,6 +69,142 @@ func (vs *ValidationService) ValidateModelInput(model string, supportedModels []
 	return &ValidationError{"Unsupported model: " + model}
 }
 
+// ValidateTargetLanguage checks that a target language is a supported Chinese script
+func (vs *ValidationService) ValidateTargetLanguage(language string) error {
+	switch language {
+	case "", models.LanguageSimplifiedChinese, models.LanguageTraditionalChinese:
+		return nil
+	}
+	return &ValidationError{"Target language must be one of: zh-Hans, zh-Hant"}
+}
+
+// ValidateFormality validates the optional formality preference
+func (vs *ValidationService) ValidateFormality(formality string) error {
+	switch formality {
+	case "", "formal", "informal":
+		return nil
+	}
+	return &ValidationError{"Formality must be one of: formal, informal"}
+}
+
+// GenerationLimits holds the generation parameter bounds a model accepts.
+// A zero MaxTokens means the model does not take generation parameters.
+type GenerationLimits struct {
+	MaxTemperature float64
+	MaxTokens      int
+}
+
+// modelLimits lists generation limits by model name prefix; the first match wins
+var modelLimits = []struct {
+	prefix string
+	limits GenerationLimits
+}{
+	{"deepl", GenerationLimits{}},
+	{"libretranslate", GenerationLimits{}},
+	{"claude", GenerationLimits{MaxTemperature: 1, MaxTokens: 4096}},
+	{"gpt-4o", GenerationLimits{MaxTemperature: 2, MaxTokens: 4096}},
+	{"gpt-4-turbo", GenerationLimits{MaxTemperature: 2, MaxTokens: 4096}},
+	{"gpt-4", GenerationLimits{MaxTemperature: 2, MaxTokens: 8192}},
+	{"gpt-3.5", GenerationLimits{MaxTemperature: 2, MaxTokens: 4096}},
+	{"qwen", GenerationLimits{MaxTemperature: 2, MaxTokens: 8192}},
+	{"gemini", GenerationLimits{MaxTemperature: 2, MaxTokens: 8192}},
+}
+
+// defaultLimits applies to models without an entry in modelLimits, such as local models
+var defaultLimits = GenerationLimits{MaxTemperature: 2, MaxTokens: 4096}
+
+// LimitsForModel returns the generation parameter bounds for a model
+func (vs *ValidationService) LimitsForModel(model string) GenerationLimits {
+	lower := strings.ToLower(model)
+	for _, entry := range modelLimits {
+		if strings.HasPrefix(lower, entry.prefix) {
+			return entry.limits
+		}
+	}
+	return defaultLimits
+}
+
+// ValidateGenerationOptions validates the optional generation parameters and
+// prompt options of a request against the bounds of the requested model
+func (vs *ValidationService) ValidateGenerationOptions(req *models.TranslationRequest) error {
+	limits := vs.LimitsForModel(req.Model)
+
+	if req.Temperature != nil || req.MaxTokens != 0 {
+		if limits.MaxTokens == 0 {
+			return &ValidationError{"Model " + req.Model + " does not support temperature or max_tokens"}
+		}
+	}
+	if req.Temperature != nil && (*req.Temperature < 0 || *req.Temperature > limits.MaxTemperature) {
+		return &ValidationError{fmt.Sprintf("Temperature must be between 0 and %g for model %s", limits.MaxTemperature, req.Model)}
+	}
+	if req.MaxTokens < 0 || req.MaxTokens > limits.MaxTokens {
+		return &ValidationError{fmt.Sprintf("max_tokens must be between 1 and %d for model %s", limits.MaxTokens, req.Model)}
+	}
+
+	if err := vs.ValidateFormality(req.Formality); err != nil {
+		return err
+	}
+
+	// Tone is inserted into the prompt, so keep it to a short single line
+	if len(req.Tone) > 50 || strings.ContainsAny(req.Tone, "\r\n") || vs.containsInvalidCharacters(req.Tone) {
+		return &ValidationError{"Tone must be a single line of at most 50 characters"}
+	}
+
+	switch req.Domain {
+	case "", "legal", "medical", "marketing", "ui":
+	default:
+		return &ValidationError{"Domain must be one of: legal, medical, marketing, ui"}
+	}
+
+	if len(req.Instructions) > 1000 {
+		return &ValidationError{"Instructions are too long (maximum 1000 characters)"}
+	}
+	if vs.containsInvalidCharacters(req.Instructions) {
+		return &ValidationError{"Instructions contain invalid characters"}
+	}
+
+	switch req.Verify {
+	case "", VerifyBackTranslation:
+	default:
+		return &ValidationError{"Verify must be one of: " + VerifyBackTranslation}
+	}
+
+	return nil
+}
+
+// maxContextSegments is the number of preceding or following segments a request may carry
+const maxContextSegments = 5
+
+// ValidateContext validates the optional context of a request
+func (vs *ValidationService) ValidateContext(tc *models.TranslationContext) error {
+	if tc == nil {
+		return nil
+	}
+
+	if len(tc.Preceding) > maxContextSegments || len(tc.Following) > maxContextSegments {
+		return &ValidationError{fmt.Sprintf("Context may include at most %d preceding and %d following segments", maxContextSegments, maxContextSegments)}
+	}
+	for _, segment := range append(append([]string{}, tc.Preceding...), tc.Following...) {
+		if len(segment) > 1000 || vs.containsInvalidCharacters(segment) {
+			return &ValidationError{"Context segments must be at most 1000 characters of valid text"}
+		}
+	}
+
+	if len(tc.Screen) > 100 || strings.ContainsAny(tc.Screen, "\r\n") || vs.containsInvalidCharacters(tc.Screen) {
+		return &ValidationError{"Screen name must be a single line of at most 100 characters"}
+	}
+
+	if len(tc.Notes) > 1000 || vs.containsInvalidCharacters(tc.Notes) {
+		return &ValidationError{"Notes must be at most 1000 characters of valid text"}
+	}
+
+	if tc.MaxLength < 0 {
+		return &ValidationError{"max_length cannot be negative"}
+	}
+
+	return nil
+}
+
 // containsEnglishCharacters checks if the text contains English letters
 func (vs *ValidationService) containsEnglishCharacters(text string) bool {
 	// Remove excessive whitespace
@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"unicode"
+
+	"translator-service/internal/models"
+	"translator-service/internal/prompts"
+)
+
+// VerifyBackTranslation translates the result back to English and compares it with the original
+const VerifyBackTranslation = "back_translation"
+
+// backTranslationModel returns the model used to translate a request's result
+// back to English: the configured one, or the request's own model
+func (ts *TranslatorService) backTranslationModel(req *models.TranslationRequest) string {
+	if model := ts.Config().BackTranslationModel; model != "" {
+		return model
+	}
+	return req.Model
+}
+
+// validateVerification checks that the verification selected by a request can
+// run before any translation is attempted
+func (ts *TranslatorService) validateVerification(ctx context.Context, req *models.TranslationRequest) error {
+	if req.Verify == "" {
+		return nil
+	}
+
+	model := ts.backTranslationModel(req)
+	if _, exists := ts.translator(ctx, model); !exists {
+		return fmt.Errorf("unsupported model: %s", model)
+	}
+	// Machine translation engines only translate from English to Chinese
+	if ts.validationService.LimitsForModel(model).MaxTokens == 0 {
+		return fmt.Errorf("validation error: %w", &ValidationError{"Model " + model + " cannot translate back to English; configure llm.quality.back_translation_model"})
+	}
+	return nil
+}
+
+// verifyBackTranslation translates a result back to English and scores its
+// similarity to the original text. A failed back-translation is reported in
+// the verification rather than failing the translation it checks.
+func (ts *TranslatorService) verifyBackTranslation(ctx context.Context, req *models.TranslationRequest, response *models.TranslationResponse) *models.Verification {
+	model := ts.backTranslationModel(req)
+	verification := &models.Verification{Method: VerifyBackTranslation, Model: model}
+
+	backTranslation, err := ts.backTranslate(ctx, model, response.Translation)
+	if err != nil {
+		log.Printf("Back-translation with %s failed: %v", model, err)
+		verification.Error = err.Error()
+		return verification
+	}
+
+	verification.BackTranslation = backTranslation
+	verification.Similarity = math.Round(textSimilarity(req.Text, backTranslation)*100) / 100
+	return verification
+}
+
+// backTranslate translates Chinese text to English with the back-translate prompt
+func (ts *TranslatorService) backTranslate(ctx context.Context, model, text string) (string, error) {
+	translator, exists := ts.translator(ctx, model)
+	if !exists {
+		return "", fmt.Errorf("unsupported model: %s", model)
+	}
+
+	ts.mu.RLock()
+	store := ts.prompts
+	ts.mu.RUnlock()
+
+	temperature := 0.0
+	req := &models.TranslationRequest{
+		Text:        text,
+		Model:       model,
+		Temperature: &temperature,
+	}
+	rendered, err := renderPrompt(store, prompts.BackTranslateName, req)
+	if err != nil {
+		return "", err
+	}
+	rendered.Unframed = true
+	req.RenderedPrompt = rendered
+
+	response, err := ts.translateWithRetry(ctx, translator, req)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(response.Translation), nil
+}
+
+// textSimilarity scores the similarity of two English texts from 0 to 1 as
+// the mean of the F1 overlap of their words and of their word pairs. Word
+// pairs reward a back-translation that keeps the original word order.
+func textSimilarity(a, b string) float64 {
+	wordsA, wordsB := similarityWords(a), similarityWords(b)
+	if len(wordsA) == 0 || len(wordsB) == 0 {
+		if len(wordsA) == len(wordsB) {
+			return 1
+		}
+		return 0
+	}
+
+	unigrams := overlapF1(wordsA, wordsB)
+	if len(wordsA) < 2 || len(wordsB) < 2 {
+		return unigrams
+	}
+	return (unigrams + overlapF1(wordPairs(wordsA), wordPairs(wordsB))) / 2
+}
+
+// similarityWords splits a text into lowercase words, ignoring punctuation
+func similarityWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r) && r != '\''
+	})
+}
+
+// wordPairs returns the adjacent word pairs of a list of words
+func wordPairs(words []string) []string {
+	pairs := make([]string, 0, len(words)-1)
+	for i := 1; i < len(words); i++ {
+		pairs = append(pairs, words[i-1]+" "+words[i])
+	}
+	return pairs
+}
+
+// overlapF1 returns the F1 score of the items two lists have in common,
+// counting repeated items as often as they occur in both
+func overlapF1(a, b []string) float64 {
+	counts := make(map[string]int, len(a))
+	for _, item := range a {
+		counts[item]++
+	}
+
+	common := 0
+	for _, item := range b {
+		if counts[item] > 0 {
+			counts[item]--
+			common++
+		}
+	}
+	if common == 0 {
+		return 0
+	}
+
+	precision := float64(common) / float64(len(b))
+	recall := float64(common) / float64(len(a))
+	return 2 * precision * recall / (precision + recall)
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"translator-service/internal/config"
+	"translator-service/internal/models"
+)
+
+func TestTextSimilarity(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		min  float64
+		max  float64
+	}{
+		{"Identical", "Save your changes.", "save your changes", 1, 1},
+		{"Reworded", "Save your changes before closing.", "Save the changes before you close.", 0.3, 0.7},
+		{"Unrelated", "Save your changes.", "The weather is nice today.", 0, 0},
+		{"Single word", "Open", "Open", 1, 1},
+		{"Empty", "Open", "", 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if score := textSimilarity(tt.a, tt.b); score < tt.min || score > tt.max {
+				t.Errorf("Expected similarity between %v and %v, got %v", tt.min, tt.max, score)
+			}
+		})
+	}
+}
+
+func TestTranslatorService_VerifyBackTranslation(t *testing.T) {
+	cfg := &config.Config{ServerPort: "8080", Timeout: 30}
+	ts := NewTranslatorService(cfg)
+
+	// Create a translator that translates into Chinese and records back-translations
+	var backRequest *models.TranslationRequest
+	ts.translators["gpt-4"] = &MockTranslatorForTesting{
+		name: "Round Trip",
+		translateFunc: func(ctx context.Context, req *models.TranslationRequest) (*models.TranslationResponse, error) {
+			if req.RenderedPrompt.Version == "back-translate@v1" {
+				backRequest = req
+				return &models.TranslationResponse{Original: req.Text, Translation: "Save your changes.", Model: req.Model}, nil
+			}
+			return &models.TranslationResponse{Original: req.Text, Translation: "保存您的更改。", Model: req.Model}, nil
+		},
+	}
+	ts.translators["deepl"] = &MockTranslatorForTesting{
+		name: "Machine Translation",
+		translateFunc: func(ctx context.Context, req *models.TranslationRequest) (*models.TranslationResponse, error) {
+			return &models.TranslationResponse{Original: req.Text, Translation: "保存更改。", Model: req.Model}, nil
+		},
+	}
+
+	// The request's model translates the result back by default
+	response, err := ts.Translate(context.Background(), &models.TranslationRequest{Text: "Save your changes.", Model: "gpt-4", Verify: "back_translation"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	verification := response.Verification
+	if verification == nil || verification.Model != "gpt-4" || verification.BackTranslation != "Save your changes." || verification.Similarity != 1 {
+		t.Errorf("Unexpected verification: %+v", verification)
+	}
+	if backRequest == nil || backRequest.Text != "保存您的更改。" {
+		t.Errorf("Expected the translation to be translated back, got %+v", backRequest)
+	}
+
+	// Machine translation engines cannot translate back to English
+	_, err = ts.Translate(context.Background(), &models.TranslationRequest{Text: "Save your changes.", Model: "deepl", Verify: "back_translation"})
+	if err == nil || !strings.Contains(err.Error(), "validation error") {
+		t.Errorf("Expected validation error, got %v", err)
+	}
+
+	// A configured back-translation model verifies their results
+	reloaded := *cfg
+	reloaded.BackTranslationModel = "gpt-4"
+	ts.Reload(&reloaded, nil)
+	ts.translators["deepl"] = &MockTranslatorForTesting{
+		name: "Machine Translation",
+		translateFunc: func(ctx context.Context, req *models.TranslationRequest) (*models.TranslationResponse, error) {
+			return &models.TranslationResponse{Original: req.Text, Translation: "保存更改。", Model: req.Model}, nil
+		},
+	}
+	ts.translators["gpt-4"] = &MockTranslatorForTesting{
+		name: "Failing Back-Translator",
+		translateFunc: func(ctx context.Context, req *models.TranslationRequest) (*models.TranslationResponse, error) {
+			return nil, errors.New("model overloaded")
+		},
+	}
+
+	// A failed back-translation is reported without failing the translation
+	response, err = ts.Translate(context.Background(), &models.TranslationRequest{Text: "Save your changes.", Model: "deepl", Verify: "back_translation"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response.Translation != "保存更改。" || response.Verification.Model != "gpt-4" || response.Verification.Error == "" {
+		t.Errorf("Expected the translation with a failed verification, got %+v", response.Verification)
+	}
+
+	// Unknown verification methods are rejected
+	_, err = ts.Translate(context.Background(), &models.TranslationRequest{Text: "Save your changes.", Model: "gpt-4", Verify: "round_trip"})
+	if err == nil || !strings.Contains(err.Error(), "validation error") {
+		t.Errorf("Expected validation error, got %v", err)
+	}
+}
@@ -0,0 +1,56 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"translator-service/internal/models"
+)
+
+const (
+	// DefaultPageSize is the page size used when a query does not specify one
+	DefaultPageSize = 20
+
+	// MaxPageSize is the largest page size a query may request
+	MaxPageSize = 100
+)
+
+// HistoryStore persists completed translations and allows searching them
+type HistoryStore interface {
+	// Save persists a translation entry, assigning its ID
+	Save(ctx context.Context, entry *models.HistoryEntry) error
+
+	// Search returns the entries matching the query, newest first
+	Search(ctx context.Context, query models.HistoryQuery) (*models.HistoryPage, error)
+
+	// Close releases any resources held by the store
+	Close() error
+}
+
+// NewHistoryStore creates a history store of the given kind ("memory" or "sqlite")
+func NewHistoryStore(kind, path string) (HistoryStore, error) {
+	switch kind {
+	case "", "memory":
+		return NewMemoryHistoryStore(0), nil
+	case "sqlite":
+		return NewSQLiteHistoryStore(path)
+	default:
+		return nil, fmt.Errorf("unknown history store: %s", kind)
+	}
+}
+
+// normalizeQuery applies default and maximum pagination values to a query
+func normalizeQuery(query models.HistoryQuery) models.HistoryQuery {
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.PageSize < 1 {
+		query.PageSize = DefaultPageSize
+	}
+	if query.PageSize > MaxPageSize {
+		query.PageSize = MaxPageSize
+	}
+	query.Search = strings.TrimSpace(query.Search)
+	return query
+}
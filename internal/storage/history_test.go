@@ -0,0 +1,210 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"translator-service/internal/models"
+)
+
+func seedHistory(t *testing.T, store HistoryStore) time.Time {
+	t.Helper()
+
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	entries := []models.HistoryEntry{
+		{Original: "Hello, world!", Translation: "你好，世界！", Model: "gpt-4", Client: "10.0.0.1", LatencyMs: 120, CreatedAt: base},
+		{Original: "Good morning", Translation: "早上好", Model: "claude-3-opus", Client: "10.0.0.2", LatencyMs: 340, CreatedAt: base.Add(time.Hour)},
+		{Original: "Hello again", Translation: "又见面了", Model: "gpt-4", Client: "10.0.0.1", LatencyMs: 90, CreatedAt: base.AddDate(0, 0, 1)},
+	}
+
+	for i := range entries {
+		if err := store.Save(context.Background(), &entries[i]); err != nil {
+			t.Fatalf("Failed to save history entry: %v", err)
+		}
+		if entries[i].ID == 0 {
+			t.Errorf("Expected Save to assign an ID")
+		}
+	}
+
+	return base
+}
+
+func testHistoryStore(t *testing.T, store HistoryStore) {
+	base := seedHistory(t, store)
+
+	tests := []struct {
+		name          string
+		query         models.HistoryQuery
+		expectedTotal int
+		expectedFirst string
+	}{
+		{
+			name:          "All entries newest first",
+			query:         models.HistoryQuery{},
+			expectedTotal: 3,
+			expectedFirst: "Hello again",
+		},
+		{
+			name:          "Filter by model",
+			query:         models.HistoryQuery{Model: "claude-3-opus"},
+			expectedTotal: 1,
+			expectedFirst: "Good morning",
+		},
+		{
+			name:          "Filter by date range",
+			query:         models.HistoryQuery{From: base, To: base.AddDate(0, 0, 1)},
+			expectedTotal: 2,
+			expectedFirst: "Good morning",
+		},
+		{
+			name:          "Search original text",
+			query:         models.HistoryQuery{Search: "hello"},
+			expectedTotal: 2,
+			expectedFirst: "Hello again",
+		},
+		{
+			name:          "Search translated text",
+			query:         models.HistoryQuery{Search: "早上"},
+			expectedTotal: 1,
+			expectedFirst: "Good morning",
+		},
+		{
+			name:          "Second page",
+			query:         models.HistoryQuery{Page: 2, PageSize: 2},
+			expectedTotal: 3,
+			expectedFirst: "Hello, world!",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := store.Search(context.Background(), tt.query)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if page.Total != tt.expectedTotal {
+				t.Errorf("Expected total %d, got %d", tt.expectedTotal, page.Total)
+			}
+			if len(page.Entries) == 0 {
+				t.Fatalf("Expected at least one entry")
+			}
+			if page.Entries[0].Original != tt.expectedFirst {
+				t.Errorf("Expected first entry %q, got %q", tt.expectedFirst, page.Entries[0].Original)
+			}
+		})
+	}
+}
+
+func testHistoryStoreTenants(t *testing.T, store HistoryStore) {
+	seedHistory(t, store)
+
+	entry := models.HistoryEntry{Original: "Quarterly report", Translation: "季度报告", Model: "gpt-4", Tenant: "acme", CreatedAt: time.Now()}
+	if err := store.Save(context.Background(), &entry); err != nil {
+		t.Fatalf("Failed to save history entry: %v", err)
+	}
+
+	page, err := store.Search(context.Background(), models.HistoryQuery{Tenant: "acme"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if page.Total != 1 || page.Entries[0].Tenant != "acme" {
+		t.Errorf("Expected only the tenant's entry, got %+v", page)
+	}
+
+	// Entries without a tenant do not include other tenants' entries
+	page, err = store.Search(context.Background(), models.HistoryQuery{Search: "report"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if page.Total != 0 {
+		t.Errorf("Expected tenant entry to be hidden, got %d entries", page.Total)
+	}
+}
+
+func TestMemoryHistoryStore(t *testing.T) {
+	testHistoryStore(t, NewMemoryHistoryStore(0))
+}
+
+func TestMemoryHistoryStore_Tenants(t *testing.T) {
+	testHistoryStoreTenants(t, NewMemoryHistoryStore(0))
+}
+
+func TestMemoryHistoryStore_Limit(t *testing.T) {
+	store := NewMemoryHistoryStore(2)
+	seedHistory(t, store)
+
+	page, err := store.Search(context.Background(), models.HistoryQuery{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if page.Total != 2 {
+		t.Errorf("Expected oldest entry to be evicted, got %d entries", page.Total)
+	}
+}
+
+func TestSQLiteHistoryStore(t *testing.T) {
+	store, err := NewSQLiteHistoryStore(filepath.Join(t.TempDir(), "history.db"))
+	if err != nil {
+		t.Fatalf("Failed to create SQLite store: %v", err)
+	}
+	defer store.Close()
+
+	testHistoryStore(t, store)
+}
+
+func TestSQLiteHistoryStore_Tenants(t *testing.T) {
+	store, err := NewSQLiteHistoryStore(filepath.Join(t.TempDir(), "history.db"))
+	if err != nil {
+		t.Fatalf("Failed to create SQLite store: %v", err)
+	}
+	defer store.Close()
+
+	testHistoryStoreTenants(t, store)
+}
+
+func TestSQLiteHistoryStore_AddsTenantColumn(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.db")
+
+	// A database created before tenants were introduced
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	_, err = db.Exec(`CREATE TABLE translation_history (
+		id          INTEGER PRIMARY KEY AUTOINCREMENT,
+		original    TEXT    NOT NULL,
+		translation TEXT    NOT NULL,
+		model       TEXT    NOT NULL,
+		client      TEXT    NOT NULL DEFAULT '',
+		latency_ms  INTEGER NOT NULL DEFAULT 0,
+		created_at  INTEGER NOT NULL
+	);
+	INSERT INTO translation_history (original, translation, model, created_at) VALUES ('Hello', '你好', 'gpt-4', 1);`)
+	db.Close()
+	if err != nil {
+		t.Fatalf("Failed to create old schema: %v", err)
+	}
+
+	store, err := NewSQLiteHistoryStore(path)
+	if err != nil {
+		t.Fatalf("Failed to open old database: %v", err)
+	}
+	defer store.Close()
+
+	page, err := store.Search(context.Background(), models.HistoryQuery{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if page.Total != 1 || page.Entries[0].Original != "Hello" {
+		t.Errorf("Expected existing entry without a tenant, got %+v", page)
+	}
+}
+
+func TestNewHistoryStore_Unknown(t *testing.T) {
+	if _, err := NewHistoryStore("redis", ""); err == nil {
+		t.Errorf("Expected error for unknown history store")
+	}
+}
@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"translator-service/internal/models"
+)
+
+// defaultMemoryHistoryLimit is the number of entries kept by an in-memory store
+const defaultMemoryHistoryLimit = 10000
+
+// MemoryHistoryStore keeps translation history in memory, evicting the oldest entries
+type MemoryHistoryStore struct {
+	mu      sync.RWMutex
+	entries []models.HistoryEntry
+	limit   int
+	nextID  int64
+}
+
+// NewMemoryHistoryStore creates a new in-memory history store holding at most limit entries
+func NewMemoryHistoryStore(limit int) *MemoryHistoryStore {
+	if limit <= 0 {
+		limit = defaultMemoryHistoryLimit
+	}
+	return &MemoryHistoryStore{limit: limit}
+}
+
+// Save stores a translation entry
+func (s *MemoryHistoryStore) Save(ctx context.Context, entry *models.HistoryEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextID++
+	entry.ID = s.nextID
+	s.entries = append(s.entries, *entry)
+
+	// Evict the oldest entries once the limit is exceeded
+	if len(s.entries) > s.limit {
+		s.entries = append([]models.HistoryEntry(nil), s.entries[len(s.entries)-s.limit:]...)
+	}
+
+	return nil
+}
+
+// Search returns the entries matching the query, newest first
+func (s *MemoryHistoryStore) Search(ctx context.Context, query models.HistoryQuery) (*models.HistoryPage, error) {
+	query = normalizeQuery(query)
+	search := strings.ToLower(query.Search)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	matches := make([]models.HistoryEntry, 0)
+	for i := len(s.entries) - 1; i >= 0; i-- {
+		entry := s.entries[i]
+		if entry.Tenant != query.Tenant {
+			continue
+		}
+		if query.Model != "" && entry.Model != query.Model {
+			continue
+		}
+		if !query.From.IsZero() && entry.CreatedAt.Before(query.From) {
+			continue
+		}
+		if !query.To.IsZero() && !entry.CreatedAt.Before(query.To) {
+			continue
+		}
+		if search != "" &&
+			!strings.Contains(strings.ToLower(entry.Original), search) &&
+			!strings.Contains(strings.ToLower(entry.Translation), search) {
+			continue
+		}
+		matches = append(matches, entry)
+	}
+
+	page := &models.HistoryPage{
+		Entries:  []models.HistoryEntry{},
+		Total:    len(matches),
+		Page:     query.Page,
+		PageSize: query.PageSize,
+	}
+
+	start := (query.Page - 1) * query.PageSize
+	if start < len(matches) {
+		end := start + query.PageSize
+		if end > len(matches) {
+			end = len(matches)
+		}
+		page.Entries = matches[start:end]
+	}
+
+	return page, nil
+}
+
+// Close is a no-op for the in-memory store
+func (s *MemoryHistoryStore) Close() error {
+	return nil
+}
@@ -0,0 +1,162 @@
+package storage
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"translator-service/internal/models"
+)
+
+// MemoryReviewStore keeps review items and their audit trail in memory
+type MemoryReviewStore struct {
+	mu          sync.RWMutex
+	items       map[int64]*models.ReviewItem
+	events      map[int64][]models.ReviewEvent
+	nextItemID  int64
+	nextEventID int64
+}
+
+// NewMemoryReviewStore creates a new in-memory review store
+func NewMemoryReviewStore() *MemoryReviewStore {
+	return &MemoryReviewStore{
+		items:  make(map[int64]*models.ReviewItem),
+		events: make(map[int64][]models.ReviewEvent),
+	}
+}
+
+// CreateReview stores a new item and its first event
+func (s *MemoryReviewStore) CreateReview(ctx context.Context, item *models.ReviewItem, event *models.ReviewEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextItemID++
+	item.ID = s.nextItemID
+	stored := *item
+	stored.Events = nil
+	s.items[item.ID] = &stored
+
+	s.appendEventLocked(item.ID, event)
+	return nil
+}
+
+// UpdateReview stores an item's new state and the event that changed it
+func (s *MemoryReviewStore) UpdateReview(ctx context.Context, item *models.ReviewItem, event *models.ReviewEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.items[item.ID]
+	if !ok {
+		return ErrReviewNotFound
+	}
+	stored.Translation = item.Translation
+	stored.State = item.State
+	stored.Reviewer = item.Reviewer
+	stored.UpdatedAt = item.UpdatedAt
+
+	s.appendEventLocked(item.ID, event)
+	return nil
+}
+
+// GetReview returns an item with its events
+func (s *MemoryReviewStore) GetReview(ctx context.Context, id int64) (*models.ReviewItem, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	stored, ok := s.items[id]
+	if !ok {
+		return nil, ErrReviewNotFound
+	}
+	item := *stored
+	item.Events = append([]models.ReviewEvent(nil), s.events[id]...)
+	return &item, nil
+}
+
+// FindReview returns the most recently updated item with the key in one of the states
+func (s *MemoryReviewStore) FindReview(ctx context.Context, key string, states ...string) (*models.ReviewItem, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var found *models.ReviewItem
+	for _, stored := range s.items {
+		if stored.Key != key || !containsState(states, stored.State) {
+			continue
+		}
+		if found == nil || newerReview(stored, found) {
+			found = stored
+		}
+	}
+	if found == nil {
+		return nil, nil
+	}
+	item := *found
+	return &item, nil
+}
+
+// ListReviews returns the items matching the query, most recently updated first
+func (s *MemoryReviewStore) ListReviews(ctx context.Context, query models.ReviewQuery) (*models.ReviewPage, error) {
+	query = normalizeReviewQuery(query)
+
+	s.mu.RLock()
+	matches := make([]models.ReviewItem, 0)
+	for _, stored := range s.items {
+		if stored.Tenant != query.Tenant || query.State != "" && stored.State != query.State {
+			continue
+		}
+		matches = append(matches, *stored)
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(matches, func(i, j int) bool {
+		return newerReview(&matches[i], &matches[j])
+	})
+
+	page := &models.ReviewPage{
+		Items:    []models.ReviewItem{},
+		Total:    len(matches),
+		Page:     query.Page,
+		PageSize: query.PageSize,
+	}
+
+	start := (query.Page - 1) * query.PageSize
+	if start < len(matches) {
+		end := start + query.PageSize
+		if end > len(matches) {
+			end = len(matches)
+		}
+		page.Items = matches[start:end]
+	}
+
+	return page, nil
+}
+
+// Close is a no-op for the in-memory store
+func (s *MemoryReviewStore) Close() error {
+	return nil
+}
+
+// appendEventLocked records an event for an item, assigning its ID
+func (s *MemoryReviewStore) appendEventLocked(itemID int64, event *models.ReviewEvent) {
+	s.nextEventID++
+	event.ID = s.nextEventID
+	event.ItemID = itemID
+	s.events[itemID] = append(s.events[itemID], *event)
+}
+
+// newerReview reports whether item a was updated after item b, breaking ties by ID
+func newerReview(a, b *models.ReviewItem) bool {
+	if !a.UpdatedAt.Equal(b.UpdatedAt) {
+		return a.UpdatedAt.After(b.UpdatedAt)
+	}
+	return a.ID > b.ID
+}
+
+// containsState reports whether state is one of states
+func containsState(states []string, state string) bool {
+	for _, s := range states {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"translator-service/internal/models"
+)
+
+// MemoryTenantStore keeps tenants in memory
+type MemoryTenantStore struct {
+	mu      sync.RWMutex
+	tenants map[string]models.Tenant
+}
+
+// NewMemoryTenantStore creates a new in-memory tenant store
+func NewMemoryTenantStore() *MemoryTenantStore {
+	return &MemoryTenantStore{tenants: make(map[string]models.Tenant)}
+}
+
+// CreateTenant stores a new tenant
+func (s *MemoryTenantStore) CreateTenant(ctx context.Context, tenant *models.Tenant) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.tenants[tenant.ID]; ok {
+		return ErrTenantExists
+	}
+	s.tenants[tenant.ID] = copyTenant(tenant)
+	return nil
+}
+
+// UpdateTenant stores a tenant's new settings
+func (s *MemoryTenantStore) UpdateTenant(ctx context.Context, tenant *models.Tenant) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.tenants[tenant.ID]; !ok {
+		return ErrTenantNotFound
+	}
+	s.tenants[tenant.ID] = copyTenant(tenant)
+	return nil
+}
+
+// GetTenant returns a tenant by ID
+func (s *MemoryTenantStore) GetTenant(ctx context.Context, id string) (*models.Tenant, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	stored, ok := s.tenants[id]
+	if !ok {
+		return nil, ErrTenantNotFound
+	}
+	tenant := copyTenant(&stored)
+	return &tenant, nil
+}
+
+// FindTenantByAPIKey returns the tenant with the given API key hash
+func (s *MemoryTenantStore) FindTenantByAPIKey(ctx context.Context, hash string) (*models.Tenant, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, stored := range s.tenants {
+		for _, storedHash := range stored.APIKeyHashes {
+			if storedHash == hash {
+				tenant := copyTenant(&stored)
+				return &tenant, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// ListTenants returns all tenants ordered by ID
+func (s *MemoryTenantStore) ListTenants(ctx context.Context) ([]models.Tenant, error) {
+	s.mu.RLock()
+	tenants := make([]models.Tenant, 0, len(s.tenants))
+	for _, stored := range s.tenants {
+		tenants = append(tenants, copyTenant(&stored))
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(tenants, func(i, j int) bool {
+		return tenants[i].ID < tenants[j].ID
+	})
+	return tenants, nil
+}
+
+// Close is a no-op for the in-memory store
+func (s *MemoryTenantStore) Close() error {
+	return nil
+}
+
+// copyTenant copies a tenant so callers cannot modify the stored slices
+func copyTenant(tenant *models.Tenant) models.Tenant {
+	copied := *tenant
+	copied.APIKeyHashes = append([]string(nil), tenant.APIKeyHashes...)
+	copied.AllowedProviders = append([]string(nil), tenant.AllowedProviders...)
+	copied.AllowedEndpoints = append([]string(nil), tenant.AllowedEndpoints...)
+	return copied
+}
@@ -0,0 +1,86 @@
+package storage
+
+import (
+	"context"
+	"sync"
+
+	"translator-service/internal/models"
+)
+
+// MemoryVoteStore keeps comparisons and their votes in memory
+type MemoryVoteStore struct {
+	mu          sync.RWMutex
+	comparisons map[string]models.Comparison
+	votes       []models.Vote
+	nextID      int64
+}
+
+// NewMemoryVoteStore creates a new in-memory vote store
+func NewMemoryVoteStore() *MemoryVoteStore {
+	return &MemoryVoteStore{comparisons: make(map[string]models.Comparison)}
+}
+
+// SaveComparison stores a comparison
+func (s *MemoryVoteStore) SaveComparison(ctx context.Context, comparison *models.Comparison) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := *comparison
+	stored.Results = append([]models.ComparisonResult(nil), comparison.Results...)
+	s.comparisons[comparison.ID] = stored
+
+	return nil
+}
+
+// GetComparison returns a tenant's comparison
+func (s *MemoryVoteStore) GetComparison(ctx context.Context, tenant, id string) (*models.Comparison, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	comparison, exists := s.comparisons[id]
+	if !exists || comparison.Tenant != tenant {
+		return nil, ErrComparisonNotFound
+	}
+	comparison.Results = append([]models.ComparisonResult(nil), comparison.Results...)
+
+	return &comparison, nil
+}
+
+// SaveVote stores a vote unless the voter has already voted on the comparison
+func (s *MemoryVoteStore) SaveVote(ctx context.Context, vote *models.Vote) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.votes {
+		if existing.ComparisonID == vote.ComparisonID && existing.Tenant == vote.Tenant && existing.Voter == vote.Voter {
+			return ErrDuplicateVote
+		}
+	}
+
+	s.nextID++
+	vote.ID = s.nextID
+	s.votes = append(s.votes, *vote)
+
+	return nil
+}
+
+// Tally returns the number of votes each model has received from a tenant
+func (s *MemoryVoteStore) Tally(ctx context.Context, tenant string) (map[string]int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tally := make(map[string]int)
+	for _, vote := range s.votes {
+		if vote.Tenant != tenant {
+			continue
+		}
+		tally[vote.Model]++
+	}
+
+	return tally, nil
+}
+
+// Close is a no-op for the in-memory store
+func (s *MemoryVoteStore) Close() error {
+	return nil
+}
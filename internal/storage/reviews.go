@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"translator-service/internal/models"
+)
+
+// ErrReviewNotFound is returned when a review item does not exist
+var ErrReviewNotFound = errors.New("review item not found")
+
+// ReviewStore persists translations under human review and their audit trail
+type ReviewStore interface {
+	// CreateReview persists a new item together with its first event, assigning their IDs
+	CreateReview(ctx context.Context, item *models.ReviewItem, event *models.ReviewEvent) error
+
+	// UpdateReview persists an item's new state and translation together with
+	// the event that changed them
+	UpdateReview(ctx context.Context, item *models.ReviewItem, event *models.ReviewEvent) error
+
+	// GetReview returns an item with its events, oldest first
+	GetReview(ctx context.Context, id int64) (*models.ReviewItem, error)
+
+	// FindReview returns the most recently updated item with the given key in
+	// one of the given states, or nil if there is none. Keys include the tenant.
+	FindReview(ctx context.Context, key string, states ...string) (*models.ReviewItem, error)
+
+	// ListReviews returns the items matching the query, most recently updated first
+	ListReviews(ctx context.Context, query models.ReviewQuery) (*models.ReviewPage, error)
+
+	// Close releases any resources held by the store
+	Close() error
+}
+
+// NewReviewStore creates a review store of the given kind ("memory" or "sqlite")
+func NewReviewStore(kind, path string) (ReviewStore, error) {
+	switch kind {
+	case "", "memory":
+		return NewMemoryReviewStore(), nil
+	case "sqlite":
+		return NewSQLiteReviewStore(path)
+	default:
+		return nil, fmt.Errorf("unknown review store: %s", kind)
+	}
+}
+
+// normalizeReviewQuery applies default and maximum pagination values to a query
+func normalizeReviewQuery(query models.ReviewQuery) models.ReviewQuery {
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.PageSize < 1 {
+		query.PageSize = DefaultPageSize
+	}
+	if query.PageSize > MaxPageSize {
+		query.PageSize = MaxPageSize
+	}
+	return query
+}
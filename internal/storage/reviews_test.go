@@ -0,0 +1,128 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"translator-service/internal/models"
+)
+
+func testReviewStore(t *testing.T, store ReviewStore) {
+	ctx := context.Background()
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// Create two items for the same request and one for another
+	items := []models.ReviewItem{
+		{Key: "k1", Original: "Save", MachineTranslation: "保存", Translation: "保存", Model: "gpt-4", State: models.ReviewMachine},
+		{Key: "k1", Original: "Save", MachineTranslation: "存储", Translation: "存储", Model: "claude", State: models.ReviewMachine},
+		{Key: "k2", Original: "Open", MachineTranslation: "打开", Translation: "打开", Model: "gpt-4", State: models.ReviewMachine},
+	}
+	for i := range items {
+		items[i].CreatedAt = base.Add(time.Duration(i) * time.Minute)
+		items[i].UpdatedAt = items[i].CreatedAt
+		event := &models.ReviewEvent{Reviewer: "system", Action: models.ReviewActionCreate, ToState: models.ReviewMachine, CreatedAt: items[i].CreatedAt}
+		if err := store.CreateReview(ctx, &items[i], event); err != nil {
+			t.Fatalf("Failed to create review item: %v", err)
+		}
+		if items[i].ID == 0 || event.ID == 0 {
+			t.Fatalf("Expected IDs to be assigned, got item %d and event %d", items[i].ID, event.ID)
+		}
+	}
+
+	// Approve the first item with an edit
+	approved := items[0]
+	approved.Translation = "保存更改"
+	approved.State = models.ReviewApproved
+	approved.Reviewer = "alice"
+	approved.UpdatedAt = base.Add(time.Hour)
+	event := &models.ReviewEvent{Reviewer: "alice", Action: models.ReviewActionApprove, FromState: models.ReviewMachine,
+		ToState: models.ReviewApproved, Translation: "保存更改", CreatedAt: approved.UpdatedAt}
+	if err := store.UpdateReview(ctx, &approved, event); err != nil {
+		t.Fatalf("Failed to update review item: %v", err)
+	}
+
+	// The item carries its audit trail, oldest first
+	item, err := store.GetReview(ctx, items[0].ID)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if item.State != models.ReviewApproved || item.Translation != "保存更改" || item.MachineTranslation != "保存" || item.Key != "k1" {
+		t.Errorf("Unexpected review item: %+v", item)
+	}
+	if len(item.Events) != 2 || item.Events[0].Action != models.ReviewActionCreate || item.Events[1].Reviewer != "alice" {
+		t.Errorf("Unexpected audit trail: %+v", item.Events)
+	}
+
+	// Lookups match both the key and the state
+	found, err := store.FindReview(ctx, "k1", models.ReviewApproved)
+	if err != nil || found == nil || found.ID != items[0].ID {
+		t.Errorf("Expected the approved item, got %+v (%v)", found, err)
+	}
+	found, err = store.FindReview(ctx, "k1", models.ReviewMachine, models.ReviewInReview)
+	if err != nil || found == nil || found.ID != items[1].ID {
+		t.Errorf("Expected the open item, got %+v (%v)", found, err)
+	}
+	if found, err := store.FindReview(ctx, "k2", models.ReviewApproved); err != nil || found != nil {
+		t.Errorf("Expected no approved item, got %+v (%v)", found, err)
+	}
+
+	// Listing filters by state, most recently updated first
+	page, err := store.ListReviews(ctx, models.ReviewQuery{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if page.Total != 3 || page.Items[0].ID != items[0].ID || page.Items[1].ID != items[2].ID {
+		t.Errorf("Unexpected review page: %+v", page)
+	}
+	page, err = store.ListReviews(ctx, models.ReviewQuery{State: models.ReviewMachine, PageSize: 1, Page: 2})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if page.Total != 2 || len(page.Items) != 1 || page.Items[0].ID != items[1].ID {
+		t.Errorf("Unexpected filtered review page: %+v", page)
+	}
+
+	// Missing items are reported as such
+	if _, err := store.GetReview(ctx, 999); !errors.Is(err, ErrReviewNotFound) {
+		t.Errorf("Expected ErrReviewNotFound, got %v", err)
+	}
+	missing := models.ReviewItem{ID: 999}
+	if err := store.UpdateReview(ctx, &missing, &models.ReviewEvent{}); !errors.Is(err, ErrReviewNotFound) {
+		t.Errorf("Expected ErrReviewNotFound on update, got %v", err)
+	}
+
+	// Listing is scoped to a tenant
+	tenantItem := models.ReviewItem{Key: "k3", Tenant: "acme", Original: "Close", MachineTranslation: "关闭", Translation: "关闭",
+		Model: "gpt-4", State: models.ReviewMachine, CreatedAt: base, UpdatedAt: base}
+	if err := store.CreateReview(ctx, &tenantItem, &models.ReviewEvent{Reviewer: "system", Action: models.ReviewActionCreate,
+		ToState: models.ReviewMachine, CreatedAt: base}); err != nil {
+		t.Fatalf("Failed to create review item: %v", err)
+	}
+	page, err = store.ListReviews(ctx, models.ReviewQuery{Tenant: "acme"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if page.Total != 1 || page.Items[0].Tenant != "acme" {
+		t.Errorf("Expected only the tenant's item, got %+v", page)
+	}
+	if page, err := store.ListReviews(ctx, models.ReviewQuery{}); err != nil || page.Total != 3 {
+		t.Errorf("Expected tenant item to be hidden without a tenant, got %+v (%v)", page, err)
+	}
+}
+
+func TestMemoryReviewStore(t *testing.T) {
+	testReviewStore(t, NewMemoryReviewStore())
+}
+
+func TestSQLiteReviewStore(t *testing.T) {
+	store, err := NewSQLiteReviewStore(filepath.Join(t.TempDir(), "translator.db"))
+	if err != nil {
+		t.Fatalf("Failed to create SQLite review store: %v", err)
+	}
+	defer store.Close()
+
+	testReviewStore(t, store)
+}
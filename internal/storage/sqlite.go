@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// sqliteColumn is a column added to a table after it was first created. The
+// schema declares it for new databases; openSQLite adds it to existing ones.
+type sqliteColumn struct {
+	table      string
+	name       string
+	definition string
+}
+
+// openSQLite opens a SQLite database file, applies the given schema and adds
+// any missing columns. Several stores may share one file, so writers wait for
+// the lock instead of failing.
+func openSQLite(path, schema string, columns ...sqliteColumn) (*sql.DB, error) {
+	if path == "" {
+		return nil, fmt.Errorf("sqlite store requires a database path")
+	}
+
+	db, err := sql.Open("sqlite3", path+"?_busy_timeout=5000&_journal_mode=WAL")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	// SQLite only supports a single writer at a time
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize database: %w", err)
+	}
+
+	for _, column := range columns {
+		if err := addColumn(db, column); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("failed to migrate database: %w", err)
+		}
+	}
+
+	return db, nil
+}
+
+// addColumn adds a column to a table unless the table already has it
+func addColumn(db *sql.DB, column sqliteColumn) error {
+	rows, err := db.Query("SELECT name FROM pragma_table_info(?)", column.table)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return err
+		}
+		if name == column.name {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	rows.Close()
+
+	_, err = db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", column.table, column.name, column.definition))
+	return err
+}
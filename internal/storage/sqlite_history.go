@@ -0,0 +1,132 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"translator-service/internal/models"
+)
+
+const historySchema = `
+CREATE TABLE IF NOT EXISTS translation_history (
+	id          INTEGER PRIMARY KEY AUTOINCREMENT,
+	original    TEXT    NOT NULL,
+	translation TEXT    NOT NULL,
+	model       TEXT    NOT NULL,
+	client      TEXT    NOT NULL DEFAULT '',
+	tenant      TEXT    NOT NULL DEFAULT '',
+	latency_ms  INTEGER NOT NULL DEFAULT 0,
+	created_at  INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_translation_history_created_at ON translation_history (created_at);
+CREATE INDEX IF NOT EXISTS idx_translation_history_model ON translation_history (model);
+`
+
+// SQLiteHistoryStore persists translation history in a SQLite database file
+type SQLiteHistoryStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteHistoryStore opens (or creates) a SQLite history database at the given path
+func NewSQLiteHistoryStore(path string) (*SQLiteHistoryStore, error) {
+	db, err := openSQLite(path, historySchema, sqliteColumn{"translation_history", "tenant", "TEXT NOT NULL DEFAULT ''"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history database: %w", err)
+	}
+
+	return &SQLiteHistoryStore{db: db}, nil
+}
+
+// Save stores a translation entry
+func (s *SQLiteHistoryStore) Save(ctx context.Context, entry *models.HistoryEntry) error {
+	result, err := s.db.ExecContext(ctx,
+		`INSERT INTO translation_history (original, translation, model, client, tenant, latency_ms, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		entry.Original, entry.Translation, entry.Model, entry.Client, entry.Tenant, entry.LatencyMs, entry.CreatedAt.UnixNano())
+	if err != nil {
+		return fmt.Errorf("failed to save history entry: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to read history entry id: %w", err)
+	}
+	entry.ID = id
+
+	return nil
+}
+
+// Search returns the entries matching the query, newest first
+func (s *SQLiteHistoryStore) Search(ctx context.Context, query models.HistoryQuery) (*models.HistoryPage, error) {
+	query = normalizeQuery(query)
+
+	// Build the WHERE clause from the query filters; entries are always scoped to a tenant
+	conditions := []string{"tenant = ?"}
+	args := []interface{}{query.Tenant}
+	if query.Model != "" {
+		conditions = append(conditions, "model = ?")
+		args = append(args, query.Model)
+	}
+	if !query.From.IsZero() {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, query.From.UnixNano())
+	}
+	if !query.To.IsZero() {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, query.To.UnixNano())
+	}
+	if query.Search != "" {
+		pattern := "%" + escapeLike(query.Search) + "%"
+		conditions = append(conditions, `(original LIKE ? ESCAPE '\' OR translation LIKE ? ESCAPE '\')`)
+		args = append(args, pattern, pattern)
+	}
+
+	where := " WHERE " + strings.Join(conditions, " AND ")
+
+	page := &models.HistoryPage{
+		Entries:  []models.HistoryEntry{},
+		Page:     query.Page,
+		PageSize: query.PageSize,
+	}
+
+	if err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM translation_history"+where, args...).Scan(&page.Total); err != nil {
+		return nil, fmt.Errorf("failed to count history entries: %w", err)
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT id, original, translation, model, client, tenant, latency_ms, created_at FROM translation_history"+where+
+			" ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?",
+		append(args, query.PageSize, (query.Page-1)*query.PageSize)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query history entries: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry models.HistoryEntry
+		var createdAt int64
+		if err := rows.Scan(&entry.ID, &entry.Original, &entry.Translation, &entry.Model, &entry.Client, &entry.Tenant, &entry.LatencyMs, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan history entry: %w", err)
+		}
+		entry.CreatedAt = time.Unix(0, createdAt).UTC()
+		page.Entries = append(page.Entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history entries: %w", err)
+	}
+
+	return page, nil
+}
+
+// Close closes the underlying database
+func (s *SQLiteHistoryStore) Close() error {
+	return s.db.Close()
+}
+
+// escapeLike escapes the LIKE wildcard characters in a search term
+func escapeLike(term string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return replacer.Replace(term)
+}
@@ -0,0 +1,257 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"translator-service/internal/models"
+)
+
+const reviewSchema = `
+CREATE TABLE IF NOT EXISTS review_items (
+	id                  INTEGER PRIMARY KEY AUTOINCREMENT,
+	request_key         TEXT    NOT NULL,
+	tenant              TEXT    NOT NULL DEFAULT '',
+	original            TEXT    NOT NULL,
+	machine_translation TEXT    NOT NULL,
+	translation         TEXT    NOT NULL,
+	model               TEXT    NOT NULL,
+	state               TEXT    NOT NULL,
+	reviewer            TEXT    NOT NULL DEFAULT '',
+	created_at          INTEGER NOT NULL,
+	updated_at          INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_review_items_key_state ON review_items (request_key, state);
+CREATE INDEX IF NOT EXISTS idx_review_items_state_updated_at ON review_items (state, updated_at);
+
+CREATE TABLE IF NOT EXISTS review_events (
+	id          INTEGER PRIMARY KEY AUTOINCREMENT,
+	item_id     INTEGER NOT NULL REFERENCES review_items (id),
+	reviewer    TEXT    NOT NULL,
+	action      TEXT    NOT NULL,
+	from_state  TEXT    NOT NULL DEFAULT '',
+	to_state    TEXT    NOT NULL,
+	translation TEXT    NOT NULL DEFAULT '',
+	comment     TEXT    NOT NULL DEFAULT '',
+	created_at  INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_review_events_item_id ON review_events (item_id);
+`
+
+// reviewItemColumns lists the review_items columns read by scanReviewItem
+const reviewItemColumns = "id, request_key, tenant, original, machine_translation, translation, model, state, reviewer, created_at, updated_at"
+
+// SQLiteReviewStore persists review items and their audit trail in a SQLite database file
+type SQLiteReviewStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteReviewStore opens (or creates) a SQLite review database at the given path
+func NewSQLiteReviewStore(path string) (*SQLiteReviewStore, error) {
+	db, err := openSQLite(path, reviewSchema, sqliteColumn{"review_items", "tenant", "TEXT NOT NULL DEFAULT ''"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open review database: %w", err)
+	}
+
+	return &SQLiteReviewStore{db: db}, nil
+}
+
+// CreateReview stores a new item and its first event in one transaction
+func (s *SQLiteReviewStore) CreateReview(ctx context.Context, item *models.ReviewItem, event *models.ReviewEvent) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin review transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		`INSERT INTO review_items (request_key, tenant, original, machine_translation, translation, model, state, reviewer, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		item.Key, item.Tenant, item.Original, item.MachineTranslation, item.Translation, item.Model, item.State, item.Reviewer,
+		item.CreatedAt.UnixNano(), item.UpdatedAt.UnixNano())
+	if err != nil {
+		return fmt.Errorf("failed to save review item: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to read review item id: %w", err)
+	}
+	item.ID = id
+
+	if err := insertReviewEvent(ctx, tx, item.ID, event); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// UpdateReview stores an item's new state and the event that changed it in one transaction
+func (s *SQLiteReviewStore) UpdateReview(ctx context.Context, item *models.ReviewItem, event *models.ReviewEvent) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin review transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		`UPDATE review_items SET translation = ?, state = ?, reviewer = ?, updated_at = ? WHERE id = ?`,
+		item.Translation, item.State, item.Reviewer, item.UpdatedAt.UnixNano(), item.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update review item: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return ErrReviewNotFound
+	}
+
+	if err := insertReviewEvent(ctx, tx, item.ID, event); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetReview returns an item with its events
+func (s *SQLiteReviewStore) GetReview(ctx context.Context, id int64) (*models.ReviewItem, error) {
+	item, err := scanReviewItem(s.db.QueryRowContext(ctx, "SELECT "+reviewItemColumns+" FROM review_items WHERE id = ?", id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrReviewNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read review item: %w", err)
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, item_id, reviewer, action, from_state, to_state, translation, comment, created_at FROM review_events WHERE item_id = ? ORDER BY id`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query review events: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var event models.ReviewEvent
+		var createdAt int64
+		if err := rows.Scan(&event.ID, &event.ItemID, &event.Reviewer, &event.Action, &event.FromState, &event.ToState,
+			&event.Translation, &event.Comment, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan review event: %w", err)
+		}
+		event.CreatedAt = time.Unix(0, createdAt).UTC()
+		item.Events = append(item.Events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read review events: %w", err)
+	}
+
+	return item, nil
+}
+
+// FindReview returns the most recently updated item with the key in one of the states
+func (s *SQLiteReviewStore) FindReview(ctx context.Context, key string, states ...string) (*models.ReviewItem, error) {
+	if len(states) == 0 {
+		return nil, nil
+	}
+
+	args := []interface{}{key}
+	for _, state := range states {
+		args = append(args, state)
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(states)), ", ")
+
+	item, err := scanReviewItem(s.db.QueryRowContext(ctx,
+		"SELECT "+reviewItemColumns+" FROM review_items WHERE request_key = ? AND state IN ("+placeholders+") ORDER BY updated_at DESC, id DESC LIMIT 1",
+		args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find review item: %w", err)
+	}
+	return item, nil
+}
+
+// ListReviews returns the items matching the query, most recently updated first
+func (s *SQLiteReviewStore) ListReviews(ctx context.Context, query models.ReviewQuery) (*models.ReviewPage, error) {
+	query = normalizeReviewQuery(query)
+
+	// Items are always scoped to a tenant
+	where := " WHERE tenant = ?"
+	args := []interface{}{query.Tenant}
+	if query.State != "" {
+		where += " AND state = ?"
+		args = append(args, query.State)
+	}
+
+	page := &models.ReviewPage{
+		Items:    []models.ReviewItem{},
+		Page:     query.Page,
+		PageSize: query.PageSize,
+	}
+
+	if err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM review_items"+where, args...).Scan(&page.Total); err != nil {
+		return nil, fmt.Errorf("failed to count review items: %w", err)
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT "+reviewItemColumns+" FROM review_items"+where+" ORDER BY updated_at DESC, id DESC LIMIT ? OFFSET ?",
+		append(args, query.PageSize, (query.Page-1)*query.PageSize)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query review items: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanReviewItem(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan review item: %w", err)
+		}
+		page.Items = append(page.Items, *item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read review items: %w", err)
+	}
+
+	return page, nil
+}
+
+// Close closes the underlying database
+func (s *SQLiteReviewStore) Close() error {
+	return s.db.Close()
+}
+
+// rowScanner is implemented by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanReviewItem reads the reviewItemColumns of a row into a review item
+func scanReviewItem(row rowScanner) (*models.ReviewItem, error) {
+	var item models.ReviewItem
+	var createdAt, updatedAt int64
+	if err := row.Scan(&item.ID, &item.Key, &item.Tenant, &item.Original, &item.MachineTranslation, &item.Translation, &item.Model,
+		&item.State, &item.Reviewer, &createdAt, &updatedAt); err != nil {
+		return nil, err
+	}
+	item.CreatedAt = time.Unix(0, createdAt).UTC()
+	item.UpdatedAt = time.Unix(0, updatedAt).UTC()
+	return &item, nil
+}
+
+// insertReviewEvent records an event for an item, assigning its ID
+func insertReviewEvent(ctx context.Context, tx *sql.Tx, itemID int64, event *models.ReviewEvent) error {
+	result, err := tx.ExecContext(ctx,
+		`INSERT INTO review_events (item_id, reviewer, action, from_state, to_state, translation, comment, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		itemID, event.Reviewer, event.Action, event.FromState, event.ToState, event.Translation, event.Comment, event.CreatedAt.UnixNano())
+	if err != nil {
+		return fmt.Errorf("failed to save review event: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to read review event id: %w", err)
+	}
+	event.ID = id
+	event.ItemID = itemID
+
+	return nil
+}
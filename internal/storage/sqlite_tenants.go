@@ -0,0 +1,320 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"translator-service/internal/models"
+)
+
+// The key hashes and restrictions of a tenant are kept in tables of their own,
+// so that a key is found by an indexed exact match
+const tenantSchema = `
+CREATE TABLE IF NOT EXISTS tenants (
+	id                TEXT    PRIMARY KEY,
+	openai_key        TEXT    NOT NULL DEFAULT '',
+	anthropic_key     TEXT    NOT NULL DEFAULT '',
+	default_model     TEXT    NOT NULL DEFAULT '',
+	target_language   TEXT    NOT NULL DEFAULT '',
+	disabled          INTEGER NOT NULL DEFAULT 0,
+	created_at        INTEGER NOT NULL,
+	updated_at        INTEGER NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS tenant_api_keys (
+	hash      TEXT PRIMARY KEY,
+	tenant_id TEXT NOT NULL REFERENCES tenants(id),
+	position  INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_tenant_api_keys_tenant ON tenant_api_keys(tenant_id);
+
+CREATE TABLE IF NOT EXISTS tenant_allowed_providers (
+	tenant_id TEXT    NOT NULL REFERENCES tenants(id),
+	position  INTEGER NOT NULL,
+	provider  TEXT    NOT NULL,
+	PRIMARY KEY (tenant_id, position)
+);
+
+CREATE TABLE IF NOT EXISTS tenant_allowed_endpoints (
+	tenant_id TEXT    NOT NULL REFERENCES tenants(id),
+	position  INTEGER NOT NULL,
+	endpoint  TEXT    NOT NULL,
+	PRIMARY KEY (tenant_id, position)
+);
+`
+
+// tenantColumns lists the tenants columns read by scanTenant
+const tenantColumns = "id, openai_key, anthropic_key, default_model, target_language, disabled, created_at, updated_at"
+
+// tenantList is a table holding one of the lists of a tenant
+type tenantList struct {
+	table  string
+	column string
+	// legacyColumn is the comma-separated tenants column the list was kept in before
+	legacyColumn string
+	values       func(tenant *models.Tenant) *[]string
+}
+
+// tenantLists are the lists stored for each tenant
+var tenantLists = []tenantList{
+	{"tenant_api_keys", "hash", "api_key_hashes", func(t *models.Tenant) *[]string { return &t.APIKeyHashes }},
+	{"tenant_allowed_providers", "provider", "allowed_providers", func(t *models.Tenant) *[]string { return &t.AllowedProviders }},
+	{"tenant_allowed_endpoints", "endpoint", "allowed_endpoints", func(t *models.Tenant) *[]string { return &t.AllowedEndpoints }},
+}
+
+// sqlExecer runs statements on a database or within a transaction
+type sqlExecer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// SQLiteTenantStore persists tenants in a SQLite database file
+type SQLiteTenantStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteTenantStore opens (or creates) a SQLite tenant database at the given path
+func NewSQLiteTenantStore(path string) (*SQLiteTenantStore, error) {
+	db, err := openSQLite(path, tenantSchema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open tenant database: %w", err)
+	}
+	if err := migrateTenantLists(db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to migrate tenant database: %w", err)
+	}
+
+	return &SQLiteTenantStore{db: db}, nil
+}
+
+// CreateTenant stores a new tenant
+func (s *SQLiteTenantStore) CreateTenant(ctx context.Context, tenant *models.Tenant) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin tenant transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var exists int
+	if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM tenants WHERE id = ?`, tenant.ID).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to look up tenant: %w", err)
+	}
+	if exists > 0 {
+		return ErrTenantExists
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"INSERT INTO tenants ("+tenantColumns+") VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+		tenant.ID, tenant.OpenAIKey, tenant.AnthropicKey, tenant.DefaultModel, tenant.TargetLanguage,
+		tenant.Disabled, tenant.CreatedAt.UnixNano(), tenant.UpdatedAt.UnixNano())
+	if err != nil {
+		return fmt.Errorf("failed to save tenant: %w", err)
+	}
+	if err := writeTenantLists(ctx, tx, tenant); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// UpdateTenant stores a tenant's new settings
+func (s *SQLiteTenantStore) UpdateTenant(ctx context.Context, tenant *models.Tenant) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin tenant transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		`UPDATE tenants SET openai_key = ?, anthropic_key = ?, default_model = ?, target_language = ?, disabled = ?, updated_at = ? WHERE id = ?`,
+		tenant.OpenAIKey, tenant.AnthropicKey, tenant.DefaultModel, tenant.TargetLanguage, tenant.Disabled,
+		tenant.UpdatedAt.UnixNano(), tenant.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update tenant: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return ErrTenantNotFound
+	}
+	if err := writeTenantLists(ctx, tx, tenant); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetTenant returns a tenant by ID
+func (s *SQLiteTenantStore) GetTenant(ctx context.Context, id string) (*models.Tenant, error) {
+	tenant, err := scanTenant(s.db.QueryRowContext(ctx, "SELECT "+tenantColumns+" FROM tenants WHERE id = ?", id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrTenantNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tenant: %w", err)
+	}
+	if err := readTenantLists(ctx, s.db, tenant); err != nil {
+		return nil, err
+	}
+	return tenant, nil
+}
+
+// FindTenantByAPIKey returns the tenant with the given API key hash
+func (s *SQLiteTenantStore) FindTenantByAPIKey(ctx context.Context, hash string) (*models.Tenant, error) {
+	var id string
+	err := s.db.QueryRowContext(ctx, `SELECT tenant_id FROM tenant_api_keys WHERE hash = ?`, hash).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find tenant: %w", err)
+	}
+	return s.GetTenant(ctx, id)
+}
+
+// ListTenants returns all tenants ordered by ID
+func (s *SQLiteTenantStore) ListTenants(ctx context.Context) ([]models.Tenant, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT "+tenantColumns+" FROM tenants ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tenants: %w", err)
+	}
+	defer rows.Close()
+
+	tenants := []models.Tenant{}
+	for rows.Next() {
+		tenant, err := scanTenant(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan tenant: %w", err)
+		}
+		tenants = append(tenants, *tenant)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read tenants: %w", err)
+	}
+	rows.Close()
+
+	for i := range tenants {
+		if err := readTenantLists(ctx, s.db, &tenants[i]); err != nil {
+			return nil, err
+		}
+	}
+	return tenants, nil
+}
+
+// Close closes the underlying database
+func (s *SQLiteTenantStore) Close() error {
+	return s.db.Close()
+}
+
+// scanTenant reads the tenantColumns of a row into a tenant
+func scanTenant(row rowScanner) (*models.Tenant, error) {
+	var tenant models.Tenant
+	var createdAt, updatedAt int64
+	if err := row.Scan(&tenant.ID, &tenant.OpenAIKey, &tenant.AnthropicKey, &tenant.DefaultModel, &tenant.TargetLanguage,
+		&tenant.Disabled, &createdAt, &updatedAt); err != nil {
+		return nil, err
+	}
+	tenant.Source = models.TenantSourceAPI
+	tenant.CreatedAt = time.Unix(0, createdAt).UTC()
+	tenant.UpdatedAt = time.Unix(0, updatedAt).UTC()
+	return &tenant, nil
+}
+
+// readTenantLists reads the key hashes and restrictions of a tenant
+func readTenantLists(ctx context.Context, db sqlExecer, tenant *models.Tenant) error {
+	for _, list := range tenantLists {
+		rows, err := db.QueryContext(ctx, fmt.Sprintf("SELECT %s FROM %s WHERE tenant_id = ? ORDER BY position", list.column, list.table), tenant.ID)
+		if err != nil {
+			return fmt.Errorf("failed to read tenant %s: %w", list.column, err)
+		}
+
+		var values []string
+		for rows.Next() {
+			var value string
+			if err := rows.Scan(&value); err != nil {
+				rows.Close()
+				return fmt.Errorf("failed to scan tenant %s: %w", list.column, err)
+			}
+			values = append(values, value)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read tenant %s: %w", list.column, err)
+		}
+		*list.values(tenant) = values
+	}
+	return nil
+}
+
+// writeTenantLists replaces the key hashes and restrictions of a tenant
+func writeTenantLists(ctx context.Context, db sqlExecer, tenant *models.Tenant) error {
+	for _, list := range tenantLists {
+		if _, err := db.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE tenant_id = ?", list.table), tenant.ID); err != nil {
+			return fmt.Errorf("failed to update tenant %s: %w", list.column, err)
+		}
+		for i, value := range *list.values(tenant) {
+			_, err := db.ExecContext(ctx, fmt.Sprintf("INSERT INTO %s (tenant_id, position, %s) VALUES (?, ?, ?)", list.table, list.column),
+				tenant.ID, i, value)
+			if err != nil {
+				return fmt.Errorf("failed to save tenant %s: %w", list.column, err)
+			}
+		}
+	}
+	return nil
+}
+
+// migrateTenantLists moves the lists of databases created before they had
+// tables of their own out of the comma-separated tenants columns
+func migrateTenantLists(db *sql.DB) error {
+	ctx := context.Background()
+	for _, list := range tenantLists {
+		var exists int
+		if err := db.QueryRow("SELECT COUNT(*) FROM pragma_table_info('tenants') WHERE name = ?", list.legacyColumn).Scan(&exists); err != nil {
+			return err
+		}
+		if exists == 0 {
+			continue
+		}
+
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		rows, err := tx.QueryContext(ctx, fmt.Sprintf("SELECT id, %s FROM tenants WHERE %s != ''", list.legacyColumn, list.legacyColumn))
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		legacy := make(map[string]string)
+		for rows.Next() {
+			var id, value string
+			if err := rows.Scan(&id, &value); err != nil {
+				rows.Close()
+				tx.Rollback()
+				return err
+			}
+			legacy[id] = value
+		}
+		rows.Close()
+
+		for id, value := range legacy {
+			for i, item := range strings.Split(value, ",") {
+				_, err := tx.ExecContext(ctx, fmt.Sprintf("INSERT OR IGNORE INTO %s (tenant_id, position, %s) VALUES (?, ?, ?)", list.table, list.column), id, i, item)
+				if err != nil {
+					tx.Rollback()
+					return err
+				}
+			}
+		}
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf("ALTER TABLE tenants DROP COLUMN %s", list.legacyColumn)); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
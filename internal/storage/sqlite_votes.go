@@ -0,0 +1,171 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"translator-service/internal/models"
+)
+
+const voteSchema = `
+CREATE TABLE IF NOT EXISTS comparison_votes (
+	id            INTEGER PRIMARY KEY AUTOINCREMENT,
+	comparison_id TEXT    NOT NULL,
+	original      TEXT    NOT NULL,
+	model         TEXT    NOT NULL,
+	candidates    TEXT    NOT NULL,
+	voter         TEXT    NOT NULL DEFAULT '',
+	tenant        TEXT    NOT NULL DEFAULT '',
+	created_at    INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_comparison_votes_model ON comparison_votes (model);
+CREATE INDEX IF NOT EXISTS idx_comparison_votes_comparison_voter ON comparison_votes (comparison_id, voter);
+
+CREATE TABLE IF NOT EXISTS comparisons (
+	id         TEXT    PRIMARY KEY,
+	tenant     TEXT    NOT NULL DEFAULT '',
+	original   TEXT    NOT NULL,
+	created_at INTEGER NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS comparison_results (
+	comparison_id TEXT    NOT NULL REFERENCES comparisons (id),
+	position      INTEGER NOT NULL,
+	model         TEXT    NOT NULL,
+	translation   TEXT    NOT NULL DEFAULT '',
+	error         TEXT    NOT NULL DEFAULT '',
+	PRIMARY KEY (comparison_id, position)
+);
+`
+
+// SQLiteVoteStore persists comparisons and their votes in a SQLite database file
+type SQLiteVoteStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteVoteStore opens (or creates) a SQLite vote database at the given path
+func NewSQLiteVoteStore(path string) (*SQLiteVoteStore, error) {
+	db, err := openSQLite(path, voteSchema, sqliteColumn{"comparison_votes", "tenant", "TEXT NOT NULL DEFAULT ''"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open vote database: %w", err)
+	}
+
+	return &SQLiteVoteStore{db: db}, nil
+}
+
+// SaveComparison stores a comparison and its results in one transaction
+func (s *SQLiteVoteStore) SaveComparison(ctx context.Context, comparison *models.Comparison) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin comparison transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO comparisons (id, tenant, original, created_at) VALUES (?, ?, ?, ?)`,
+		comparison.ID, comparison.Tenant, comparison.Original, comparison.CreatedAt.UnixNano()); err != nil {
+		return fmt.Errorf("failed to save comparison: %w", err)
+	}
+	for i, result := range comparison.Results {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO comparison_results (comparison_id, position, model, translation, error) VALUES (?, ?, ?, ?, ?)`,
+			comparison.ID, i, result.Model, result.Translation, result.Error); err != nil {
+			return fmt.Errorf("failed to save comparison result: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetComparison returns a tenant's comparison with the model, translation and error of each result
+func (s *SQLiteVoteStore) GetComparison(ctx context.Context, tenant, id string) (*models.Comparison, error) {
+	comparison := &models.Comparison{ID: id, Tenant: tenant}
+	var createdAt int64
+	err := s.db.QueryRowContext(ctx, `SELECT original, created_at FROM comparisons WHERE id = ? AND tenant = ?`, id, tenant).
+		Scan(&comparison.Original, &createdAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrComparisonNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read comparison: %w", err)
+	}
+	comparison.CreatedAt = time.Unix(0, createdAt).UTC()
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT model, translation, error FROM comparison_results WHERE comparison_id = ? ORDER BY position`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query comparison results: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var result models.ComparisonResult
+		if err := rows.Scan(&result.Model, &result.Translation, &result.Error); err != nil {
+			return nil, fmt.Errorf("failed to scan comparison result: %w", err)
+		}
+		comparison.Results = append(comparison.Results, result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read comparison results: %w", err)
+	}
+
+	return comparison, nil
+}
+
+// SaveVote stores a vote unless the voter has already voted on the comparison.
+// The check and the insert are one statement so concurrent votes cannot both pass.
+func (s *SQLiteVoteStore) SaveVote(ctx context.Context, vote *models.Vote) error {
+	result, err := s.db.ExecContext(ctx,
+		`INSERT INTO comparison_votes (comparison_id, original, model, candidates, voter, tenant, created_at)
+		SELECT ?, ?, ?, ?, ?, ?, ?
+		WHERE NOT EXISTS (SELECT 1 FROM comparison_votes WHERE comparison_id = ? AND tenant = ? AND voter = ?)`,
+		vote.ComparisonID, vote.Original, vote.Model, strings.Join(vote.Candidates, ","), vote.Voter, vote.Tenant, vote.CreatedAt.UnixNano(),
+		vote.ComparisonID, vote.Tenant, vote.Voter)
+	if err != nil {
+		return fmt.Errorf("failed to save vote: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return ErrDuplicateVote
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to read vote id: %w", err)
+	}
+	vote.ID = id
+
+	return nil
+}
+
+// Tally returns the number of votes each model has received from a tenant
+func (s *SQLiteVoteStore) Tally(ctx context.Context, tenant string) (map[string]int, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT model, COUNT(*) FROM comparison_votes WHERE tenant = ? GROUP BY model`, tenant)
+	if err != nil {
+		return nil, fmt.Errorf("failed to tally votes: %w", err)
+	}
+	defer rows.Close()
+
+	tally := make(map[string]int)
+	for rows.Next() {
+		var model string
+		var count int
+		if err := rows.Scan(&model, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan vote tally: %w", err)
+		}
+		tally[model] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read vote tally: %w", err)
+	}
+
+	return tally, nil
+}
+
+// Close closes the underlying database
+func (s *SQLiteVoteStore) Close() error {
+	return s.db.Close()
+}
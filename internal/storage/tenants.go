@@ -0,0 +1,50 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"translator-service/internal/models"
+)
+
+var (
+	// ErrTenantNotFound is returned when a tenant does not exist
+	ErrTenantNotFound = errors.New("tenant not found")
+
+	// ErrTenantExists is returned when a tenant is created with an ID already in use
+	ErrTenantExists = errors.New("tenant already exists")
+)
+
+// TenantStore persists the tenants created through the admin API
+type TenantStore interface {
+	// CreateTenant persists a new tenant
+	CreateTenant(ctx context.Context, tenant *models.Tenant) error
+
+	// UpdateTenant persists a tenant's new settings
+	UpdateTenant(ctx context.Context, tenant *models.Tenant) error
+
+	// GetTenant returns a tenant by ID
+	GetTenant(ctx context.Context, id string) (*models.Tenant, error)
+
+	// FindTenantByAPIKey returns the tenant with the given API key hash, or nil if there is none
+	FindTenantByAPIKey(ctx context.Context, hash string) (*models.Tenant, error)
+
+	// ListTenants returns all tenants ordered by ID
+	ListTenants(ctx context.Context) ([]models.Tenant, error)
+
+	// Close releases any resources held by the store
+	Close() error
+}
+
+// NewTenantStore creates a tenant store of the given kind ("memory" or "sqlite")
+func NewTenantStore(kind, path string) (TenantStore, error) {
+	switch kind {
+	case "", "memory":
+		return NewMemoryTenantStore(), nil
+	case "sqlite":
+		return NewSQLiteTenantStore(path)
+	default:
+		return nil, fmt.Errorf("unknown tenant store: %s", kind)
+	}
+}
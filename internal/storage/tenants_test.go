@@ -0,0 +1,135 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"translator-service/internal/models"
+)
+
+func testTenantStore(t *testing.T, store TenantStore) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tenants := []models.Tenant{
+		{ID: "globex", APIKeyHashes: []string{"hash-globex"}, CreatedAt: now, UpdatedAt: now},
+		{ID: "acme", APIKeyHashes: []string{"hash-acme-1", "hash-acme-2"}, OpenAIKey: "sk-acme", DefaultModel: "gpt-4",
+			TargetLanguage: models.LanguageTraditionalChinese, AllowedProviders: []string{"openai", "azure"}, CreatedAt: now, UpdatedAt: now},
+	}
+	for i := range tenants {
+		if err := store.CreateTenant(ctx, &tenants[i]); err != nil {
+			t.Fatalf("Failed to create tenant: %v", err)
+		}
+	}
+	if err := store.CreateTenant(ctx, &tenants[0]); !errors.Is(err, ErrTenantExists) {
+		t.Errorf("Expected ErrTenantExists, got %v", err)
+	}
+
+	tenant, err := store.GetTenant(ctx, "acme")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if tenant.OpenAIKey != "sk-acme" || tenant.TargetLanguage != models.LanguageTraditionalChinese ||
+		len(tenant.AllowedProviders) != 2 || len(tenant.APIKeyHashes) != 2 || !tenant.CreatedAt.Equal(now) {
+		t.Errorf("Unexpected tenant: %+v", tenant)
+	}
+	if _, err := store.GetTenant(ctx, "initech"); !errors.Is(err, ErrTenantNotFound) {
+		t.Errorf("Expected ErrTenantNotFound, got %v", err)
+	}
+
+	// Any of a tenant's key hashes finds it, but not part of one
+	if found, err := store.FindTenantByAPIKey(ctx, "hash-acme-2"); err != nil || found == nil || found.ID != "acme" {
+		t.Errorf("Expected acme for its second key, got %+v (%v)", found, err)
+	}
+	if found, err := store.FindTenantByAPIKey(ctx, "hash-acme"); err != nil || found != nil {
+		t.Errorf("Expected no tenant for a partial key, got %+v (%v)", found, err)
+	}
+
+	tenant.Disabled = true
+	tenant.APIKeyHashes = []string{"hash-acme-3"}
+	tenant.UpdatedAt = now.Add(time.Hour)
+	if err := store.UpdateTenant(ctx, tenant); err != nil {
+		t.Fatalf("Failed to update tenant: %v", err)
+	}
+
+	// Replaced keys no longer find the tenant
+	if found, err := store.FindTenantByAPIKey(ctx, "hash-acme-1"); err != nil || found != nil {
+		t.Errorf("Expected no tenant for a replaced key, got %+v (%v)", found, err)
+	}
+	if found, err := store.FindTenantByAPIKey(ctx, "hash-acme-3"); err != nil || found == nil || !found.Disabled {
+		t.Errorf("Expected updated acme for its new key, got %+v (%v)", found, err)
+	}
+	if err := store.UpdateTenant(ctx, &models.Tenant{ID: "initech"}); !errors.Is(err, ErrTenantNotFound) {
+		t.Errorf("Expected ErrTenantNotFound on update, got %v", err)
+	}
+
+	list, err := store.ListTenants(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(list) != 2 || list[0].ID != "acme" || !list[0].Disabled || list[1].ID != "globex" {
+		t.Errorf("Unexpected tenant list: %+v", list)
+	}
+}
+
+func TestMemoryTenantStore(t *testing.T) {
+	testTenantStore(t, NewMemoryTenantStore())
+}
+
+func TestSQLiteTenantStore(t *testing.T) {
+	store, err := NewSQLiteTenantStore(filepath.Join(t.TempDir(), "translator.db"))
+	if err != nil {
+		t.Fatalf("Failed to create SQLite tenant store: %v", err)
+	}
+	defer store.Close()
+
+	testTenantStore(t, store)
+}
+
+func TestSQLiteTenantStore_MigratesListColumns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "translator.db")
+
+	// A database created while the lists were kept in comma-separated columns
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	_, err = db.Exec(`CREATE TABLE tenants (
+		id                TEXT    PRIMARY KEY,
+		api_key_hashes    TEXT    NOT NULL DEFAULT '',
+		openai_key        TEXT    NOT NULL DEFAULT '',
+		anthropic_key     TEXT    NOT NULL DEFAULT '',
+		default_model     TEXT    NOT NULL DEFAULT '',
+		target_language   TEXT    NOT NULL DEFAULT '',
+		allowed_providers TEXT    NOT NULL DEFAULT '',
+		allowed_endpoints TEXT    NOT NULL DEFAULT '',
+		disabled          INTEGER NOT NULL DEFAULT 0,
+		created_at        INTEGER NOT NULL,
+		updated_at        INTEGER NOT NULL
+	);
+	INSERT INTO tenants (id, api_key_hashes, allowed_providers, created_at, updated_at)
+		VALUES ('acme', 'hash-acme-1,hash-acme-2', 'openai,azure', 1, 1);`)
+	db.Close()
+	if err != nil {
+		t.Fatalf("Failed to create old schema: %v", err)
+	}
+
+	store, err := NewSQLiteTenantStore(path)
+	if err != nil {
+		t.Fatalf("Failed to open old database: %v", err)
+	}
+	defer store.Close()
+
+	tenant, err := store.FindTenantByAPIKey(context.Background(), "hash-acme-2")
+	if err != nil || tenant == nil {
+		t.Fatalf("Expected acme for its second key, got %+v (%v)", tenant, err)
+	}
+	if len(tenant.APIKeyHashes) != 2 || len(tenant.AllowedProviders) != 2 || tenant.AllowedProviders[1] != "azure" ||
+		len(tenant.AllowedEndpoints) != 0 {
+		t.Errorf("Unexpected migrated tenant: %+v", tenant)
+	}
+}
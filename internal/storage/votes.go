@@ -0,0 +1,45 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"translator-service/internal/models"
+)
+
+// ErrComparisonNotFound is returned when a tenant has no comparison with the given ID
+var ErrComparisonNotFound = errors.New("comparison not found")
+
+// ErrDuplicateVote is returned when a voter has already voted on a comparison
+var ErrDuplicateVote = errors.New("vote already recorded for this comparison")
+
+// VoteStore persists model comparisons and the reviewer votes cast on them
+type VoteStore interface {
+	// SaveComparison persists a comparison so votes on it can be checked
+	SaveComparison(ctx context.Context, comparison *models.Comparison) error
+
+	// GetComparison returns a tenant's comparison with its results
+	GetComparison(ctx context.Context, tenant, id string) (*models.Comparison, error)
+
+	// SaveVote persists a vote, assigning its ID. Each voter may vote once per comparison.
+	SaveVote(ctx context.Context, vote *models.Vote) error
+
+	// Tally returns the number of votes each model has received from a tenant
+	Tally(ctx context.Context, tenant string) (map[string]int, error)
+
+	// Close releases any resources held by the store
+	Close() error
+}
+
+// NewVoteStore creates a vote store of the given kind ("memory" or "sqlite")
+func NewVoteStore(kind, path string) (VoteStore, error) {
+	switch kind {
+	case "", "memory":
+		return NewMemoryVoteStore(), nil
+	case "sqlite":
+		return NewSQLiteVoteStore(path)
+	default:
+		return nil, fmt.Errorf("unknown vote store: %s", kind)
+	}
+}
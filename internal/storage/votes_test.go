@@ -0,0 +1,112 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"translator-service/internal/models"
+)
+
+func testVoteStore(t *testing.T, store VoteStore) {
+	votes := []models.Vote{
+		{ComparisonID: "c1", Model: "gpt-4", Candidates: []string{"gpt-4", "claude"}},
+		{ComparisonID: "c2", Model: "claude", Candidates: []string{"gpt-4", "claude"}},
+		{ComparisonID: "c3", Model: "gpt-4", Candidates: []string{"gpt-4", "llama"}},
+	}
+	for i := range votes {
+		if err := store.SaveVote(context.Background(), &votes[i]); err != nil {
+			t.Fatalf("Failed to save vote: %v", err)
+		}
+	}
+
+	tally, err := store.Tally(context.Background(), "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if tally["gpt-4"] != 2 || tally["claude"] != 1 {
+		t.Errorf("Unexpected vote tally: %v", tally)
+	}
+
+	vote := models.Vote{ComparisonID: "c4", Model: "claude", Candidates: []string{"gpt-4", "claude"}, Tenant: "acme"}
+	if err := store.SaveVote(context.Background(), &vote); err != nil {
+		t.Fatalf("Failed to save vote: %v", err)
+	}
+
+	tally, err = store.Tally(context.Background(), "acme")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(tally) != 1 || tally["claude"] != 1 {
+		t.Errorf("Expected only the tenant's votes, got %v", tally)
+	}
+
+	// A voter may vote once per comparison
+	vote = models.Vote{ComparisonID: "c4", Model: "gpt-4", Candidates: []string{"gpt-4", "claude"}, Tenant: "acme"}
+	if err := store.SaveVote(context.Background(), &vote); !errors.Is(err, ErrDuplicateVote) {
+		t.Errorf("Expected ErrDuplicateVote, got %v", err)
+	}
+	vote.Voter = "203.0.113.7"
+	if err := store.SaveVote(context.Background(), &vote); err != nil {
+		t.Errorf("Expected another voter's vote to be saved, got %v", err)
+	}
+}
+
+func testComparisonStore(t *testing.T, store VoteStore) {
+	comparison := &models.Comparison{
+		ID:       "c1",
+		Tenant:   "acme",
+		Original: "Hello",
+		Results: []models.ComparisonResult{
+			{Model: "gpt-4", Translation: "你好"},
+			{Model: "claude", Error: "context deadline exceeded"},
+		},
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := store.SaveComparison(context.Background(), comparison); err != nil {
+		t.Fatalf("Failed to save comparison: %v", err)
+	}
+
+	stored, err := store.GetComparison(context.Background(), "acme", "c1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if stored.Original != "Hello" || len(stored.Results) != 2 || stored.Results[0].Translation != "你好" || stored.Results[1].Error == "" {
+		t.Errorf("Unexpected comparison: %+v", stored)
+	}
+
+	// Comparisons are scoped to their tenant
+	if _, err := store.GetComparison(context.Background(), "", "c1"); !errors.Is(err, ErrComparisonNotFound) {
+		t.Errorf("Expected ErrComparisonNotFound for another tenant, got %v", err)
+	}
+	if _, err := store.GetComparison(context.Background(), "acme", "c2"); !errors.Is(err, ErrComparisonNotFound) {
+		t.Errorf("Expected ErrComparisonNotFound for an unknown ID, got %v", err)
+	}
+}
+
+func TestMemoryVoteStore(t *testing.T) {
+	testVoteStore(t, NewMemoryVoteStore())
+	testComparisonStore(t, NewMemoryVoteStore())
+}
+
+func TestSQLiteVoteStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "translator.db")
+
+	// Votes share the database file with history
+	history, err := NewSQLiteHistoryStore(path)
+	if err != nil {
+		t.Fatalf("Failed to create SQLite history store: %v", err)
+	}
+	defer history.Close()
+
+	store, err := NewSQLiteVoteStore(path)
+	if err != nil {
+		t.Fatalf("Failed to create SQLite vote store: %v", err)
+	}
+	defer store.Close()
+
+	testVoteStore(t, store)
+	testComparisonStore(t, store)
+}